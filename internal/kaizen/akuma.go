@@ -0,0 +1,238 @@
+package kaizen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// AkumaQueryResponse is the /v1/akuma/query response. Columns and Rows are
+// set in sql-and-results mode; Rows holds one array per row.
+type AkumaQueryResponse struct {
+	Response
+	QueryID string        `json:"queryId,omitempty"`
+	SQL     string        `json:"sql"`
+	Columns []string      `json:"columns,omitempty"`
+	Rows    []interface{} `json:"rows,omitempty"`
+}
+
+// AkumaInteractiveResponse is the /v1/akuma/queries/interactive envelope.
+// Result is kept raw: its shape depends on Status, and a malformed one is
+// reported by the caller rather than failing the decode.
+type AkumaInteractiveResponse struct {
+	Response
+	Status string          `json:"status"`
+	SQL    string          `json:"sql,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// AkumaInteractiveResult is the result of a completed or rejected
+// interactive query.
+type AkumaInteractiveResult struct {
+	Error   string        `json:"error,omitempty"`
+	Columns []string      `json:"columns,omitempty"`
+	Rows    []interface{} `json:"rows,omitempty"`
+}
+
+// AkumaExplainResponse is the /v1/akuma/explain response.
+type AkumaExplainResponse struct {
+	Response
+	Explanation string                 `json:"explanation"`
+	Plan        map[string]interface{} `json:"plan,omitempty"`
+}
+
+// AkumaSchemaResponse describes a registered schema version, as returned
+// when registering, reading, or introspecting one.
+type AkumaSchemaResponse struct {
+	Response
+	SourceID string `json:"sourceId"`
+	Version  string `json:"version"`
+	Dialect  string `json:"dialect,omitempty"`
+	// Tables is a count after registration and the table list otherwise.
+	Tables interface{} `json:"tables,omitempty"`
+}
+
+// AkumaDiagnostic is one finding about a SQL statement.
+type AkumaDiagnostic struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// AkumaValidateResponse is the /v1/akuma/validate response.
+type AkumaValidateResponse struct {
+	Response
+	Valid       bool              `json:"valid"`
+	Diagnostics []AkumaDiagnostic `json:"diagnostics"`
+}
+
+// AkumaOptimizeResponse is the /v1/akuma/optimize response.
+type AkumaOptimizeResponse struct {
+	Response
+	SQL                  string                   `json:"sql"`
+	Rewrites             []map[string]interface{} `json:"rewrites,omitempty"`
+	EstimatedImprovement map[string]interface{}   `json:"estimatedImprovement,omitempty"`
+}
+
+// AkumaLintResponse is the /v1/akuma/lint response.
+type AkumaLintResponse struct {
+	Response
+	Findings []AkumaDiagnostic `json:"findings"`
+}
+
+// AkumaCostResponse is the /v1/akuma/cost response.
+type AkumaCostResponse struct {
+	Response
+	BytesScanned     float64 `json:"bytesScanned,omitempty"`
+	EstimatedCostUSD float64 `json:"estimatedCostUSD"`
+	Method           string  `json:"method,omitempty"`
+}
+
+// AkumaHistoryResponse is one page of /v1/akuma/history.
+type AkumaHistoryResponse struct {
+	Response
+	Items []struct {
+		ID     string `json:"id"`
+		SQL    string `json:"sql"`
+		Status string `json:"status"`
+	} `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// AkumaDiffResponse is the /v1/akuma/diff response: the structural changes
+// between two queries, by clause.
+type AkumaDiffResponse struct {
+	Response
+	Filters     map[string]interface{} `json:"filters"`
+	Joins       map[string]interface{} `json:"joins"`
+	Projections map[string]interface{} `json:"projections"`
+}
+
+// AkumaPreviewResponse is the /v1/akuma/preview response.
+type AkumaPreviewResponse struct {
+	Response
+	Columns []string      `json:"columns"`
+	Rows    []interface{} `json:"rows"`
+	Masked  []string      `json:"masked,omitempty"`
+	RowCap  int           `json:"rowCap,omitempty"`
+}
+
+// AkumaConvertResponse is the /v1/akuma/convert response.
+type AkumaConvertResponse struct {
+	Response
+	SQL   string        `json:"sql"`
+	Notes []interface{} `json:"notes,omitempty"`
+}
+
+// AkumaFeedbackResponse is the /v1/akuma/feedback response.
+type AkumaFeedbackResponse struct {
+	Response
+	ID       string `json:"id"`
+	QueryID  string `json:"queryId"`
+	Recorded bool   `json:"recorded"`
+}
+
+// AkumaGlossaryResponse is the /v1/akuma/glossary response.
+type AkumaGlossaryResponse struct {
+	Response
+	Terms []struct {
+		Term       string   `json:"term"`
+		Definition string   `json:"definition"`
+		Tables     []string `json:"tables,omitempty"`
+		Columns    []string `json:"columns,omitempty"`
+		Filter     string   `json:"filter,omitempty"`
+	} `json:"terms"`
+}
+
+// AkumaQuery generates SQL for a prompt and, in sql-and-results mode,
+// runs it.
+func (c *Client) AkumaQuery(ctx context.Context, req AkumaQueryRequest) (*AkumaQueryResponse, error) {
+	return send[AkumaQueryResponse](ctx, c, http.MethodPost, "/v1/akuma/query", req)
+}
+
+// AkumaQueryInteractive runs a query through the interactive endpoint,
+// which reports rejections in a 200 envelope.
+func (c *Client) AkumaQueryInteractive(ctx context.Context, req AkumaQueryRequest) (*AkumaInteractiveResponse, error) {
+	return send[AkumaInteractiveResponse](ctx, c, http.MethodPost, "/v1/akuma/queries/interactive", req)
+}
+
+// AkumaExplain explains a SQL statement in prose.
+func (c *Client) AkumaExplain(ctx context.Context, req AkumaExplainRequest) (*AkumaExplainResponse, error) {
+	return send[AkumaExplainResponse](ctx, c, http.MethodPost, "/v1/akuma/explain", req)
+}
+
+// AkumaRegisterSchema registers tables for a source. The payload keeps
+// only the arguments the caller set.
+func (c *Client) AkumaRegisterSchema(ctx context.Context, payload map[string]interface{}) (*AkumaSchemaResponse, error) {
+	return send[AkumaSchemaResponse](ctx, c, http.MethodPost, "/v1/akuma/schema", payload)
+}
+
+// AkumaSchema reads the registered schema of a source, or of the default
+// source when sourceID is empty.
+func (c *Client) AkumaSchema(ctx context.Context, sourceID string) (*AkumaSchemaResponse, error) {
+	query := url.Values{}
+	if sourceID != "" {
+		query.Set("sourceId", sourceID)
+	}
+	return get[AkumaSchemaResponse](ctx, c, "/v1/akuma/schema", query)
+}
+
+// AkumaIntrospect registers a schema read from a server-side connection.
+func (c *Client) AkumaIntrospect(ctx context.Context, req AkumaIntrospectRequest) (*AkumaSchemaResponse, error) {
+	return send[AkumaSchemaResponse](ctx, c, http.MethodPost, "/v1/akuma/schema/introspect", req)
+}
+
+// AkumaValidate checks a SQL statement against the registered schema.
+func (c *Client) AkumaValidate(ctx context.Context, req AkumaSQLRequest) (*AkumaValidateResponse, error) {
+	return send[AkumaValidateResponse](ctx, c, http.MethodPost, "/v1/akuma/validate", req)
+}
+
+// AkumaOptimize rewrites a SQL statement for performance.
+func (c *Client) AkumaOptimize(ctx context.Context, req AkumaSQLRequest) (*AkumaOptimizeResponse, error) {
+	return send[AkumaOptimizeResponse](ctx, c, http.MethodPost, "/v1/akuma/optimize", req)
+}
+
+// AkumaLint reports style and safety findings for a SQL statement.
+func (c *Client) AkumaLint(ctx context.Context, req AkumaSQLRequest) (*AkumaLintResponse, error) {
+	return send[AkumaLintResponse](ctx, c, http.MethodPost, "/v1/akuma/lint", req)
+}
+
+// AkumaCost estimates what running a SQL statement would cost.
+func (c *Client) AkumaCost(ctx context.Context, req AkumaSQLRequest) (*AkumaCostResponse, error) {
+	return send[AkumaCostResponse](ctx, c, http.MethodPost, "/v1/akuma/cost", req)
+}
+
+// AkumaHistory lists past queries matching query (status, since, until,
+// limit, cursor).
+func (c *Client) AkumaHistory(ctx context.Context, query url.Values) (*AkumaHistoryResponse, error) {
+	return get[AkumaHistoryResponse](ctx, c, "/v1/akuma/history", query)
+}
+
+// AkumaDiff compares two versions of a query.
+func (c *Client) AkumaDiff(ctx context.Context, req AkumaDiffRequest) (*AkumaDiffResponse, error) {
+	return send[AkumaDiffResponse](ctx, c, http.MethodPost, "/v1/akuma/diff", req)
+}
+
+// AkumaPreview returns a few masked rows of a table.
+func (c *Client) AkumaPreview(ctx context.Context, req AkumaPreviewRequest) (*AkumaPreviewResponse, error) {
+	return send[AkumaPreviewResponse](ctx, c, http.MethodPost, "/v1/akuma/preview", req)
+}
+
+// AkumaConvert translates SQL between dialects.
+func (c *Client) AkumaConvert(ctx context.Context, req AkumaConvertRequest) (*AkumaConvertResponse, error) {
+	return send[AkumaConvertResponse](ctx, c, http.MethodPost, "/v1/akuma/convert", req)
+}
+
+// AkumaFeedback records a rating or correction for generated SQL.
+func (c *Client) AkumaFeedback(ctx context.Context, req AkumaFeedbackRequest) (*AkumaFeedbackResponse, error) {
+	return send[AkumaFeedbackResponse](ctx, c, http.MethodPost, "/v1/akuma/feedback", req)
+}
+
+// AkumaGlossary resolves business terms matching query (term, sourceId).
+func (c *Client) AkumaGlossary(ctx context.Context, query url.Values) (*AkumaGlossaryResponse, error) {
+	return get[AkumaGlossaryResponse](ctx, c, "/v1/akuma/glossary", query)
+}
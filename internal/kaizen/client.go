@@ -0,0 +1,116 @@
+// Package kaizen is a typed client for the Kaizen API: one method per
+// endpoint, taking a request struct and returning a response struct, so
+// callers read fields instead of asserting types out of decoded maps.
+//
+// The client does not speak HTTP itself. A Transport performs each request
+// and returns the body of a successful response, which keeps credentials,
+// retries, failover, and tracing in the transport's hands.
+package kaizen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Transport sends one request with payload as its JSON body (nil for
+// none) and returns the response body. Non-2xx responses must be
+// returned as errors; the client passes them through unchanged.
+type Transport interface {
+	Do(ctx context.Context, method, path string, payload interface{}) ([]byte, error)
+}
+
+// TransportFunc adapts a function to Transport.
+type TransportFunc func(ctx context.Context, method, path string, payload interface{}) ([]byte, error)
+
+// Do calls f.
+func (f TransportFunc) Do(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	return f(ctx, method, path, payload)
+}
+
+// Client calls Kaizen API endpoints through a Transport.
+type Client struct {
+	transport Transport
+}
+
+// New returns a Client that sends its requests through transport.
+func New(transport Transport) *Client {
+	return &Client{transport: transport}
+}
+
+// Response is embedded in every response struct. It keeps the body as a
+// whole, so fields the struct does not model, such as ones added by a
+// newer backend, still reach callers that pass results on.
+type Response struct {
+	body   []byte
+	fields map[string]interface{}
+}
+
+// Body returns the response body as the backend sent it, for callers that
+// need values exactly as encoded, such as integers beyond float64
+// precision.
+func (r *Response) Body() []byte { return r.body }
+
+// Fields returns the decoded response body. It is never nil.
+func (r *Response) Fields() map[string]interface{} {
+	if r.fields == nil {
+		r.fields = map[string]interface{}{}
+	}
+	return r.fields
+}
+
+func (r *Response) setBody(body []byte, fields map[string]interface{}) {
+	r.body, r.fields = body, fields
+}
+
+type response interface {
+	setBody([]byte, map[string]interface{})
+}
+
+// Do calls any endpoint and returns its body untyped, for endpoints the
+// client has no method for, such as ones declared in a config file.
+func (c *Client) Do(ctx context.Context, method, path string, payload interface{}) (*Response, error) {
+	return send[Response](ctx, c, method, path, payload)
+}
+
+// send performs a request and decodes the response both into T and, for
+// Fields, into a map. An empty body decodes as an empty object.
+func send[T any, PT interface {
+	*T
+	response
+}](ctx context.Context, c *Client, method, path string, payload interface{}) (*T, error) {
+	body, err := c.transport.Do(ctx, method, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	out := PT(new(T))
+	fields := map[string]interface{}{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if err := json.Unmarshal(body, out); err != nil {
+			return nil, fmt.Errorf("unexpected response from %s %s: %w", method, withoutQuery(path), err)
+		}
+	}
+	out.setBody(body, fields)
+	return out, nil
+}
+
+func get[T any, PT interface {
+	*T
+	response
+}](ctx context.Context, c *Client, path string, query url.Values) (*T, error) {
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	return send[T, PT](ctx, c, http.MethodGet, path, nil)
+}
+
+func withoutQuery(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	return path
+}
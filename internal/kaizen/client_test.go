@@ -0,0 +1,111 @@
+package kaizen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type recordedRequest struct {
+	method  string
+	path    string
+	payload interface{}
+}
+
+func newTestClient(body string, err error, got *recordedRequest) *Client {
+	return New(TransportFunc(func(_ context.Context, method, path string, payload interface{}) ([]byte, error) {
+		*got = recordedRequest{method: method, path: path, payload: payload}
+		if err != nil {
+			return nil, err
+		}
+		return []byte(body), nil
+	}))
+}
+
+func TestClientDecodesTypedFieldsAndKeepsTheRest(t *testing.T) {
+	var got recordedRequest
+	client := newTestClient(`{"window":"7d","totalCostUSD":12.5,"groups":[{"key":"gpt-4o","costUSD":10}],"currency":"USD"}`, nil, &got)
+
+	resp, err := client.EnzanSummary(context.Background(), EnzanSummaryRequest{Window: "7d"})
+	if err != nil {
+		t.Fatalf("EnzanSummary: %v", err)
+	}
+	if got.method != http.MethodPost || got.path != "/v1/enzan/summary" {
+		t.Fatalf("request = %s %s", got.method, got.path)
+	}
+	if resp.TotalCostUSD != 12.5 || len(resp.Groups) != 1 || resp.Groups[0].Key != "gpt-4o" {
+		t.Fatalf("typed fields = %+v", resp)
+	}
+	if resp.Fields()["currency"] != "USD" {
+		t.Fatalf("fields lost an unmodelled key: %v", resp.Fields())
+	}
+}
+
+func TestClientEncodesQueryAndEscapesPath(t *testing.T) {
+	var got recordedRequest
+	client := newTestClient(`{"jobId":"job 1","records":[]}`, nil, &got)
+
+	if _, err := client.SozoJobResult(context.Background(), "job 1", url.Values{"limit": {"10"}}); err != nil {
+		t.Fatalf("SozoJobResult: %v", err)
+	}
+	if got.method != http.MethodGet || got.path != "/v1/sozo/jobs/job%201/result?limit=10" {
+		t.Fatalf("request = %s %s", got.method, got.path)
+	}
+}
+
+func TestClientPassesTransportErrorsThrough(t *testing.T) {
+	var got recordedRequest
+	want := errors.New("boom (status=503)")
+	client := newTestClient("", want, &got)
+
+	if _, err := client.EnzanBurn(context.Background()); err != want {
+		t.Fatalf("err = %v, want the transport's error", err)
+	}
+}
+
+func TestClientEmptyBodyDecodesAsEmptyObject(t *testing.T) {
+	var got recordedRequest
+	client := newTestClient("", nil, &got)
+
+	resp, err := client.EnzanDeleteAlert(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("EnzanDeleteAlert: %v", err)
+	}
+	if resp.Deleted || resp.Fields() == nil || len(resp.Fields()) != 0 {
+		t.Fatalf("resp = %+v, fields = %v", resp, resp.Fields())
+	}
+}
+
+func TestClientReportsResponsesOfTheWrongShape(t *testing.T) {
+	var got recordedRequest
+	client := newTestClient(`{"hourlyUSD":"lots"}`, nil, &got)
+
+	_, err := client.EnzanBurn(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unexpected response from GET /v1/enzan/burn") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestScopesDecodeFromStringOrArray(t *testing.T) {
+	var got recordedRequest
+	for body, want := range map[string]int{
+		`{"scopes":"akuma:read enzan:read"}`: 2,
+		`{"scopes":["akuma:read"]}`:          1,
+		`{"scopes":[]}`:                      0,
+	} {
+		identity, err := newTestClient(body, nil, &got).WhoAmI(context.Background())
+		if err != nil {
+			t.Fatalf("WhoAmI(%s): %v", body, err)
+		}
+		if identity.Scopes == nil || len(identity.Scopes) != want {
+			t.Fatalf("WhoAmI(%s).Scopes = %#v, want %d", body, identity.Scopes, want)
+		}
+	}
+	identity, err := newTestClient(`{"scopes":null}`, nil, &got).WhoAmI(context.Background())
+	if err != nil || identity.Scopes != nil {
+		t.Fatalf("null scopes = %#v, %v; want nil", identity.Scopes, err)
+	}
+}
@@ -0,0 +1,532 @@
+package kaizen
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// EnzanCostGroup is the cost of one group in a summary or breakdown.
+type EnzanCostGroup struct {
+	Key     string  `json:"key"`
+	CostUSD float64 `json:"costUSD"`
+}
+
+// EnzanSummaryResponse is the /v1/enzan/summary response.
+type EnzanSummaryResponse struct {
+	Response
+	Window       string           `json:"window"`
+	TotalCostUSD float64          `json:"totalCostUSD"`
+	Groups       []EnzanCostGroup `json:"groups,omitempty"`
+}
+
+// EnzanCostsByModelResponse is the /v1/enzan/costs/by-model response.
+type EnzanCostsByModelResponse struct {
+	Response
+	Window string `json:"window"`
+	Models []struct {
+		Provider string  `json:"provider"`
+		Model    string  `json:"model"`
+		CostUSD  float64 `json:"costUSD"`
+	} `json:"models"`
+}
+
+// EnzanOptimizeResponse is the /v1/enzan/optimize response.
+type EnzanOptimizeResponse struct {
+	Response
+	Window          string `json:"window"`
+	Recommendations []struct {
+		Title               string  `json:"title"`
+		EstimatedSavingsUSD float64 `json:"estimatedSavingsUSD"`
+	} `json:"recommendations"`
+}
+
+// EnzanForecastResponse is the /v1/enzan/forecast response.
+type EnzanForecastResponse struct {
+	Response
+	Horizon      string  `json:"horizon"`
+	ProjectedUSD float64 `json:"projectedUSD"`
+	Series       []struct {
+		Date        string  `json:"date"`
+		ExpectedUSD float64 `json:"expectedUSD"`
+		LowerUSD    float64 `json:"lowerUSD"`
+		UpperUSD    float64 `json:"upperUSD"`
+	} `json:"series,omitempty"`
+}
+
+// EnzanBudget is one budget, as listed or as created or updated.
+type EnzanBudget struct {
+	Response
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name,omitempty"`
+	AmountUSD float64                `json:"amountUSD"`
+	Period    string                 `json:"period"`
+	SpentUSD  float64                `json:"spentUSD,omitempty"`
+	Scope     map[string]interface{} `json:"scope,omitempty"`
+}
+
+// EnzanBudgetsResponse is the /v1/enzan/budgets listing.
+type EnzanBudgetsResponse struct {
+	Response
+	Budgets []EnzanBudget `json:"budgets"`
+}
+
+// EnzanBreakdownResponse is the /v1/enzan/breakdown response.
+type EnzanBreakdownResponse struct {
+	Response
+	Dimension string           `json:"dimension"`
+	Groups    []EnzanCostGroup `json:"groups"`
+}
+
+// EnzanExportResponse is the /v1/enzan/export response: the CSV inline,
+// or a download link for reports over the inline limit.
+type EnzanExportResponse struct {
+	Response
+	Format      string `json:"format"`
+	RowCount    *int   `json:"rowCount,omitempty"`
+	CSV         string `json:"csv,omitempty"`
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+}
+
+// EnzanCompareResponse is the /v1/enzan/compare response.
+type EnzanCompareResponse struct {
+	Response
+	Period string `json:"period"`
+	Groups []struct {
+		Key         string  `json:"key"`
+		PreviousUSD float64 `json:"previousUSD"`
+		CurrentUSD  float64 `json:"currentUSD"`
+		DeltaUSD    float64 `json:"deltaUSD"`
+		DeltaPct    float64 `json:"deltaPct"`
+	} `json:"groups"`
+}
+
+// EnzanChatResponse is the /v1/enzan/chat response.
+type EnzanChatResponse struct {
+	Response
+	ConversationID string `json:"conversationId"`
+	Answer         string `json:"answer"`
+}
+
+// EnzanRouting is the model routing configuration.
+type EnzanRouting struct {
+	Response
+	Enabled       bool   `json:"enabled"`
+	SimpleModel   string `json:"simple_model,omitempty"`
+	ModerateModel string `json:"moderate_model,omitempty"`
+	ComplexModel  string `json:"complex_model,omitempty"`
+}
+
+// EnzanRoutingSavingsResponse is the /v1/enzan/routing/savings response.
+type EnzanRoutingSavingsResponse struct {
+	Response
+	Window         string  `json:"window"`
+	SavingsUSD     float64 `json:"savingsUSD"`
+	RoutedRequests int     `json:"routedRequests"`
+}
+
+// EnzanModelPrice is the price of one model.
+type EnzanModelPrice struct {
+	Response
+	Provider              string  `json:"provider"`
+	Model                 string  `json:"model"`
+	InputCostPer1kTokens  float64 `json:"input_cost_per_1k_tokens_usd"`
+	OutputCostPer1kTokens float64 `json:"output_cost_per_1k_tokens_usd"`
+	Active                bool    `json:"active"`
+}
+
+// EnzanModelPricingResponse is the /v1/enzan/pricing/models listing.
+type EnzanModelPricingResponse struct {
+	Response
+	Models []EnzanModelPrice `json:"models"`
+}
+
+// EnzanGPUPrice is the hourly rate of one GPU type.
+type EnzanGPUPrice struct {
+	Response
+	Provider      string  `json:"provider"`
+	GPUType       string  `json:"gpu_type"`
+	HourlyRateUSD float64 `json:"hourly_rate_usd"`
+	Active        bool    `json:"active"`
+}
+
+// EnzanGPUPricingResponse is the /v1/enzan/pricing/gpus listing.
+type EnzanGPUPricingResponse struct {
+	Response
+	GPUs []EnzanGPUPrice `json:"gpus"`
+}
+
+// EnzanPricingProvidersResponse is the /v1/enzan/pricing/providers
+// listing.
+type EnzanPricingProvidersResponse struct {
+	Response
+	Providers []struct {
+		Name            string `json:"name"`
+		LastRefreshedAt string `json:"lastRefreshedAt,omitempty"`
+	} `json:"providers"`
+}
+
+// EnzanPricingRefreshResponse reports a pricing refresh request. A
+// dropped request comes back as a 429 error carrying this body.
+type EnzanPricingRefreshResponse struct {
+	Response
+	Status      string `json:"status"`
+	TriggeredBy string `json:"triggeredBy,omitempty"`
+}
+
+// EnzanPricingRefreshLogResponse is the /v1/enzan/pricing/refresh/log
+// response.
+type EnzanPricingRefreshLogResponse struct {
+	Response
+	Entries []struct {
+		StartedAt string `json:"startedAt"`
+		Status    string `json:"status"`
+		Updated   int    `json:"updated"`
+	} `json:"entries"`
+}
+
+// EnzanPricingOfferResponse is the /v1/enzan/pricing/offers response. A
+// stale offer comes back as a 409 error carrying this body.
+type EnzanPricingOfferResponse struct {
+	Response
+	Status string                 `json:"status"`
+	GPU    map[string]interface{} `json:"gpu,omitempty"`
+	LLM    map[string]interface{} `json:"llm,omitempty"`
+}
+
+// EnzanAlert is one alert rule.
+type EnzanAlert struct {
+	Response
+	ID        string  `json:"id"`
+	Name      string  `json:"name,omitempty"`
+	Type      string  `json:"type,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Window    string  `json:"window,omitempty"`
+	Enabled   bool    `json:"enabled"`
+}
+
+// EnzanAlertsResponse is the /v1/enzan/alerts listing.
+type EnzanAlertsResponse struct {
+	Response
+	Alerts []EnzanAlert `json:"alerts"`
+}
+
+// EnzanAlertEventsResponse is the /v1/enzan/alerts/events listing.
+type EnzanAlertEventsResponse struct {
+	Response
+	Events []struct {
+		ID        string                   `json:"id"`
+		Status    string                   `json:"status"`
+		AlertID   string                   `json:"alertId"`
+		Resources []map[string]interface{} `json:"resources,omitempty"`
+	} `json:"events"`
+}
+
+// EnzanAlertDeliveriesResponse is the /v1/enzan/alerts/deliveries listing.
+type EnzanAlertDeliveriesResponse struct {
+	Response
+	Deliveries []struct {
+		ID           string `json:"id"`
+		EndpointID   string `json:"endpointId"`
+		Status       string `json:"status"`
+		ResponseCode int    `json:"responseCode,omitempty"`
+	} `json:"deliveries"`
+}
+
+// EnzanAlertEndpoint is one webhook endpoint for alert deliveries.
+type EnzanAlertEndpoint struct {
+	Response
+	ID        string `json:"id"`
+	TargetURL string `json:"targetUrl,omitempty"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// EnzanAlertEndpointsResponse is the /v1/enzan/alerts/endpoints listing.
+type EnzanAlertEndpointsResponse struct {
+	Response
+	Endpoints []EnzanAlertEndpoint `json:"endpoints"`
+}
+
+// DeleteResponse acknowledges a deletion.
+type DeleteResponse struct {
+	Response
+	Deleted bool `json:"deleted"`
+}
+
+// EnzanBurnResponse is the /v1/enzan/burn response.
+type EnzanBurnResponse struct {
+	Response
+	HourlyUSD           float64 `json:"hourlyUSD"`
+	DailyUSD            float64 `json:"dailyUSD"`
+	MonthlyProjectedUSD float64 `json:"monthlyProjectedUSD"`
+}
+
+// EnzanAnomaliesResponse is the /v1/enzan/anomalies response.
+type EnzanAnomaliesResponse struct {
+	Response
+	Anomalies []struct {
+		Project      string  `json:"project,omitempty"`
+		Cluster      string  `json:"cluster,omitempty"`
+		ExpectedUSD  float64 `json:"expectedUSD"`
+		ActualUSD    float64 `json:"actualUSD"`
+		DeviationPct float64 `json:"deviationPct"`
+	} `json:"anomalies"`
+}
+
+// EnzanEventsResponse is the /v1/enzan/events response.
+type EnzanEventsResponse struct {
+	Response
+	Window string `json:"window"`
+	Events []struct {
+		Time     string `json:"time"`
+		Kind     string `json:"kind"`
+		Cluster  string `json:"cluster,omitempty"`
+		Node     string `json:"node,omitempty"`
+		Workload string `json:"workload,omitempty"`
+	} `json:"events"`
+}
+
+// EnzanUtilizationResponse is the /v1/enzan/utilization response; the
+// populated list follows the requested grouping.
+type EnzanUtilizationResponse struct {
+	Response
+	Nodes    []map[string]interface{} `json:"nodes,omitempty"`
+	Clusters []map[string]interface{} `json:"clusters,omitempty"`
+	GPUs     []map[string]interface{} `json:"gpus,omitempty"`
+}
+
+// EnzanRecommendationsResponse is the /v1/enzan/recommendations response.
+type EnzanRecommendationsResponse struct {
+	Response
+	Recommendations []struct {
+		Kind                       string  `json:"kind"`
+		Resource                   string  `json:"resource"`
+		EstimatedMonthlySavingsUSD float64 `json:"estimatedMonthlySavingsUSD"`
+	} `json:"recommendations"`
+}
+
+// EnzanIdleResponse is the /v1/enzan/idle response.
+type EnzanIdleResponse struct {
+	Response
+	GPUs []struct {
+		Node        string  `json:"node"`
+		GPU         int     `json:"gpu"`
+		IdleHours   float64 `json:"idleHours"`
+		IdleCostUSD float64 `json:"idleCostUSD"`
+	} `json:"gpus"`
+}
+
+// EnzanAllocationResponse is the /v1/enzan/allocation response.
+type EnzanAllocationResponse struct {
+	Response
+	CostCenters []struct {
+		Name    string  `json:"name"`
+		CostUSD float64 `json:"costUSD"`
+	} `json:"costCenters"`
+	UnallocatedUSD float64 `json:"unallocatedUSD"`
+}
+
+// EnzanSummary reports spend over a window, optionally grouped.
+func (c *Client) EnzanSummary(ctx context.Context, req EnzanSummaryRequest) (*EnzanSummaryResponse, error) {
+	return send[EnzanSummaryResponse](ctx, c, http.MethodPost, "/v1/enzan/summary", req)
+}
+
+// EnzanCostsByModel reports spend per model over a window.
+func (c *Client) EnzanCostsByModel(ctx context.Context, req EnzanWindowRequest) (*EnzanCostsByModelResponse, error) {
+	return send[EnzanCostsByModelResponse](ctx, c, http.MethodPost, "/v1/enzan/costs/by-model", req)
+}
+
+// EnzanOptimize lists savings opportunities over a window.
+func (c *Client) EnzanOptimize(ctx context.Context, req EnzanWindowRequest) (*EnzanOptimizeResponse, error) {
+	return send[EnzanOptimizeResponse](ctx, c, http.MethodPost, "/v1/enzan/optimize", req)
+}
+
+// EnzanForecast projects spend over a horizon.
+func (c *Client) EnzanForecast(ctx context.Context, req EnzanForecastRequest) (*EnzanForecastResponse, error) {
+	return send[EnzanForecastResponse](ctx, c, http.MethodPost, "/v1/enzan/forecast", req)
+}
+
+// EnzanBudgets lists budgets.
+func (c *Client) EnzanBudgets(ctx context.Context) (*EnzanBudgetsResponse, error) {
+	return get[EnzanBudgetsResponse](ctx, c, "/v1/enzan/budgets", nil)
+}
+
+// EnzanCreateBudget creates a budget.
+func (c *Client) EnzanCreateBudget(ctx context.Context, req EnzanBudgetRequest) (*EnzanBudget, error) {
+	return send[EnzanBudget](ctx, c, http.MethodPost, "/v1/enzan/budgets", req)
+}
+
+// EnzanUpdateBudget changes the fields of a budget that req sets.
+func (c *Client) EnzanUpdateBudget(ctx context.Context, id string, req EnzanBudgetRequest) (*EnzanBudget, error) {
+	return send[EnzanBudget](ctx, c, http.MethodPatch, "/v1/enzan/budgets/"+url.PathEscape(id), req)
+}
+
+// EnzanBreakdown reports spend by one dimension (dimension, labelKey,
+// window, top).
+func (c *Client) EnzanBreakdown(ctx context.Context, query url.Values) (*EnzanBreakdownResponse, error) {
+	return get[EnzanBreakdownResponse](ctx, c, "/v1/enzan/breakdown", query)
+}
+
+// EnzanExport exports a spend report as CSV.
+func (c *Client) EnzanExport(ctx context.Context, req EnzanExportRequest) (*EnzanExportResponse, error) {
+	return send[EnzanExportResponse](ctx, c, http.MethodPost, "/v1/enzan/export", req)
+}
+
+// EnzanCompare compares spend in the current and previous period.
+func (c *Client) EnzanCompare(ctx context.Context, req EnzanCompareRequest) (*EnzanCompareResponse, error) {
+	return send[EnzanCompareResponse](ctx, c, http.MethodPost, "/v1/enzan/compare", req)
+}
+
+// EnzanChat asks the cost assistant a question. The payload keeps only
+// the arguments the caller set.
+func (c *Client) EnzanChat(ctx context.Context, payload map[string]interface{}) (*EnzanChatResponse, error) {
+	return send[EnzanChatResponse](ctx, c, http.MethodPost, "/v1/enzan/chat", payload)
+}
+
+// EnzanRouting reads the model routing configuration.
+func (c *Client) EnzanRouting(ctx context.Context) (*EnzanRouting, error) {
+	return get[EnzanRouting](ctx, c, "/v1/enzan/routing", nil)
+}
+
+// EnzanSetRouting replaces the model routing configuration.
+func (c *Client) EnzanSetRouting(ctx context.Context, payload map[string]interface{}) (*EnzanRouting, error) {
+	return send[EnzanRouting](ctx, c, http.MethodPost, "/v1/enzan/routing", payload)
+}
+
+// EnzanRoutingSavings reports what routing saved over a window, or the
+// backend's default window when window is empty.
+func (c *Client) EnzanRoutingSavings(ctx context.Context, window string) (*EnzanRoutingSavingsResponse, error) {
+	query := url.Values{}
+	if window != "" {
+		query.Set("window", window)
+	}
+	return get[EnzanRoutingSavingsResponse](ctx, c, "/v1/enzan/routing/savings", query)
+}
+
+// EnzanModelPricing lists model prices.
+func (c *Client) EnzanModelPricing(ctx context.Context) (*EnzanModelPricingResponse, error) {
+	return get[EnzanModelPricingResponse](ctx, c, "/v1/enzan/pricing/models", nil)
+}
+
+// EnzanSetModelPricing overrides a model's token prices.
+func (c *Client) EnzanSetModelPricing(ctx context.Context, payload map[string]interface{}) (*EnzanModelPrice, error) {
+	return send[EnzanModelPrice](ctx, c, http.MethodPost, "/v1/enzan/pricing/models", payload)
+}
+
+// EnzanGPUPricing lists GPU hourly rates.
+func (c *Client) EnzanGPUPricing(ctx context.Context) (*EnzanGPUPricingResponse, error) {
+	return get[EnzanGPUPricingResponse](ctx, c, "/v1/enzan/pricing/gpus", nil)
+}
+
+// EnzanSetGPUPricing overrides a GPU type's hourly rate.
+func (c *Client) EnzanSetGPUPricing(ctx context.Context, payload map[string]interface{}) (*EnzanGPUPrice, error) {
+	return send[EnzanGPUPrice](ctx, c, http.MethodPost, "/v1/enzan/pricing/gpus", payload)
+}
+
+// EnzanPricingProviders lists the providers prices are refreshed from.
+func (c *Client) EnzanPricingProviders(ctx context.Context) (*EnzanPricingProvidersResponse, error) {
+	return get[EnzanPricingProvidersResponse](ctx, c, "/v1/enzan/pricing/providers", nil)
+}
+
+// EnzanTriggerPricingRefresh queues a refresh of provider prices.
+func (c *Client) EnzanTriggerPricingRefresh(ctx context.Context) (*EnzanPricingRefreshResponse, error) {
+	return send[EnzanPricingRefreshResponse](ctx, c, http.MethodPost, "/v1/enzan/pricing/refresh", nil)
+}
+
+// EnzanPricingRefreshLog lists past pricing refreshes (limit).
+func (c *Client) EnzanPricingRefreshLog(ctx context.Context, query url.Values) (*EnzanPricingRefreshLogResponse, error) {
+	return get[EnzanPricingRefreshLogResponse](ctx, c, "/v1/enzan/pricing/refresh/log", query)
+}
+
+// EnzanUpsertPricingOffer records a GPU or LLM offer; payload sets
+// exactly one of gpu and llm.
+func (c *Client) EnzanUpsertPricingOffer(ctx context.Context, payload map[string]interface{}) (*EnzanPricingOfferResponse, error) {
+	return send[EnzanPricingOfferResponse](ctx, c, http.MethodPost, "/v1/enzan/pricing/offers", payload)
+}
+
+// EnzanAlerts lists alert rules.
+func (c *Client) EnzanAlerts(ctx context.Context) (*EnzanAlertsResponse, error) {
+	return get[EnzanAlertsResponse](ctx, c, "/v1/enzan/alerts", nil)
+}
+
+// EnzanCreateAlert creates an alert rule.
+func (c *Client) EnzanCreateAlert(ctx context.Context, payload map[string]interface{}) (*EnzanAlert, error) {
+	return send[EnzanAlert](ctx, c, http.MethodPost, "/v1/enzan/alerts", payload)
+}
+
+// EnzanUpdateAlert changes the fields of an alert rule that payload sets.
+func (c *Client) EnzanUpdateAlert(ctx context.Context, id string, payload map[string]interface{}) (*EnzanAlert, error) {
+	return send[EnzanAlert](ctx, c, http.MethodPatch, "/v1/enzan/alerts/"+url.PathEscape(id), payload)
+}
+
+// EnzanDeleteAlert deletes an alert rule.
+func (c *Client) EnzanDeleteAlert(ctx context.Context, id string) (*DeleteResponse, error) {
+	return send[DeleteResponse](ctx, c, http.MethodDelete, "/v1/enzan/alerts/"+url.PathEscape(id), nil)
+}
+
+// EnzanAlertEvents lists alert firings (status, window, limit).
+func (c *Client) EnzanAlertEvents(ctx context.Context, query url.Values) (*EnzanAlertEventsResponse, error) {
+	return get[EnzanAlertEventsResponse](ctx, c, "/v1/enzan/alerts/events", query)
+}
+
+// EnzanAlertDeliveries lists webhook deliveries (limit).
+func (c *Client) EnzanAlertDeliveries(ctx context.Context, query url.Values) (*EnzanAlertDeliveriesResponse, error) {
+	return get[EnzanAlertDeliveriesResponse](ctx, c, "/v1/enzan/alerts/deliveries", query)
+}
+
+// EnzanAlertEndpoints lists webhook endpoints.
+func (c *Client) EnzanAlertEndpoints(ctx context.Context) (*EnzanAlertEndpointsResponse, error) {
+	return get[EnzanAlertEndpointsResponse](ctx, c, "/v1/enzan/alerts/endpoints", nil)
+}
+
+// EnzanCreateAlertEndpoint adds a webhook endpoint.
+func (c *Client) EnzanCreateAlertEndpoint(ctx context.Context, payload map[string]interface{}) (*EnzanAlertEndpoint, error) {
+	return send[EnzanAlertEndpoint](ctx, c, http.MethodPost, "/v1/enzan/alerts/endpoints", payload)
+}
+
+// EnzanUpdateAlertEndpoint changes the fields of a webhook endpoint that
+// payload sets.
+func (c *Client) EnzanUpdateAlertEndpoint(ctx context.Context, id string, payload map[string]interface{}) (*EnzanAlertEndpoint, error) {
+	return send[EnzanAlertEndpoint](ctx, c, http.MethodPatch, "/v1/enzan/alerts/endpoints/"+url.PathEscape(id), payload)
+}
+
+// EnzanDeleteAlertEndpoint deletes a webhook endpoint.
+func (c *Client) EnzanDeleteAlertEndpoint(ctx context.Context, id string) (*DeleteResponse, error) {
+	return send[DeleteResponse](ctx, c, http.MethodDelete, "/v1/enzan/alerts/endpoints/"+url.PathEscape(id), nil)
+}
+
+// EnzanBurn reports the current burn rate.
+func (c *Client) EnzanBurn(ctx context.Context) (*EnzanBurnResponse, error) {
+	return get[EnzanBurnResponse](ctx, c, "/v1/enzan/burn", nil)
+}
+
+// EnzanAnomalies lists spend anomalies (window, minDeviationPct, limit).
+func (c *Client) EnzanAnomalies(ctx context.Context, query url.Values) (*EnzanAnomaliesResponse, error) {
+	return get[EnzanAnomaliesResponse](ctx, c, "/v1/enzan/anomalies", query)
+}
+
+// EnzanEvents lists infrastructure events such as preemptions.
+func (c *Client) EnzanEvents(ctx context.Context, query url.Values) (*EnzanEventsResponse, error) {
+	return get[EnzanEventsResponse](ctx, c, "/v1/enzan/events", query)
+}
+
+// EnzanUtilization reports GPU utilization (cluster, groupBy, window).
+func (c *Client) EnzanUtilization(ctx context.Context, query url.Values) (*EnzanUtilizationResponse, error) {
+	return get[EnzanUtilizationResponse](ctx, c, "/v1/enzan/utilization", query)
+}
+
+// EnzanRecommendations lists cost recommendations (kind, minSavingsUSD).
+func (c *Client) EnzanRecommendations(ctx context.Context, query url.Values) (*EnzanRecommendationsResponse, error) {
+	return get[EnzanRecommendationsResponse](ctx, c, "/v1/enzan/recommendations", query)
+}
+
+// EnzanIdle lists idle GPUs (minHours, thresholdPct).
+func (c *Client) EnzanIdle(ctx context.Context, query url.Values) (*EnzanIdleResponse, error) {
+	return get[EnzanIdleResponse](ctx, c, "/v1/enzan/idle", query)
+}
+
+// EnzanAllocation reports spend by cost center (mode, window).
+func (c *Client) EnzanAllocation(ctx context.Context, query url.Values) (*EnzanAllocationResponse, error) {
+	return get[EnzanAllocationResponse](ctx, c, "/v1/enzan/allocation", query)
+}
@@ -0,0 +1,82 @@
+package kaizen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HealthResponse is the /v1/health response.
+type HealthResponse struct {
+	Response
+	Status     string                 `json:"status"`
+	APIVersion string                 `json:"apiVersion,omitempty"`
+	Components map[string]interface{} `json:"components,omitempty"`
+}
+
+// Identity is who the credentials resolve to, from /v1/whoami.
+type Identity struct {
+	Response
+	Subject      string `json:"subject,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Scopes       Scopes `json:"scopes,omitempty"`
+}
+
+// Scopes is a list of API scopes. It decodes from an array or, as OAuth
+// scope claims are written, from one space-separated string.
+type Scopes []string
+
+// UnmarshalJSON accepts both encodings. A null leaves s nil, like an
+// absent field, so callers can tell "no scopes reported" from "none".
+func (s *Scopes) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var joined string
+	if err := json.Unmarshal(data, &joined); err == nil {
+		*s = strings.Fields(joined)
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("scopes must be a string or an array of strings")
+	}
+	*s = list
+	return nil
+}
+
+// UsageResponse is the /v1/usage response.
+type UsageResponse struct {
+	Response
+	Period string                 `json:"period,omitempty"`
+	Quotas map[string]interface{} `json:"quotas,omitempty"`
+}
+
+// BudgetEstimate is the /v1/budget/estimate response.
+type BudgetEstimate struct {
+	Response
+	EstimatedCostUSD   float64  `json:"estimatedCostUSD"`
+	RemainingBudgetUSD *float64 `json:"remainingBudgetUSD,omitempty"`
+}
+
+// Health reports backend and per-component health.
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	return get[HealthResponse](ctx, c, "/v1/health", nil)
+}
+
+// WhoAmI reports the identity and scopes of the credentials.
+func (c *Client) WhoAmI(ctx context.Context) (*Identity, error) {
+	return get[Identity](ctx, c, "/v1/whoami", nil)
+}
+
+// Usage reports quota usage for the current period.
+func (c *Client) Usage(ctx context.Context) (*UsageResponse, error) {
+	return get[UsageResponse](ctx, c, "/v1/usage", nil)
+}
+
+// EstimateBudget prices a tool call without running it.
+func (c *Client) EstimateBudget(ctx context.Context, req BudgetEstimateRequest) (*BudgetEstimate, error) {
+	return send[BudgetEstimate](ctx, c, http.MethodPost, "/v1/budget/estimate", req)
+}
@@ -0,0 +1,173 @@
+package kaizen
+
+// AkumaQueryRequest is the wire payload shared by /v1/akuma/query and
+// /v1/akuma/queries/interactive.
+type AkumaQueryRequest struct {
+	Dialect    string                 `json:"dialect"`
+	Prompt     string                 `json:"prompt"`
+	Mode       string                 `json:"mode,omitempty"`
+	MaxRows    *int                   `json:"maxRows,omitempty"`
+	SourceID   string                 `json:"sourceId,omitempty"`
+	Guardrails map[string]interface{} `json:"guardrails,omitempty"`
+}
+
+// EnzanWindowRequest is the wire payload for window-scoped Enzan
+// analytics endpoints such as costs by model and optimize.
+type EnzanWindowRequest struct {
+	Window string `json:"window"`
+}
+
+// EnzanSummaryRequest is the wire payload for /v1/enzan/summary.
+type EnzanSummaryRequest struct {
+	Window   string   `json:"window"`
+	GroupBy  []string `json:"groupBy,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+// AkumaSQLRequest is the wire payload for Akuma endpoints that analyze a
+// single SQL statement (validate, optimize, lint, cost).
+type AkumaSQLRequest struct {
+	SQL      string `json:"sql"`
+	Dialect  string `json:"dialect,omitempty"`
+	SourceID string `json:"sourceId,omitempty"`
+}
+
+// AkumaDiffRequest is the wire payload for /v1/akuma/diff.
+type AkumaDiffRequest struct {
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	Dialect string `json:"dialect,omitempty"`
+}
+
+// AkumaPreviewRequest is the wire payload for /v1/akuma/preview.
+type AkumaPreviewRequest struct {
+	Table    string   `json:"table"`
+	SourceID string   `json:"sourceId,omitempty"`
+	Columns  []string `json:"columns,omitempty"`
+	Limit    *int     `json:"limit,omitempty"`
+}
+
+// AkumaConvertRequest is the wire payload for /v1/akuma/convert.
+type AkumaConvertRequest struct {
+	SQL  string `json:"sql"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AkumaIntrospectRequest is the wire payload for
+// /v1/akuma/schema/introspect. Connection names a server-side connection.
+type AkumaIntrospectRequest struct {
+	Connection string   `json:"connection"`
+	SourceID   string   `json:"sourceId,omitempty"`
+	Schemas    []string `json:"schemas,omitempty"`
+	Tables     []string `json:"tables,omitempty"`
+}
+
+// EnzanForecastRequest is the wire payload for /v1/enzan/forecast.
+type EnzanForecastRequest struct {
+	Horizon string `json:"horizon"`
+}
+
+// EnzanBudgetRequest is the wire payload for creating (POST) or partially
+// updating (PATCH) an Enzan budget. The budget id travels in the path.
+type EnzanBudgetRequest struct {
+	Name      string                 `json:"name,omitempty"`
+	AmountUSD *float64               `json:"amountUSD,omitempty"`
+	Period    string                 `json:"period,omitempty"`
+	Scope     map[string]interface{} `json:"scope,omitempty"`
+}
+
+// EnzanExportRequest is the wire payload for /v1/enzan/export. Reports
+// larger than MaxInlineBytes are returned as a download URL.
+type EnzanExportRequest struct {
+	Window         string   `json:"window,omitempty"`
+	GroupBy        []string `json:"groupBy,omitempty"`
+	MaxInlineBytes int      `json:"maxInlineBytes"`
+}
+
+// EnzanCompareRequest is the wire payload for /v1/enzan/compare.
+type EnzanCompareRequest struct {
+	Period  string   `json:"period"`
+	GroupBy []string `json:"groupBy,omitempty"`
+}
+
+// SozoValidateRequest is the wire payload for /v1/sozo/validate.
+type SozoValidateRequest struct {
+	Schema       map[string]interface{} `json:"schema"`
+	Correlations map[string]interface{} `json:"correlations,omitempty"`
+}
+
+// SozoPreviewRequest is the wire payload for /v1/sozo/preview.
+type SozoPreviewRequest struct {
+	SchemaName   string                 `json:"schemaName,omitempty"`
+	Schema       map[string]interface{} `json:"schema,omitempty"`
+	Correlations map[string]interface{} `json:"correlations,omitempty"`
+	Seed         *float64               `json:"seed,omitempty"`
+	Records      int                    `json:"records"`
+}
+
+// SozoAnonymizeRequest is the wire payload for /v1/sozo/anonymize.
+type SozoAnonymizeRequest struct {
+	Records []map[string]interface{} `json:"records"`
+	Rules   map[string]string        `json:"rules,omitempty"`
+	Seed    *float64                 `json:"seed,omitempty"`
+}
+
+// SozoPresetRequest is the wire payload for saving a custom preset to
+// /v1/sozo/schemas.
+type SozoPresetRequest struct {
+	Name         string                 `json:"name"`
+	Schema       map[string]interface{} `json:"schema"`
+	Correlations map[string]interface{} `json:"correlations,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Overwrite    bool                   `json:"overwrite,omitempty"`
+}
+
+// SozoAugmentRequest is the wire payload for /v1/sozo/augment.
+type SozoAugmentRequest struct {
+	SeedRecords []map[string]interface{} `json:"seedRecords"`
+	Records     int                      `json:"records"`
+	Seed        *float64                 `json:"seed,omitempty"`
+}
+
+// SozoDriftRequest is the wire payload for /v1/sozo/drift.
+type SozoDriftRequest struct {
+	SchemaName string                 `json:"schemaName,omitempty"`
+	Schema     map[string]interface{} `json:"schema,omitempty"`
+	Records    int                    `json:"records"`
+	ShiftPct   float64                `json:"shiftPct"`
+	Fields     []string               `json:"fields,omitempty"`
+	Kind       string                 `json:"kind,omitempty"`
+	Seed       *float64               `json:"seed,omitempty"`
+}
+
+// SozoCorrelationsRequest is the wire payload for /v1/sozo/correlations.
+type SozoCorrelationsRequest struct {
+	JobID        string                   `json:"jobId,omitempty"`
+	Records      []map[string]interface{} `json:"records,omitempty"`
+	Correlations map[string]interface{}   `json:"correlations,omitempty"`
+	Fields       []string                 `json:"fields,omitempty"`
+}
+
+// AkumaFeedbackRequest is the wire payload for /v1/akuma/feedback.
+type AkumaFeedbackRequest struct {
+	QueryID    string `json:"queryId"`
+	Rating     string `json:"rating"`
+	Correction string `json:"correction,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// AkumaExplainRequest is the wire payload for /v1/akuma/explain.
+// IncludePlan asks for the structured plan alongside the prose.
+type AkumaExplainRequest struct {
+	SQL         string `json:"sql"`
+	IncludePlan bool   `json:"includePlan,omitempty"`
+}
+
+// BudgetEstimateRequest is the wire payload for /v1/budget/estimate: the
+// tool call to price, as the agent would send it.
+type BudgetEstimateRequest struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
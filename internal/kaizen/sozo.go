@@ -0,0 +1,162 @@
+package kaizen
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SozoGenerateResponse is the /v1/sozo/generate response.
+type SozoGenerateResponse struct {
+	Response
+	JobID   string                   `json:"jobId,omitempty"`
+	Records []map[string]interface{} `json:"records"`
+}
+
+// SozoJob is the state of an asynchronous generation job.
+type SozoJob struct {
+	Response
+	JobID    string   `json:"jobId"`
+	Status   string   `json:"status"`
+	Progress *float64 `json:"progress,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// SozoJobResultResponse is one page of a finished job's records.
+type SozoJobResultResponse struct {
+	Response
+	JobID   string                   `json:"jobId,omitempty"`
+	Records []map[string]interface{} `json:"records"`
+	Total   int                      `json:"total,omitempty"`
+}
+
+// SozoValidateResponse is the /v1/sozo/validate response.
+type SozoValidateResponse struct {
+	Response
+	Valid  bool `json:"valid"`
+	Errors []struct {
+		Path    string `json:"path"`
+		Message string `json:"message"`
+	} `json:"errors,omitempty"`
+}
+
+// SozoRecordsResponse carries generated records, as returned by preview,
+// anonymize, augment, and drift.
+type SozoRecordsResponse struct {
+	Response
+	Records []map[string]interface{} `json:"records"`
+}
+
+// SozoSchemasResponse is the /v1/sozo/schemas listing.
+type SozoSchemasResponse struct {
+	Response
+	Schemas []struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		Tags        []string `json:"tags,omitempty"`
+		Builtin     bool     `json:"builtin,omitempty"`
+	} `json:"schemas"`
+}
+
+// SozoSchema is a saved custom preset.
+type SozoSchema struct {
+	Response
+	Name string `json:"name"`
+}
+
+// SozoCorrelationsResponse is the /v1/sozo/correlations response.
+type SozoCorrelationsResponse struct {
+	Response
+	Pairs []struct {
+		Fields   []string `json:"fields"`
+		Target   float64  `json:"target"`
+		Observed float64  `json:"observed"`
+	} `json:"pairs"`
+}
+
+// SozoSeedsResponse is the /v1/sozo/seeds listing.
+type SozoSeedsResponse struct {
+	Response
+	Seeds []struct {
+		Name       string  `json:"name"`
+		Seed       float64 `json:"seed"`
+		SchemaName string  `json:"schemaName,omitempty"`
+	} `json:"seeds"`
+}
+
+// SozoGenerate generates records synchronously. The payload keeps only
+// the arguments the caller set.
+func (c *Client) SozoGenerate(ctx context.Context, payload map[string]interface{}) (*SozoGenerateResponse, error) {
+	return send[SozoGenerateResponse](ctx, c, http.MethodPost, "/v1/sozo/generate", payload)
+}
+
+// SozoStartJob queues the generation SozoGenerate runs synchronously.
+func (c *Client) SozoStartJob(ctx context.Context, payload map[string]interface{}) (*SozoJob, error) {
+	return send[SozoJob](ctx, c, http.MethodPost, "/v1/sozo/jobs", payload)
+}
+
+// SozoJob reads the state of a generation job.
+func (c *Client) SozoJob(ctx context.Context, id string) (*SozoJob, error) {
+	return get[SozoJob](ctx, c, "/v1/sozo/jobs/"+url.PathEscape(id), nil)
+}
+
+// SozoJobResult reads a page of a finished job's records (offset, limit).
+func (c *Client) SozoJobResult(ctx context.Context, id string, query url.Values) (*SozoJobResultResponse, error) {
+	return get[SozoJobResultResponse](ctx, c, "/v1/sozo/jobs/"+url.PathEscape(id)+"/result", query)
+}
+
+// SozoValidate checks a schema and its correlations without generating.
+func (c *Client) SozoValidate(ctx context.Context, req SozoValidateRequest) (*SozoValidateResponse, error) {
+	return send[SozoValidateResponse](ctx, c, http.MethodPost, "/v1/sozo/validate", req)
+}
+
+// SozoPreview generates a few records from a schema or preset.
+func (c *Client) SozoPreview(ctx context.Context, req SozoPreviewRequest) (*SozoRecordsResponse, error) {
+	return send[SozoRecordsResponse](ctx, c, http.MethodPost, "/v1/sozo/preview", req)
+}
+
+// SozoAnonymize replaces sensitive values in records.
+func (c *Client) SozoAnonymize(ctx context.Context, req SozoAnonymizeRequest) (*SozoRecordsResponse, error) {
+	return send[SozoRecordsResponse](ctx, c, http.MethodPost, "/v1/sozo/anonymize", req)
+}
+
+// SozoSchemas lists built-in and custom presets (tag).
+func (c *Client) SozoSchemas(ctx context.Context, query url.Values) (*SozoSchemasResponse, error) {
+	return get[SozoSchemasResponse](ctx, c, "/v1/sozo/schemas", query)
+}
+
+// SozoSaveSchema saves a custom preset.
+func (c *Client) SozoSaveSchema(ctx context.Context, req SozoPresetRequest) (*SozoSchema, error) {
+	return send[SozoSchema](ctx, c, http.MethodPost, "/v1/sozo/schemas", req)
+}
+
+// SozoDeleteSchema deletes a custom preset.
+func (c *Client) SozoDeleteSchema(ctx context.Context, name string) (*DeleteResponse, error) {
+	return send[DeleteResponse](ctx, c, http.MethodDelete, "/v1/sozo/schemas/"+url.PathEscape(name), nil)
+}
+
+// SozoAugment generates records resembling a sample.
+func (c *Client) SozoAugment(ctx context.Context, req SozoAugmentRequest) (*SozoRecordsResponse, error) {
+	return send[SozoRecordsResponse](ctx, c, http.MethodPost, "/v1/sozo/augment", req)
+}
+
+// SozoDrift generates records whose distribution drifts from a schema.
+func (c *Client) SozoDrift(ctx context.Context, req SozoDriftRequest) (*SozoRecordsResponse, error) {
+	return send[SozoRecordsResponse](ctx, c, http.MethodPost, "/v1/sozo/drift", req)
+}
+
+// SozoCorrelations compares the correlations records show with the ones
+// requested.
+func (c *Client) SozoCorrelations(ctx context.Context, req SozoCorrelationsRequest) (*SozoCorrelationsResponse, error) {
+	return send[SozoCorrelationsResponse](ctx, c, http.MethodPost, "/v1/sozo/correlations", req)
+}
+
+// SozoSeeds lists named seeds (schemaName).
+func (c *Client) SozoSeeds(ctx context.Context, query url.Values) (*SozoSeedsResponse, error) {
+	return get[SozoSeedsResponse](ctx, c, "/v1/sozo/seeds", query)
+}
+
+// SozoReplaySeed regenerates the records a named seed produced.
+func (c *Client) SozoReplaySeed(ctx context.Context, name string) (*SozoGenerateResponse, error) {
+	return send[SozoGenerateResponse](ctx, c, http.MethodPost, "/v1/sozo/seeds/"+url.PathEscape(name)+"/replay", nil)
+}
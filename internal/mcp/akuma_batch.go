@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+// akumaBatchMaxPrompts bounds akuma.batch; every prompt is its own backend
+// query, all in flight at once.
+const akumaBatchMaxPrompts = 10
+
+// callAkumaBatch runs one akuma.query per prompt concurrently, sharing the
+// other arguments. A failed prompt does not fail the batch: its entry in
+// results carries the error instead.
+func (s *Server) callAkumaBatch(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	rawPrompts, _ := args["prompts"].([]interface{})
+	if len(rawPrompts) == 0 || len(rawPrompts) > akumaBatchMaxPrompts {
+		return nil, fmt.Errorf("prompts must hold 1 to %d prompts", akumaBatchMaxPrompts)
+	}
+	payloads := make([]*kaizen.AkumaQueryRequest, len(rawPrompts))
+	for i, raw := range rawPrompts {
+		prompt, ok := raw.(string)
+		if !ok || prompt == "" {
+			return nil, fmt.Errorf("prompts[%d] must be a non-empty string", i)
+		}
+		payload, err := s.akumaQueryPayload(akumaBatchQueryArgs(args, prompt))
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = payload
+	}
+
+	results := make([]interface{}, len(payloads))
+	var wg sync.WaitGroup
+	for i, payload := range payloads {
+		wg.Add(1)
+		go func(i int, payload *kaizen.AkumaQueryRequest) {
+			defer wg.Done()
+			entry := map[string]interface{}{"prompt": payload.Prompt}
+			results[i] = entry
+			// A panic here would kill the process: the tool's own
+			// recovery only covers the handler's goroutine.
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					s.logPanic(ctx, "akuma.batch", recovered, debug.Stack())
+					entry["error"] = "prompt failed unexpectedly; see the server log"
+				}
+			}()
+			resp, err := s.kaizenClient(ctx).AkumaQuery(ctx, *payload)
+			if err != nil {
+				entry["error"] = err.Error()
+			} else {
+				entry["result"] = capRows(resp.Fields(), payload.MaxRows)
+			}
+		}(i, payload)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, entry := range results {
+		if _, ok := entry.(map[string]interface{})["error"]; ok {
+			failed++
+		}
+	}
+	return map[string]interface{}{
+		"results":   results,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+	}, nil
+}
+
+// akumaBatchQueryArgs is the akuma.query call behind one batch prompt.
+func akumaBatchQueryArgs(args map[string]interface{}, prompt string) map[string]interface{} {
+	queryArgs := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if key != "prompts" {
+			queryArgs[key] = value
+		}
+	}
+	queryArgs["prompt"] = prompt
+	return queryArgs
+}
+
+// akumaBatchEstimates prices a batch as the akuma.query calls it runs, one
+// per prompt, so the whole batch is held to the ceiling.
+func akumaBatchEstimates(args map[string]interface{}) []kaizen.BudgetEstimateRequest {
+	prompts, _ := args["prompts"].([]interface{})
+	requests := make([]kaizen.BudgetEstimateRequest, 0, len(prompts))
+	for _, raw := range prompts {
+		if prompt, ok := raw.(string); ok {
+			requests = append(requests, kaizen.BudgetEstimateRequest{Tool: "akuma.query", Arguments: akumaBatchQueryArgs(args, prompt)})
+		}
+	}
+	return requests
+}
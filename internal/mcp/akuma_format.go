@@ -0,0 +1,417 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// akuma.format pretty-prints SQL locally, without a backend call, so
+// queries can be tidied while the Kaizen API is unreachable. It is a
+// layout pass, not a parser: tokens are kept as written except for
+// keyword case, and each clause starts a new line.
+
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuoted
+	sqlString
+	sqlNumber
+	sqlParam
+	sqlPunct
+	sqlLineComment
+	sqlBlockComment
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+var sqlKeywords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`ADD ALL ALTER AND ANY AS ASC BETWEEN BY CASE CAST CREATE CROSS DELETE DESC
+		DISTINCT DROP ELSE END EXCEPT EXISTS FALSE FETCH FILTER FIRST FROM FULL GROUP HAVING ILIKE IN INNER
+		INSERT INTERSECT INTERVAL INTO IS JOIN KEY LAST LATERAL LEFT LIKE LIMIT NATURAL NEXT NOT NULL NULLS
+		OFFSET ON ONLY OR ORDER OUTER OVER PARTITION PRIMARY QUALIFY RECURSIVE RETURNING RIGHT ROW ROWS
+		SELECT SET SOME TABLE THEN TRUE UNION UPDATE USING VALUES VIEW WHEN WHERE WINDOW WITH WITHIN`) {
+		sqlKeywords[word] = true
+	}
+}
+
+// sqlClauseKeywords start a new line when they appear at statement level.
+var sqlClauseKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true,
+	"LIMIT": true, "OFFSET": true, "UNION": true, "INTERSECT": true, "EXCEPT": true, "INSERT": true,
+	"VALUES": true, "UPDATE": true, "SET": true, "DELETE": true, "WITH": true, "JOIN": true,
+	"LEFT": true, "RIGHT": true, "INNER": true, "FULL": true, "CROSS": true, "NATURAL": true,
+	"QUALIFY": true, "WINDOW": true, "RETURNING": true, "FETCH": true,
+}
+
+// sqlJoinModifiers keep a following JOIN on their line.
+var sqlJoinModifiers = map[string]bool{"LEFT": true, "RIGHT": true, "INNER": true, "FULL": true, "CROSS": true, "NATURAL": true, "OUTER": true}
+
+var sqlMultiCharOperators = []string{"<=", ">=", "<>", "!=", "::", "||", "->>", "->", "=>"}
+
+func tokenizeSQL(sql, dialect string) ([]sqlToken, error) {
+	backslashEscapes := dialect == "mysql" || dialect == "bigquery"
+	var tokens []sqlToken
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(sql[i:], "--") || (c == '#' && dialect == "mysql"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			tokens = append(tokens, sqlToken{sqlLineComment, strings.TrimRight(sql[i:i+end], " \t\r")})
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated block comment")
+			}
+			tokens = append(tokens, sqlToken{sqlBlockComment, sql[i : i+end+4]})
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			end, err := scanSQLQuoted(sql, i, backslashEscapes && c != '`')
+			if err != nil {
+				return nil, err
+			}
+			kind := sqlQuoted
+			if c == '\'' || (c == '"' && dialect == "bigquery") {
+				kind = sqlString
+			}
+			tokens = append(tokens, sqlToken{kind, sql[i:end]})
+			i = end
+		case c == '$' && sqlDollarTag.MatchString(sql[i:]):
+			tag := sqlDollarTag.FindString(sql[i:])
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated %s quoted text starting at offset %d", tag, i)
+			}
+			end += i + 2*len(tag)
+			tokens = append(tokens, sqlToken{sqlString, sql[i:end]})
+			i = end
+		case startsSQLNumber(sql, i) || (c == '-' || c == '+') && startsSQLNumber(sql, i+1) && isUnaryPosition(tokens):
+			end := scanSQLNumber(sql, i)
+			tokens = append(tokens, sqlToken{sqlNumber, sql[i:end]})
+			i = end
+		case (c == ':' || c == '@' || c == '$') && i+1 < len(sql) && (isSQLWordByte(sql[i+1]) || sql[i+1] == '@' && c == '@'):
+			// Bind parameters (:name, @name, $1) and @@variables.
+			end := i + 1
+			for end < len(sql) && (isSQLWordByte(sql[end]) || sql[end] == '@' || sql[end] >= 0x80) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{sqlParam, sql[i:end]})
+			i = end
+		case isSQLWordByte(c) || c == '$' || c >= 0x80:
+			end := i + 1
+			for end < len(sql) && (isSQLWordByte(sql[end]) || sql[end] == '$' || sql[end] >= 0x80) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{sqlWord, sql[i:end]})
+			i = end
+		default:
+			text := sql[i : i+1]
+			for _, op := range sqlMultiCharOperators {
+				if strings.HasPrefix(sql[i:], op) {
+					text = op
+					break
+				}
+			}
+			tokens = append(tokens, sqlToken{sqlPunct, text})
+			i += len(text)
+		}
+	}
+	return tokens, nil
+}
+
+// sqlDollarTag matches the opening delimiter of a dollar-quoted string,
+// $$ or $tag$.
+var sqlDollarTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+func startsSQLNumber(sql string, i int) bool {
+	return i < len(sql) && (isDigit(sql[i]) || sql[i] == '.' && i+1 < len(sql) && isDigit(sql[i+1]))
+}
+
+// scanSQLNumber returns the index just past the number starting at start,
+// which may begin with a sign, including an exponent such as e-5 or E+3.
+func scanSQLNumber(sql string, start int) int {
+	end := start + 1
+	hex := strings.HasPrefix(strings.TrimLeft(sql[start:], "+-"), "0x")
+	for end < len(sql) {
+		switch c := sql[end]; {
+		case isSQLWordByte(c) || c == '.':
+			end++
+		case (c == '+' || c == '-') && !hex && (sql[end-1] == 'e' || sql[end-1] == 'E') && end+1 < len(sql) && isDigit(sql[end+1]):
+			end += 2
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// isUnaryPosition reports whether a sign after tokens is unary: at the
+// start, or after an operator, an opening parenthesis, a comma, or a
+// keyword such as SELECT or THEN.
+func isUnaryPosition(tokens []sqlToken) bool {
+	for j := len(tokens) - 1; j >= 0; j-- {
+		switch prev := tokens[j]; prev.kind {
+		case sqlLineComment, sqlBlockComment:
+			continue
+		case sqlPunct:
+			return prev.text != ")" && prev.text != "]"
+		case sqlWord:
+			return sqlKeywords[strings.ToUpper(prev.text)] && !strings.EqualFold(prev.text, "NULL") &&
+				!strings.EqualFold(prev.text, "TRUE") && !strings.EqualFold(prev.text, "FALSE")
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || unicode.IsLetter(rune(c))
+}
+
+// scanSQLQuoted returns the index just past the quoted token starting at
+// start. A doubled quote character is an escaped quote.
+func scanSQLQuoted(sql string, start int, backslashEscapes bool) (int, error) {
+	quote := sql[start]
+	for i := start + 1; i < len(sql); i++ {
+		switch {
+		case backslashEscapes && sql[i] == '\\':
+			i++
+		case sql[i] == quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated quoted text starting at offset %d", start)
+}
+
+type sqlFrame struct {
+	subquery bool
+	indent   int
+	clause   string
+	between  bool
+}
+
+type sqlFormatter struct {
+	tokens      []sqlToken
+	keywordCase string
+	indentUnit  string
+
+	out     strings.Builder
+	line    strings.Builder
+	frames  []sqlFrame
+	prev    sqlToken
+	started bool
+}
+
+// formatSQL lays out sql one clause per line. Select-list items and
+// AND/OR conditions get their own indented lines, and subqueries are
+// indented inside their parentheses.
+func formatSQL(sql, dialect, keywordCase string, indent int) (string, error) {
+	tokens, err := tokenizeSQL(sql, dialect)
+	if err != nil {
+		return "", err
+	}
+	f := &sqlFormatter{
+		tokens:      tokens,
+		keywordCase: keywordCase,
+		indentUnit:  strings.Repeat(" ", indent),
+		frames:      []sqlFrame{{subquery: true}},
+	}
+	for i, tok := range tokens {
+		f.emit(i, tok)
+	}
+	f.flush()
+	return strings.TrimRight(f.out.String(), "\n"), nil
+}
+
+func (f *sqlFormatter) top() *sqlFrame { return &f.frames[len(f.frames)-1] }
+
+func (f *sqlFormatter) flush() {
+	if line := strings.TrimRight(f.line.String(), " "); line != "" {
+		f.out.WriteString(line)
+		f.out.WriteByte('\n')
+	}
+	f.line.Reset()
+	f.started = false
+}
+
+func (f *sqlFormatter) newline(indent int) {
+	f.flush()
+	f.line.WriteString(strings.Repeat(f.indentUnit, indent))
+}
+
+func (f *sqlFormatter) next(i int) sqlToken {
+	for j := i + 1; j < len(f.tokens); j++ {
+		if kind := f.tokens[j].kind; kind != sqlLineComment && kind != sqlBlockComment {
+			return f.tokens[j]
+		}
+	}
+	return sqlToken{}
+}
+
+func (f *sqlFormatter) word(tok sqlToken) string {
+	if tok.kind != sqlWord {
+		return ""
+	}
+	if upper := strings.ToUpper(tok.text); sqlKeywords[upper] {
+		return upper
+	}
+	return ""
+}
+
+func (f *sqlFormatter) emit(i int, tok sqlToken) {
+	frame := f.top()
+	keyword := f.word(tok)
+	prevKeyword := f.word(f.prev)
+	functionCall := f.next(i).text == "("
+
+	switch {
+	case tok.kind == sqlPunct && tok.text == ";":
+		f.write(tok)
+		f.flush()
+		f.out.WriteByte('\n')
+		f.frames = []sqlFrame{{subquery: true}}
+		f.prev = sqlToken{}
+		return
+	case tok.kind == sqlPunct && tok.text == ")":
+		if len(f.frames) > 1 {
+			closing := *frame
+			f.frames = f.frames[:len(f.frames)-1]
+			if closing.subquery {
+				f.newline(f.top().indent)
+				f.started = false
+			}
+		}
+	case keyword != "" && frame.subquery && sqlClauseKeywords[keyword] && !functionCall &&
+		!(keyword == "JOIN" && sqlJoinModifiers[prevKeyword]) &&
+		!(keyword == "FROM" && prevKeyword == "DELETE"):
+		if f.started {
+			f.newline(frame.indent)
+		}
+		frame.clause = keyword
+		frame.between = false
+	case (keyword == "AND" || keyword == "OR") && frame.subquery:
+		if keyword == "AND" && frame.between {
+			frame.between = false
+			break
+		}
+		f.newline(frame.indent + 1)
+	case keyword == "BETWEEN":
+		frame.between = true
+	}
+
+	f.write(tok)
+
+	switch {
+	case tok.kind == sqlPunct && tok.text == "(":
+		next := f.word(f.next(i))
+		subquery := next == "SELECT" || next == "WITH"
+		indent := frame.indent
+		if subquery {
+			indent++
+		}
+		f.frames = append(f.frames, sqlFrame{subquery: subquery, indent: indent})
+		if subquery {
+			f.newline(indent)
+		}
+	case tok.kind == sqlPunct && tok.text == "," && frame.subquery && (frame.clause == "SELECT" || frame.clause == "SET" || frame.clause == "WITH"):
+		f.newline(frame.indent + 1)
+	case tok.kind == sqlLineComment:
+		f.newline(frame.indent)
+	}
+	f.prev = tok
+}
+
+// write appends tok, deciding whether it needs a leading space.
+func (f *sqlFormatter) write(tok sqlToken) {
+	text := tok.text
+	if keyword := f.word(tok); keyword != "" {
+		switch f.keywordCase {
+		case "lower":
+			text = strings.ToLower(keyword)
+		case "preserve":
+		default:
+			text = keyword
+		}
+	}
+	if f.started && f.needsSpace(tok) {
+		f.line.WriteByte(' ')
+	}
+	f.line.WriteString(text)
+	f.started = true
+}
+
+func (f *sqlFormatter) needsSpace(tok sqlToken) bool {
+	prev := f.prev
+	if prev.kind == sqlPunct && (prev.text == "(" || prev.text == "." || prev.text == "::") {
+		return false
+	}
+	if tok.kind != sqlPunct {
+		return true
+	}
+	switch tok.text {
+	case ")", ",", ".", ";", "::":
+		return false
+	case "(":
+		if keyword := f.word(prev); keyword != "" {
+			// IN, VALUES, AS and the like take a space; CAST and the
+			// LEFT/RIGHT string functions are calls.
+			return keyword != "CAST" && keyword != "LEFT" && keyword != "RIGHT"
+		}
+		// Function calls hug their parenthesis, except for the column
+		// list of INSERT INTO t (...).
+		return f.top().clause == "INSERT" || (prev.kind != sqlWord && prev.kind != sqlQuoted)
+	}
+	return true
+}
+
+func (s *Server) callAkumaFormat(_ context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req akumaFormatRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.SQL) == "" {
+		return nil, fmt.Errorf("sql is required")
+	}
+	if req.Dialect == "" {
+		req.Dialect = "postgres"
+	}
+	if req.KeywordCase == "" {
+		req.KeywordCase = "upper"
+	}
+	if req.Indent == 0 {
+		req.Indent = 2
+	}
+	formatted, err := formatSQL(req.SQL, req.Dialect, req.KeywordCase, req.Indent)
+	if err != nil {
+		return nil, fmt.Errorf("cannot format sql: %w", err)
+	}
+	return map[string]interface{}{"sql": formatted, "dialect": req.Dialect}, nil
+}
+
+// renderAkumaFormat shows the formatted SQL as-is instead of as an escaped
+// JSON string.
+func renderAkumaFormat(_, data map[string]interface{}) ([]string, bool) {
+	sql, ok := data["sql"].(string)
+	return []string{sql}, ok
+}
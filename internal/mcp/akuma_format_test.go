@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFormatSQL(t *testing.T) {
+	cases := []struct {
+		name        string
+		sql         string
+		dialect     string
+		keywordCase string
+		want        string
+	}{
+		{
+			name: "clauses, select list, and conditions",
+			sql:  "select id, name from users u left join orders o on o.user_id = u.id where active = true and age between 18 and 65 group by id order by name desc limit 10",
+			want: "SELECT id,\n  name\nFROM users u\nLEFT JOIN orders o ON o.user_id = u.id\nWHERE active = TRUE\n  AND age BETWEEN 18 AND 65\nGROUP BY id\nORDER BY name DESC\nLIMIT 10",
+		},
+		{
+			name: "subquery",
+			sql:  "select id from users where id in (select user_id from bans where reason like '%spam%')",
+			want: "SELECT id\nFROM users\nWHERE id IN (\n  SELECT user_id\n  FROM bans\n  WHERE reason LIKE '%spam%'\n)",
+		},
+		{
+			name: "function calls and casts",
+			sql:  "select cast(x as int), left(name, 3), count(*), x::text from t",
+			want: "SELECT CAST(x AS int),\n  LEFT(name, 3),\n  count(*),\n  x::text\nFROM t",
+		},
+		{
+			name: "string literals and comments kept",
+			sql:  "select 'select from where' as s -- note\nfrom t where y = 'it''s'",
+			want: "SELECT 'select from where' AS s -- note\nFROM t\nWHERE y = 'it''s'",
+		},
+		{
+			name: "insert column list",
+			sql:  "insert into t (a, b) values (1, 2)",
+			want: "INSERT INTO t (a, b) VALUES (1, 2)",
+		},
+		{
+			name: "multiple statements",
+			sql:  "delete from t where id = 1; select 1",
+			want: "DELETE FROM t\nWHERE id = 1;\n\nSELECT 1",
+		},
+		{
+			name:        "lower keywords",
+			sql:         "SELECT a FROM t WHERE b IS NULL",
+			keywordCase: "lower",
+			want:        "select a\nfrom t\nwhere b is null",
+		},
+		{
+			name:        "preserve keywords",
+			sql:         "Select a From t",
+			keywordCase: "preserve",
+			want:        "Select a\nFrom t",
+		},
+		{
+			name:    "mysql backslash escapes and backticks",
+			sql:     "select `from` from t where s = 'a\\'b'",
+			dialect: "mysql",
+			want:    "SELECT `from`\nFROM t\nWHERE s = 'a\\'b'",
+		},
+		{
+			name: "exponents stay one number",
+			sql:  "select 1e-5, 2.5E+3, 0x1e-5 from t",
+			want: "SELECT 1e-5,\n  2.5E+3,\n  0x1e - 5\nFROM t",
+		},
+		{
+			name: "dollar-quoted text kept verbatim",
+			sql:  "select $$select  a   from  b$$ as body, $fn$ where  x $fn$ from t",
+			want: "SELECT $$select  a   from  b$$ AS body,\n  $fn$ where  x $fn$\nFROM t",
+		},
+		{
+			name: "bind parameters kept whole",
+			sql:  "select * from t where k = :key and n = @name and m = $1 and v = @@version and c = x::text",
+			want: "SELECT *\nFROM t\nWHERE k = :key\n  AND n = @name\n  AND m = $1\n  AND v = @@version\n  AND c = x::text",
+		},
+		{
+			name: "unary minus stays attached",
+			sql:  "select -1, x - 1, (-2.5e-3) from t where a = -1 and b in (-1, +2) and c = d -1",
+			want: "SELECT -1,\n  x - 1,\n  (-2.5e-3)\nFROM t\nWHERE a = -1\n  AND b IN (-1, +2)\n  AND c = d - 1",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.dialect == "" {
+				tc.dialect = "postgres"
+			}
+			if tc.keywordCase == "" {
+				tc.keywordCase = "upper"
+			}
+			got, err := formatSQL(tc.sql, tc.dialect, tc.keywordCase, 2)
+			if err != nil {
+				t.Fatalf("formatSQL: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatSQLRejectsUnterminatedQuote(t *testing.T) {
+	if _, err := formatSQL("select 'oops from t", "postgres", "upper", 2); err == nil || !strings.Contains(err.Error(), "unterminated") {
+		t.Fatalf("err = %v, want unterminated quote error", err)
+	}
+}
+
+func TestFormatSQLRejectsUnterminatedDollarQuote(t *testing.T) {
+	if _, err := formatSQL("select $tag$ oops $other$ from t", "postgres", "upper", 2); err == nil || !strings.Contains(err.Error(), "unterminated $tag$") {
+		t.Fatalf("err = %v, want unterminated dollar quote error", err)
+	}
+}
+
+func TestAkumaFormatMakesNoBackendCall(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+
+	result, err := s.callAkumaFormat(context.Background(), map[string]interface{}{"sql": "select a from t", "indent": float64(4)})
+	if err != nil {
+		t.Fatalf("callAkumaFormat: %v", err)
+	}
+	if result["sql"] != "SELECT a\nFROM t" || result["dialect"] != "postgres" {
+		t.Fatalf("result = %#v", result)
+	}
+	if len(captured) != 0 {
+		t.Fatalf("akuma.format reached the backend: %+v", captured)
+	}
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+// akumaGuardrails are the operator defaults from the config file's
+// defaultGuardrails. They are merged into the guardrails of every Akuma
+// query so an agent can tighten them but never loosen them.
+type akumaGuardrails struct {
+	// BlockedTables may not be read at all.
+	BlockedTables []string `json:"blockedTables,omitempty"`
+	// RequireWhereTables may only be queried with a WHERE clause.
+	RequireWhereTables []string `json:"requireWhereTables,omitempty"`
+	// MaxRows caps the rows a query may return.
+	MaxRows *int `json:"maxRows,omitempty"`
+}
+
+// akumaQueryPayload builds the akuma.query request with the configured
+// default guardrails applied.
+func (s *Server) akumaQueryPayload(args map[string]interface{}) (*kaizen.AkumaQueryRequest, error) {
+	req, err := buildAkumaQueryPayload(args)
+	if err != nil {
+		return nil, err
+	}
+	if s.config == nil || s.config.DefaultGuardrails == nil {
+		return req, nil
+	}
+	if req.Guardrails, err = mergeGuardrails(*s.config.DefaultGuardrails, req.Guardrails); err != nil {
+		return nil, err
+	}
+	if limit, ok := req.Guardrails["maxRows"].(int); ok && (req.MaxRows == nil || *req.MaxRows > limit) {
+		req.MaxRows = &limit
+	}
+	return req, nil
+}
+
+// mergeGuardrails combines operator defaults with the agent's guardrails:
+// table lists are unioned and maxRows takes the smaller cap. Guardrails
+// the server does not know are passed through from the agent unchanged.
+func mergeGuardrails(defaults akumaGuardrails, requested map[string]interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{}, len(requested)+3)
+	for key, value := range requested {
+		merged[key] = value
+	}
+	for _, list := range []struct {
+		key      string
+		defaults []string
+	}{
+		{"blockedTables", defaults.BlockedTables},
+		{"requireWhereTables", defaults.RequireWhereTables},
+	} {
+		tables, err := guardrailTables(requested, list.key)
+		if err != nil {
+			return nil, err
+		}
+		if union := unionTables(list.defaults, tables); len(union) > 0 {
+			merged[list.key] = union
+		}
+	}
+	limit, ok := numericToolArg(requested, "maxRows")
+	if _, present := requested["maxRows"]; present && !ok {
+		return nil, fmt.Errorf("guardrails.maxRows must be an integer")
+	}
+	if defaults.MaxRows != nil && (!ok || limit > *defaults.MaxRows) {
+		limit, ok = *defaults.MaxRows, true
+	}
+	if ok {
+		merged["maxRows"] = limit
+	}
+	return merged, nil
+}
+
+func guardrailTables(guardrails map[string]interface{}, key string) ([]string, error) {
+	raw, ok := guardrails[key]
+	if !ok {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("guardrails.%s must be an array of table names", key)
+	}
+	tables := make([]string, 0, len(items))
+	for _, item := range items {
+		table, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("guardrails.%s must be an array of table names", key)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+func unionTables(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var union []string
+	for _, list := range lists {
+		for _, table := range list {
+			if !seen[table] {
+				seen[table] = true
+				union = append(union, table)
+			}
+		}
+	}
+	sort.Strings(union)
+	return union
+}
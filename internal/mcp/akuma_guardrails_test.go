@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDefaultGuardrailsOnlyTighten(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/query": `{"sql":"SELECT 1"}`,
+	})
+	defer cleanup()
+	maxRows := 100
+	s.config = &serverConfig{DefaultGuardrails: &akumaGuardrails{
+		BlockedTables:      []string{"secrets", "payroll"},
+		RequireWhereTables: []string{"events"},
+		MaxRows:            &maxRows,
+	}}
+
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{
+			name: "defaults only",
+			args: map[string]interface{}{},
+			want: `{"dialect":"postgres","prompt":"p","maxRows":100,"guardrails":{"blockedTables":["payroll","secrets"],"maxRows":100,"requireWhereTables":["events"]}}`,
+		},
+		{
+			name: "agent cannot loosen",
+			args: map[string]interface{}{
+				"maxRows":    500.0,
+				"guardrails": map[string]interface{}{"blockedTables": []interface{}{}, "maxRows": 1000.0},
+			},
+			want: `{"dialect":"postgres","prompt":"p","maxRows":100,"guardrails":{"blockedTables":["payroll","secrets"],"maxRows":100,"requireWhereTables":["events"]}}`,
+		},
+		{
+			name: "agent can tighten",
+			args: map[string]interface{}{
+				"guardrails": map[string]interface{}{"blockedTables": []interface{}{"users"}, "maxRows": 10.0, "readOnly": true},
+			},
+			want: `{"dialect":"postgres","prompt":"p","maxRows":10,"guardrails":{"blockedTables":["payroll","secrets","users"],"maxRows":10,"readOnly":true,"requireWhereTables":["events"]}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			captured = nil
+			tc.args["dialect"], tc.args["prompt"] = "postgres", "p"
+			raw, _ := json.Marshal(toolsCallParams{Name: "akuma.query", Arguments: tc.args})
+			if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] == true {
+				t.Fatalf("unexpected tool error: %#v", result)
+			}
+			if len(captured) != 1 || captured[0].Body != tc.want {
+				t.Fatalf("payload = %+v\nwant %s", captured, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeGuardrailsRejectsMalformedAgentGuardrails(t *testing.T) {
+	for _, requested := range []map[string]interface{}{
+		{"blockedTables": "secrets"},
+		{"requireWhereTables": []interface{}{1.0}},
+		{"maxRows": "ten"},
+	} {
+		if _, err := mergeGuardrails(akumaGuardrails{BlockedTables: []string{"secrets"}}, requested); err == nil {
+			t.Fatalf("expected %v to be rejected", requested)
+		}
+	}
+}
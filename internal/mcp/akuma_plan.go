@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Query plans come back from /v1/akuma/explain (when includePlan is set)
+// as a tree of nodes:
+//
+//	{"operation": "Hash Join", "detail": "orders.user_id = users.id",
+//	 "estimatedRows": 1200, "estimatedCost": 34.5, "children": [...]}
+//
+// renderAkumaPlan turns that tree into an ASCII tree or a Mermaid
+// flowchart for the text content block; structuredContent keeps the raw
+// response either way.
+func renderAkumaPlan(args, data map[string]interface{}) ([]string, bool) {
+	format, _ := args["format"].(string)
+	plan, ok := data["plan"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	var b strings.Builder
+	if explanation, _ := data["explanation"].(string); explanation != "" {
+		b.WriteString(explanation)
+		b.WriteString("\n\n")
+	}
+	switch format {
+	case "ascii":
+		b.WriteString(planNodeLabel(plan))
+		b.WriteString("\n")
+		writeASCIIPlan(&b, plan, "")
+	case "mermaid":
+		b.WriteString("```mermaid\nflowchart TD\n")
+		next := 0
+		writeMermaidPlan(&b, plan, &next)
+		b.WriteString("```\n")
+	default:
+		return nil, false
+	}
+	return []string{strings.TrimRight(b.String(), "\n")}, true
+}
+
+func writeASCIIPlan(b *strings.Builder, node map[string]interface{}, prefix string) {
+	children := planChildren(node)
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(b, "%s%s%s\n", prefix, branch, planNodeLabel(child))
+		writeASCIIPlan(b, child, prefix+indent)
+	}
+}
+
+func writeMermaidPlan(b *strings.Builder, node map[string]interface{}, next *int) int {
+	id := *next
+	*next++
+	label := strings.ReplaceAll(planNodeLabel(node), `"`, "#quot;")
+	fmt.Fprintf(b, "  n%d[\"%s\"]\n", id, label)
+	for _, child := range planChildren(node) {
+		childID := writeMermaidPlan(b, child, next)
+		fmt.Fprintf(b, "  n%d --> n%d\n", id, childID)
+	}
+	return id
+}
+
+func planChildren(node map[string]interface{}) []map[string]interface{} {
+	raw, _ := node["children"].([]interface{})
+	children := make([]map[string]interface{}, 0, len(raw))
+	for _, item := range raw {
+		if child, ok := item.(map[string]interface{}); ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func planNodeLabel(node map[string]interface{}) string {
+	label, _ := node["operation"].(string)
+	if label == "" {
+		label = "?"
+	}
+	if detail, _ := node["detail"].(string); detail != "" {
+		label += " " + detail
+	}
+	var stats []string
+	if rows, ok := node["estimatedRows"].(float64); ok {
+		stats = append(stats, "rows="+formatQueryNumber(rows))
+	}
+	if cost, ok := node["estimatedCost"].(float64); ok {
+		stats = append(stats, "cost="+formatQueryNumber(cost))
+	}
+	if len(stats) > 0 {
+		label += " (" + strings.Join(stats, ", ") + ")"
+	}
+	return label
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testExplainResponse = `{
+  "explanation": "Joins orders to users.",
+  "plan": {
+    "operation": "Hash Join", "detail": "orders.user_id = users.id", "estimatedRows": 1200, "estimatedCost": 34.5,
+    "children": [
+      {"operation": "Seq Scan", "detail": "on orders", "children": [{"operation": "Filter", "detail": "status = \"paid\""}]},
+      {"operation": "Index Scan", "detail": "on users"}
+    ]
+  }
+}`
+
+func TestHandleToolCallAkumaExplainRendersASCIIPlan(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{"POST /v1/akuma/explain": testExplainResponse})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.explain", Arguments: map[string]interface{}{"sql": "SELECT 1", "format": "ascii"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if captured[0].Body != `{"sql":"SELECT 1","includePlan":true}` {
+		t.Fatalf("expected plan to be requested, got %s", captured[0].Body)
+	}
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	want := strings.Join([]string{
+		"Joins orders to users.",
+		"",
+		"Hash Join orders.user_id = users.id (rows=1200, cost=34.5)",
+		"├── Seq Scan on orders",
+		"│   └── Filter status = \"paid\"",
+		"└── Index Scan on users",
+	}, "\n")
+	if text != want {
+		t.Fatalf("unexpected ascii plan:\n%s\nwant:\n%s", text, want)
+	}
+	if _, ok := result.(map[string]interface{})["structuredContent"].(map[string]interface{})["plan"]; !ok {
+		t.Fatalf("expected structuredContent to keep the raw plan")
+	}
+}
+
+func TestRenderAkumaPlanMermaid(t *testing.T) {
+	var data map[string]interface{}
+	_ = json.Unmarshal([]byte(testExplainResponse), &data)
+	texts, ok := renderAkumaPlan(map[string]interface{}{"format": "mermaid"}, data)
+	if !ok || len(texts) != 1 {
+		t.Fatalf("expected one mermaid text block, got %q", texts)
+	}
+	text := texts[0]
+	for _, want := range []string{
+		"```mermaid\nflowchart TD\n",
+		`n0["Hash Join orders.user_id = users.id (rows=1200, cost=34.5)"]`,
+		"n0 --> n1",
+		"n1 --> n2",
+		"n0 --> n3",
+		`n2["Filter status = #quot;paid#quot;"]`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderAkumaPlanFallsBackWithoutPlan(t *testing.T) {
+	if _, ok := renderAkumaPlan(map[string]interface{}{"format": "ascii"}, map[string]interface{}{"explanation": "x"}); ok {
+		t.Fatalf("expected fallback when the response has no plan")
+	}
+	if _, ok := renderAkumaPlan(map[string]interface{}{}, map[string]interface{}{"plan": map[string]interface{}{}}); ok {
+		t.Fatalf("expected fallback for prose format")
+	}
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+// callAkumaGenerateAndCheck generates SQL and then validates it and
+// estimates its cost, so agents get the checks in the same round trip as
+// the query instead of skipping them. A failed check is reported in its
+// section of the result rather than failing the call, since the generated
+// SQL is still useful.
+func (s *Server) callAkumaGenerateAndCheck(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req, err := s.akumaQueryPayload(args)
+	if err != nil {
+		return nil, err
+	}
+	req.Mode = "sql-only"
+	reportProgress(ctx, 0, 3)
+	client := s.kaizenClient(ctx)
+	generated, err := client.AkumaQuery(ctx, *req)
+	if err != nil {
+		return nil, err
+	}
+	sql := generated.SQL
+	if strings.TrimSpace(sql) == "" {
+		return nil, fmt.Errorf("akuma.query returned no SQL to check")
+	}
+
+	check := kaizen.AkumaSQLRequest{SQL: sql, Dialect: req.Dialect, SourceID: req.SourceID}
+	result := map[string]interface{}{"sql": sql, "query": generated.Fields()}
+	for i, step := range []struct {
+		key  string
+		call func() (map[string]interface{}, error)
+	}{
+		{"validation", func() (map[string]interface{}, error) { return fieldsOf(client.AkumaValidate(ctx, check)) }},
+		{"cost", func() (map[string]interface{}, error) { return fieldsOf(client.AkumaCost(ctx, check)) }},
+	} {
+		reportProgress(ctx, float64(i+1), 3)
+		data, err := step.call()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			data = map[string]interface{}{"error": err.Error()}
+		}
+		result[step.key] = data
+	}
+	reportProgress(ctx, 3, 3)
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAkumaGenerateAndCheckRunsAllSteps(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/query":    `{"sql":"select * from orders"}`,
+		"POST /v1/akuma/validate": `{"valid":true,"diagnostics":[]}`,
+		"POST /v1/akuma/cost":     `{"bytesScanned":1024,"estimatedCostUsd":0.01}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.generate_and_check", Arguments: map[string]interface{}{
+		"dialect":  "bigquery",
+		"prompt":   "all orders",
+		"sourceId": "warehouse",
+	}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	resultMap := result.(map[string]interface{})
+	if resultMap["isError"] == true {
+		t.Fatalf("unexpected tool error: %#v", resultMap)
+	}
+
+	if len(captured) != 3 {
+		t.Fatalf("expected 3 backend calls, got %+v", captured)
+	}
+	if !strings.Contains(captured[0].Body, `"mode":"sql-only"`) {
+		t.Fatalf("query should request sql-only mode: %s", captured[0].Body)
+	}
+	for i, path := range []string{"/v1/akuma/validate", "/v1/akuma/cost"} {
+		req := captured[i+1]
+		if req.Path != path {
+			t.Fatalf("call %d path = %s, want %s", i+1, req.Path, path)
+		}
+		var body map[string]interface{}
+		_ = json.Unmarshal([]byte(req.Body), &body)
+		if body["sql"] != "select * from orders" || body["dialect"] != "bigquery" || body["sourceId"] != "warehouse" {
+			t.Fatalf("unexpected check payload for %s: %s", path, req.Body)
+		}
+	}
+
+	structured := resultMap["structuredContent"].(map[string]interface{})
+	if structured["sql"] != "select * from orders" {
+		t.Fatalf("sql = %#v", structured["sql"])
+	}
+	if cost, _ := structured["cost"].(map[string]interface{}); cost["bytesScanned"] != float64(1024) {
+		t.Fatalf("cost = %#v", structured["cost"])
+	}
+}
+
+func TestAkumaGenerateAndCheckReportsFailedCheckInline(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/akuma/query":
+			_, _ = w.Write([]byte(`{"sql":"select 1"}`))
+		case "/v1/akuma/validate":
+			_, _ = w.Write([]byte(`{"valid":true}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"cost estimates are not supported for postgres"}`))
+		}
+	}))
+	defer hs.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "test-key", httpClient: hs.Client()}}
+
+	data, err := s.callAkumaGenerateAndCheck(context.Background(), map[string]interface{}{"dialect": "postgres", "prompt": "one"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cost, _ := data["cost"].(map[string]interface{})
+	if _, ok := cost["error"].(string); !ok {
+		t.Fatalf("expected inline cost error, got %#v", data["cost"])
+	}
+	if validation, _ := data["validation"].(map[string]interface{}); validation["valid"] != true {
+		t.Fatalf("validation = %#v", data["validation"])
+	}
+}
+
+func TestAkumaGenerateAndCheckRequiresSQL(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/query": `{"explanation":"nothing to do"}`,
+	})
+	defer cleanup()
+
+	_, err := s.callAkumaGenerateAndCheck(context.Background(), map[string]interface{}{"dialect": "postgres", "prompt": "x"})
+	if err == nil || !strings.Contains(err.Error(), "no SQL") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(captured) != 1 {
+		t.Fatalf("checks should not run without SQL: %+v", captured)
+	}
+}
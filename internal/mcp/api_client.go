@@ -3,89 +3,352 @@ package mcp
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
 )
 
+// deadlineHeader carries the remaining tool-call budget in milliseconds.
+const deadlineHeader = "X-Kaizen-Deadline-Ms"
+
 type kaizenAPIClient struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	// timeout is the client-wide backend deadline. It is applied per call
+	// through the context rather than http.Client.Timeout so per-tool
+	// overrides can be longer than the default.
+	timeout    time.Duration
+	middleware []ClientMiddleware
+	// hedgeDelay enables request hedging for GETs: when the first attempt
+	// hasn't answered after this delay, a second identical attempt is sent
+	// and whichever succeeds first wins. Zero disables hedging.
+	hedgeDelay time.Duration
+	// signer replaces bearer auth with AWS SigV4 signing when
+	// KAIZEN_API_AUTH_MODE=sigv4.
+	signer *sigV4Signer
+	// failover holds every configured base URL when KAIZEN_API_BASE_URL
+	// lists more than one; baseURL is then only the first entry.
+	failover *endpointPool
+	// userAgentSuffix identifies the install (team, cluster, client) to
+	// backend analytics; it is appended to the kaizen-mcp product token.
+	userAgentSuffix string
+	// workspace is sent as X-Kaizen-Workspace unless the call overrides it.
+	workspace string
+	// passthroughAuth marks that requests authenticate with the HTTP
+	// caller's bearer token (KAIZEN_API_PASSTHROUGH_AUTH).
+	passthroughAuth bool
 }
 
-func newKaizenAPIClient() *kaizenAPIClient {
-	baseURL := strings.TrimRight(getEnv("KAIZEN_API_BASE_URL", "http://localhost:8080"), "/")
+func newKaizenAPIClient() (*kaizenAPIClient, error) {
+	var baseURLs []string
+	unixSockets := map[string]string{}
+	for i, raw := range strings.Split(getEnv("KAIZEN_API_BASE_URL", "http://localhost:8080"), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		baseURL, socketHost, socketPath, err := resolveBaseURL(raw, i)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KAIZEN_API_BASE_URL: %w", err)
+		}
+		if socketPath != "" {
+			unixSockets[socketHost] = socketPath
+		}
+		baseURLs = append(baseURLs, baseURL)
+	}
+	if len(baseURLs) == 0 {
+		return nil, fmt.Errorf("invalid KAIZEN_API_BASE_URL: no base URL given")
+	}
+	var failover *endpointPool
+	if len(baseURLs) > 1 {
+		failover = &endpointPool{urls: baseURLs}
+	}
+	var err error
+	timeout := defaultAPITimeout
+	if raw := getEnv("KAIZEN_API_TIMEOUT", ""); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid KAIZEN_API_TIMEOUT %q: expected a positive duration such as 60s", raw)
+		}
+		timeout = parsed
+	}
+	var hedgeDelay time.Duration
+	if raw := getEnv("KAIZEN_API_HEDGE_DELAY", ""); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid KAIZEN_API_HEDGE_DELAY %q: expected a positive duration such as 500ms", raw)
+		}
+		hedgeDelay = parsed
+	}
+	var signer *sigV4Signer
+	switch mode := getEnv("KAIZEN_API_AUTH_MODE", "bearer"); mode {
+	case "bearer":
+	case "sigv4":
+		if signer, err = newSigV4Signer(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid KAIZEN_API_AUTH_MODE %q: expected bearer or sigv4", mode)
+	}
+	userAgentSuffix := getEnv("KAIZEN_API_USER_AGENT_SUFFIX", "")
+	if strings.ContainsAny(userAgentSuffix, "\r\n") {
+		return nil, fmt.Errorf("invalid KAIZEN_API_USER_AGENT_SUFFIX: must be a single line")
+	}
+	workspace := getEnv("KAIZEN_WORKSPACE", "")
+	if err := validateWorkspace("KAIZEN_WORKSPACE", workspace); err != nil {
+		return nil, err
+	}
+	transport, err := newAPITransport(unixSockets)
+	if err != nil {
+		return nil, err
+	}
 	return &kaizenAPIClient{
-		baseURL: baseURL,
-		apiKey:  os.Getenv("KAIZEN_API_KEY"),
+		baseURL:  baseURLs[0],
+		failover: failover,
+		apiKey:   os.Getenv("KAIZEN_API_KEY"),
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Transport: transport,
 		},
-	}
+		timeout:    timeout,
+		hedgeDelay: hedgeDelay,
+		signer:     signer,
+
+		userAgentSuffix: userAgentSuffix,
+		workspace:       workspace,
+	}, nil
 }
 
 func (c *kaizenAPIClient) call(ctx context.Context, method, path string, payload interface{}) (map[string]interface{}, error) {
-	if strings.TrimSpace(c.apiKey) == "" {
-		return nil, fmt.Errorf("KAIZEN_API_KEY is not set")
+	return fieldsOf(c.typed().Do(ctx, method, path, payload))
+}
+
+// typed returns a kaizen.Client that sends its requests through c.
+func (c *kaizenAPIClient) typed() *kaizen.Client {
+	return kaizen.New(kaizen.TransportFunc(c.fetch))
+}
+
+// fetch is call for endpoints that answer with a file (CSV, Parquet, ...)
+// rather than a JSON object. Error responses are still decoded as JSON.
+func (c *kaizenAPIClient) fetch(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	status, respBody, err := c.exchange(ctx, method, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	if status >= 400 {
+		decoded := map[string]interface{}{}
+		_ = json.Unmarshal(respBody, &decoded)
+		return nil, newAPICallError(status, decoded)
 	}
+	return respBody, nil
+}
 
-	var body io.Reader
+// exchange encodes payload, applies the default deadline when the caller
+// set none, and performs the request with hedging or failover as
+// configured.
+func (c *kaizenAPIClient) exchange(ctx context.Context, method, path string, payload interface{}) (int, []byte, error) {
+	if !c.hasCredentials(ctx) {
+		return 0, nil, c.missingCredentials()
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.defaultTimeout())
+		defer cancel()
+	}
+
+	var raw []byte
 	if payload != nil {
-		raw, err := json.Marshal(payload)
+		encoded, err := json.Marshal(payload)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+			return 0, nil, fmt.Errorf("failed to marshal request payload: %w", err)
 		}
-		body = bytes.NewBuffer(raw)
+		raw = encoded
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if method == http.MethodGet && c.hedgeDelay > 0 {
+		return c.doHedged(ctx, method, path, raw)
 	}
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("User-Agent", fmt.Sprintf("%s/%s", serverName, serverVersion))
-	if payload != nil && method != http.MethodGet {
-		req.Header.Set("Content-Type", "application/json")
+	return c.do(ctx, method, path, raw)
+}
+
+// newAPICallError builds the error for a non-2xx response, preferring the
+// backend's own message and error code over a generic one.
+func newAPICallError(status int, decoded map[string]interface{}) *apiCallError {
+	apiErr := &apiCallError{Status: status, Body: decoded}
+	msg := "Kaizen API request failed"
+	switch v := decoded["error"].(type) {
+	case string:
+		if v != "" {
+			msg = v
+		}
+	case map[string]interface{}:
+		apiErr.Code, _ = v["code"].(string)
+		apiErr.Fields = parseErrorFields(v["fields"])
+		if text, _ := v["message"].(string); text != "" {
+			msg = text
+		}
+		if apiErr.Code != "" {
+			msg = apiErr.Code + ": " + msg
+		}
+	}
+	apiErr.Msg = fmt.Sprintf("%s (status=%d)%s", msg, status, formatErrorFields(apiErr.Fields))
+	return apiErr
+}
+
+// do sends one request attempt and reads the full response body. With
+// several base URLs configured the attempt fails over across them.
+func (c *kaizenAPIClient) do(ctx context.Context, method, path string, raw []byte) (int, []byte, error) {
+	if c.failover != nil {
+		return c.failover.do(ctx, func(baseURL string) (int, []byte, error) {
+			return c.send(ctx, baseURL, method, path, raw)
+		})
+	}
+	return c.send(ctx, c.baseURL, method, path, raw)
+}
+
+// send makes a single HTTP request against baseURL.
+func (c *kaizenAPIClient) send(ctx context.Context, baseURL, method, path string, raw []byte) (int, []byte, error) {
+	req, err := c.newRequest(ctx, baseURL, method, path, raw)
+	if err != nil {
+		return 0, nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.roundTrip()(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return 0, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return 0, nil, fmt.Errorf("failed to read response: %w", err)
 	}
-
-	var decoded map[string]interface{}
-	if len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, &decoded); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
+	if resp.StatusCode < http.StatusBadRequest {
+		if err := confirmDryRun(ctx, resp.Header, respBody); err != nil {
+			return 0, nil, err
 		}
-	} else {
-		decoded = map[string]interface{}{}
 	}
+	return resp.StatusCode, respBody, nil
+}
 
-	if resp.StatusCode >= 400 {
-		msg := "Kaizen API request failed"
-		if v, ok := decoded["error"].(string); ok && v != "" {
-			msg = v
-		}
-		return nil, &apiCallError{
-			Status: resp.StatusCode,
-			Body:   decoded,
-			Msg:    fmt.Sprintf("%s (status=%d)", msg, resp.StatusCode),
+// newRequest builds a Kaizen API request with auth, user agent, deadline,
+// and idempotency headers set.
+func (c *kaizenAPIClient) newRequest(ctx context.Context, baseURL, method, path string, raw []byte) (*http.Request, error) {
+	var body io.Reader
+	if raw != nil {
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if credential := c.credential(ctx); c.signer == nil && credential != "" {
+		req.Header.Set("Authorization", "Bearer "+credential)
+	}
+	req.Header.Set("User-Agent", c.userAgent())
+	if raw != nil && method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Tell the backend how long the MCP side will wait so it can abandon
+	// work whose result would arrive after the tool call has timed out.
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline).Milliseconds()
+		if remaining < 1 {
+			remaining = 1
 		}
+		req.Header.Set(deadlineHeader, strconv.FormatInt(remaining, 10))
+	}
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" && (method == http.MethodPost || method == http.MethodPatch) {
+		req.Header.Set("Idempotency-Key", key)
 	}
+	if isDryRun(ctx) {
+		req.Header.Set(dryRunHeader, "true")
+	}
+	if workspace := c.workspaceFor(ctx); workspace != "" {
+		req.Header.Set(workspaceHeader, workspace)
+	}
+	if id := requestIDFrom(ctx); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+	if currency := requestedCurrency(ctx); currency != "" {
+		req.Header.Set(currencyHeader, currency)
+	}
+	return req, nil
+}
+
+type callerCredentialContextKey struct{}
 
-	return decoded, nil
+// withCallerCredential makes the Kaizen API client authenticate as the MCP
+// caller (HTTP transport with KAIZEN_API_PASSTHROUGH_AUTH) instead of with
+// the shared KAIZEN_API_KEY.
+func withCallerCredential(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, callerCredentialContextKey{}, token)
+}
+
+func (c *kaizenAPIClient) credential(ctx context.Context) string {
+	if token, ok := ctx.Value(callerCredentialContextKey{}).(string); ok && token != "" {
+		return token
+	}
+	return c.apiKey
+}
+
+// missingCredentials explains why hasCredentials failed: under
+// passthrough auth it is the caller's token that is missing, not the
+// shared key.
+func (c *kaizenAPIClient) missingCredentials() error {
+	if c != nil && c.passthroughAuth {
+		return fmt.Errorf("no caller credential: the Authorization header is missing (KAIZEN_API_PASSTHROUGH_AUTH requires a bearer token on each request)")
+	}
+	return fmt.Errorf("KAIZEN_API_KEY is not set")
+}
+
+// hasCredentials reports whether requests in ctx can authenticate.
+func (c *kaizenAPIClient) hasCredentials(ctx context.Context) bool {
+	return c != nil && (c.signer != nil || strings.TrimSpace(c.credential(ctx)) != "")
+}
+
+type idempotencyKeyContextKey struct{}
+
+// withIdempotencyKey attaches one key per logical tool call. Every POST or
+// PATCH made on behalf of that call (including any retry) sends the same
+// Idempotency-Key so the backend can dedupe, e.g. sozo.generate quota.
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func newIdempotencyKey() string {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		// crypto/rand only fails when the OS entropy source is broken;
+		// sending no key is safer than sending a predictable one.
+		return ""
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+func (c *kaizenAPIClient) userAgent() string {
+	ua := fmt.Sprintf("%s/%s", serverName, serverVersion)
+	if c.userAgentSuffix != "" {
+		ua += " " + c.userAgentSuffix
+	}
+	return ua
+}
+
+func (c *kaizenAPIClient) defaultTimeout() time.Duration {
+	if c.timeout > 0 {
+		return c.timeout
+	}
+	return defaultAPITimeout
 }
 
 // apiCallError lets dispatchers recover the typed response body for
@@ -93,14 +356,62 @@ func (c *kaizenAPIClient) call(ctx context.Context, method, path string, payload
 // 429 {status:"dropped",triggeredBy:...} and 409 {status:"stale"} on the
 // 8.2-public live-pricing surface). Callers that need the body do
 // errors.As + check Status; callers that don't see only the err string.
+//
+// Structured bodies of the form {"error":{"code","message","fields"}}
+// populate Code and Fields, and the per-field messages are folded into Msg
+// so agents reading only the tool error text can still self-correct.
 type apiCallError struct {
 	Status int
 	Body   map[string]interface{}
 	Msg    string
+	Code   string
+	Fields map[string]string
 }
 
 func (e *apiCallError) Error() string { return e.Msg }
 
+// parseErrorFields accepts both {"field": "msg"} and {"field": ["msg", ...]}.
+func parseErrorFields(raw interface{}) map[string]string {
+	entries, ok := raw.(map[string]interface{})
+	if !ok || len(entries) == 0 {
+		return nil
+	}
+	fields := make(map[string]string, len(entries))
+	for name, value := range entries {
+		switch v := value.(type) {
+		case string:
+			fields[name] = v
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, item := range v {
+				if text, ok := item.(string); ok {
+					parts = append(parts, text)
+				}
+			}
+			fields[name] = strings.Join(parts, "; ")
+		default:
+			fields[name] = fmt.Sprint(v)
+		}
+	}
+	return fields
+}
+
+func formatErrorFields(fields map[string]string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n- %s: %s", name, fields[name])
+	}
+	return b.String()
+}
+
 func getEnv(key, fallback string) string {
 	if val := strings.TrimSpace(os.Getenv(key)); val != "" {
 		return val
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKaizenAPIClientParsesStructuredErrorBody(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"validation_failed","message":"request is invalid","fields":{"maxRows":"must be positive","dialect":["unsupported dialect","must be lowercase"]}}}`))
+	}))
+	defer hs.Close()
+
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}
+	_, err := client.call(context.Background(), http.MethodPost, "/v1/akuma/query", map[string]interface{}{})
+	var apiErr *apiCallError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected apiCallError, got %v", err)
+	}
+	if apiErr.Code != "validation_failed" || apiErr.Fields["maxRows"] != "must be positive" {
+		t.Fatalf("unexpected parsed error: %+v", apiErr)
+	}
+	want := "validation_failed: request is invalid (status=400)\n- dialect: unsupported dialect; must be lowercase\n- maxRows: must be positive"
+	if err.Error() != want {
+		t.Fatalf("unexpected error text:\n%s\nwant:\n%s", err.Error(), want)
+	}
+}
+
+func TestKaizenAPIClientKeepsStringErrorBody(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":"admin key required"}`))
+	}))
+	defer hs.Close()
+
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}
+	_, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/pricing/models", nil)
+	if err == nil || err.Error() != "admin key required (status=403)" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestKaizenAPIClientSendsRemainingDeadline(t *testing.T) {
+	var got string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(deadlineHeader)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.call(ctx, http.MethodGet, "/v1/enzan/burn", nil); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	ms, err := strconv.Atoi(got)
+	if err != nil || ms <= 0 || ms > 5000 {
+		t.Fatalf("expected remaining deadline in (0, 5000], got %q", got)
+	}
+
+	// Without a caller deadline the client-wide timeout still bounds the call.
+	client.timeout = 2 * time.Second
+	if _, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if ms, err := strconv.Atoi(got); err != nil || ms <= 0 || ms > 2000 {
+		t.Fatalf("expected client timeout as deadline, got %q", got)
+	}
+}
+
+func TestNewKaizenAPIClientAppendsUserAgentSuffix(t *testing.T) {
+	var got string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", hs.URL)
+	t.Setenv("KAIZEN_API_KEY", "k")
+	t.Setenv("KAIZEN_API_USER_AGENT_SUFFIX", "team-data/cluster-eu1")
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	if _, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if want := serverName + "/" + serverVersion + " team-data/cluster-eu1"; got != want {
+		t.Fatalf("User-Agent = %q, want %q", got, want)
+	}
+}
+
+func TestKaizenAPIClientReportsTheMissingCredential(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		client *kaizenAPIClient
+		want   string
+	}{
+		{"shared key", &kaizenAPIClient{baseURL: "http://127.0.0.1:1"}, "KAIZEN_API_KEY is not set"},
+		{"passthrough", &kaizenAPIClient{baseURL: "http://127.0.0.1:1", passthroughAuth: true}, "Authorization header is missing"},
+	} {
+		_, err := tc.client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// endpointPool fails over across the base URLs listed in
+// KAIZEN_API_BASE_URL. Each request starts at the last endpoint that
+// answered successfully and moves down the list on connection errors or
+// 5xx responses, so a dead primary costs one failed attempt rather than
+// one per call.
+type endpointPool struct {
+	urls      []string
+	preferred atomic.Int32
+}
+
+// current returns the endpoint new requests start at.
+func (p *endpointPool) current() string {
+	return p.urls[int(p.preferred.Load())%len(p.urls)]
+}
+
+func (p *endpointPool) do(ctx context.Context, attempt func(baseURL string) (int, []byte, error)) (int, []byte, error) {
+	start := int(p.preferred.Load())
+	var (
+		status int
+		body   []byte
+		err    error
+	)
+	for i := range p.urls {
+		index := (start + i) % len(p.urls)
+		status, body, err = attempt(p.urls[index])
+		if err == nil && status < http.StatusInternalServerError {
+			p.preferred.Store(int32(index))
+			return status, body, nil
+		}
+		// The caller gave up; trying another endpoint cannot help.
+		if ctx.Err() != nil {
+			break
+		}
+		// The endpoint may have applied the change; another could apply
+		// it twice.
+		var ignored *dryRunIgnoredError
+		if errors.As(err, &ignored) {
+			break
+		}
+	}
+	return status, body, err
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewKaizenAPIClientFailsOverAndPrefersLastGood(t *testing.T) {
+	var primaryHits, secondaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryHits.Add(1)
+		if got := r.Header.Get("Idempotency-Key"); got != "key-1" {
+			t.Errorf("expected idempotency key to be reused across endpoints, got %q", got)
+		}
+		_, _ = w.Write([]byte(`{"via":"secondary"}`))
+	}))
+	defer secondary.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", primary.URL+", "+secondary.URL)
+	t.Setenv("KAIZEN_API_KEY", "k")
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+
+	ctx := withIdempotencyKey(context.Background(), "key-1")
+	for i := 0; i < 2; i++ {
+		data, err := client.call(ctx, http.MethodPost, "/v1/sozo/generate", map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+		if data["via"] != "secondary" {
+			t.Fatalf("call %d: unexpected response %#v", i, data)
+		}
+	}
+	if primaryHits.Load() != 1 || secondaryHits.Load() != 2 {
+		t.Fatalf("expected the second call to go straight to the last-known-good endpoint, got primary=%d secondary=%d", primaryHits.Load(), secondaryHits.Load())
+	}
+}
+
+func TestNewKaizenAPIClientFailsOverOnConnectionError(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", "http://127.0.0.1:1,"+api.URL)
+	t.Setenv("KAIZEN_API_KEY", "k")
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	data, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil || data["ok"] != true {
+		t.Fatalf("expected failover to succeed, got %#v, %v", data, err)
+	}
+}
+
+func TestNewKaizenAPIClientReturnsLastFailureWhenAllEndpointsFail(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"error":"upstream down"}`))
+	}))
+	defer down.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", "http://127.0.0.1:1,"+down.URL)
+	t.Setenv("KAIZEN_API_KEY", "k")
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	_, err = client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err == nil || err.Error() != "upstream down (status=502)" {
+		t.Fatalf("expected last endpoint's error, got %v", err)
+	}
+}
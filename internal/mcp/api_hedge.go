@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type hedgeAttempt struct {
+	status int
+	body   []byte
+	err    error
+}
+
+// doHedged sends a read-only request and, if it hasn't completed within
+// hedgeDelay, fires one more identical attempt. The first attempt that
+// returns a non-5xx response wins and the other is cancelled. Failures are
+// never retried here: if the only in-flight attempt fails, that failure is
+// returned as-is.
+func (c *kaizenAPIClient) doHedged(ctx context.Context, method, path string, raw []byte) (int, []byte, error) {
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeAttempt, 2)
+	launch := func() {
+		go func() {
+			// A panic here, in the client or its middleware, would kill
+			// the process: no tool call recovery covers this goroutine.
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					results <- hedgeAttempt{err: recoveredPanic(recovered)}
+				}
+			}()
+			status, body, err := c.do(attemptCtx, method, path, raw)
+			results <- hedgeAttempt{status: status, body: body, err: err}
+		}()
+	}
+
+	launch()
+	pending, hedged := 1, false
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	var firstFailure *hedgeAttempt
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				pending++
+				launch()
+			}
+		case result := <-results:
+			pending--
+			if result.err == nil && result.status < http.StatusInternalServerError {
+				return result.status, result.body, nil
+			}
+			if firstFailure == nil {
+				firstFailure = &result
+			}
+			if pending == 0 {
+				return firstFailure.status, firstFailure.body, firstFailure.err
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgedGetTakesFirstSuccess(t *testing.T) {
+	var attempts int32
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// Simulate the slow replica: hold until the hedge wins and the
+			// client cancels this attempt.
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"burnRateUSDPerHour":4.2}`))
+	}))
+	defer hs.Close()
+
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client(), hedgeDelay: 20 * time.Millisecond}
+	start := time.Now()
+	data, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if data["burnRateUSDPerHour"] != 4.2 {
+		t.Fatalf("unexpected response: %#v", data)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("hedged call took too long: %v", elapsed)
+	}
+}
+
+func TestHedgingSkipsFastResponsesAndNonGetMethods(t *testing.T) {
+	var attempts int32
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		if r.Method == http.MethodPost {
+			time.Sleep(60 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client(), hedgeDelay: 20 * time.Millisecond}
+	if _, err := client.call(context.Background(), http.MethodPost, "/v1/sozo/generate", map[string]interface{}{}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("POST must never be hedged, got %d attempts", got)
+	}
+
+	client.hedgeDelay = time.Second
+	if _, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("fast GET should not be hedged, got %d total attempts", got)
+	}
+}
+
+func TestHedgedGetReturnsFailureWhenAllAttemptsFail(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"error":"replica down"}`))
+	}))
+	defer hs.Close()
+
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client(), hedgeDelay: 10 * time.Millisecond}
+	_, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err == nil || err.Error() != "replica down (status=502)" {
+		t.Fatalf("expected 502 failure, got %v", err)
+	}
+}
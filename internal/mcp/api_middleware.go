@@ -0,0 +1,40 @@
+package mcp
+
+import "net/http"
+
+// RoundTripFunc sends one Kaizen API request and returns the raw response.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// ClientMiddleware wraps the Kaizen API round trip so deployments can add
+// headers, sign requests, record metrics, or rewrite payloads without
+// forking the client. A middleware may mutate the request (including
+// replacing its Body) before calling next, and inspect or replace the
+// response afterwards.
+type ClientMiddleware func(next RoundTripFunc) RoundTripFunc
+
+// UseClientMiddleware appends middleware to the Kaizen API client. The
+// first middleware registered is the outermost one. The chain applies to
+// every configured profile. Register middleware before calling Serve; the
+// chain is not safe to modify concurrently.
+func (s *Server) UseClientMiddleware(middleware ...ClientMiddleware) {
+	s.client.middleware = append(s.client.middleware, middleware...)
+	for _, client := range s.profiles {
+		if client != s.client {
+			client.middleware = append(client.middleware, middleware...)
+		}
+	}
+}
+
+// roundTrip composes the middleware chain around the underlying HTTP client.
+// Request signing, when enabled, always runs innermost so it covers any
+// headers or body rewritten by middleware.
+func (c *kaizenAPIClient) roundTrip() RoundTripFunc {
+	next := RoundTripFunc(c.httpClient.Do)
+	if c.signer != nil {
+		next = c.signer.wrap(next)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next
+}
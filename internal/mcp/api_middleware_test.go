@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientMiddlewareRunsInRegistrationOrder(t *testing.T) {
+	var gotHeader, gotBody string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Trace")
+		raw, _ := io.ReadAll(r.Body)
+		gotBody = string(raw)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer hs.Close()
+
+	srv := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}}
+	var order []string
+	tag := func(name string) ClientMiddleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Set("X-Trace", req.Header.Get("X-Trace")+name)
+				return next(req)
+			}
+		}
+	}
+	rewrite := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			body := []byte(`{"rewritten":true}`)
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+			return next(req)
+		}
+	}
+	srv.UseClientMiddleware(tag("a"), tag("b"), rewrite)
+
+	data, err := srv.client.call(context.Background(), http.MethodPost, "/v1/enzan/summary", map[string]interface{}{"window": "24h"})
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if data["ok"] != true {
+		t.Fatalf("unexpected response: %#v", data)
+	}
+	if strings.Join(order, ",") != "a,b" || gotHeader != "ab" {
+		t.Fatalf("expected outermost-first order, got order=%v header=%q", order, gotHeader)
+	}
+	if gotBody != `{"rewritten":true}` {
+		t.Fatalf("expected rewritten body, got %q", gotBody)
+	}
+}
+
+func TestClientMiddlewareCanShortCircuit(t *testing.T) {
+	srv := &Server{client: &kaizenAPIClient{baseURL: "http://unused.invalid", apiKey: "k", httpClient: http.DefaultClient}}
+	srv.UseClientMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"cached":true}`)),
+				Header:     http.Header{},
+				Request:    req,
+			}, nil
+		}
+	})
+	data, err := srv.client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if data["cached"] != true {
+		t.Fatalf("expected middleware response, got %#v", data)
+	}
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sensitiveHeaders never reach a transcript verbatim.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Amz-Security-Token": true,
+	"X-Api-Key":            true,
+}
+
+// transcript is one backend request/response pair as written by
+// RecordTranscripts.
+type transcript struct {
+	Time       time.Time           `json:"time"`
+	DurationMS int64               `json:"durationMs"`
+	Request    transcriptRequest   `json:"request"`
+	Response   *transcriptResponse `json:"response,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+type transcriptRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers"`
+	Body    interface{}         `json:"body,omitempty"`
+}
+
+type transcriptResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers"`
+	Body    interface{}         `json:"body,omitempty"`
+}
+
+// RecordTranscripts writes every Kaizen API request/response pair to dir,
+// one JSON file per round trip, so discrepancies between what an agent
+// asked for and what the API returned can be inspected after the fact.
+// Credentials in headers and secret-looking body fields are redacted.
+func (s *Server) RecordTranscripts(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create record directory: %w", err)
+	}
+	s.UseClientMiddleware(newTranscriptRecorder(dir, s.redactor, func(err error) {
+		if s.logger != nil {
+			s.logger.Warn("failed to record backend transcript", "error", err.Error())
+		}
+	}))
+	return nil
+}
+
+func newTranscriptRecorder(dir string, redactor *redactor, onError func(error)) ClientMiddleware {
+	var seq atomic.Uint64
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			started := time.Now()
+			entry := transcript{
+				Time: started.UTC(),
+				Request: transcriptRequest{
+					Method:  req.Method,
+					URL:     req.URL.String(),
+					Headers: sanitizeHeaders(req.Header, redactor),
+				},
+			}
+			if req.Body != nil {
+				raw, err := io.ReadAll(req.Body)
+				req.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(raw))
+				entry.Request.Body = transcriptBody(raw, req.Header.Get("Content-Type"), redactor)
+			}
+
+			resp, err := next(req)
+			write := func() {
+				name := fmt.Sprintf("%s-%06d.json", started.UTC().Format("20060102T150405.000000000Z"), seq.Add(1))
+				if writeErr := writeTranscript(filepath.Join(dir, name), entry); writeErr != nil && onError != nil {
+					onError(writeErr)
+				}
+			}
+			if err != nil {
+				entry.DurationMS = time.Since(started).Milliseconds()
+				entry.Error = err.Error()
+				write()
+				return nil, err
+			}
+			// The body is teed rather than read up front, so NDJSON streams
+			// still reach the caller as they arrive. The transcript is
+			// written once the caller has read or closed the body.
+			resp.Body = &recordingBody{body: resp.Body, finish: func(raw []byte, readErr error) {
+				entry.DurationMS = time.Since(started).Milliseconds()
+				if readErr != nil {
+					entry.Error = readErr.Error()
+				}
+				entry.Response = &transcriptResponse{
+					Status:  resp.StatusCode,
+					Headers: sanitizeHeaders(resp.Header, redactor),
+					Body:    transcriptBody(raw, resp.Header.Get("Content-Type"), redactor),
+				}
+				write()
+			}}
+			return resp, nil
+		}
+	}
+}
+
+// recordingBody copies a response body into a buffer as the caller reads
+// it and hands the copy to finish at EOF, on a read error, or on Close,
+// whichever comes first.
+type recordingBody struct {
+	body    io.ReadCloser
+	buf     bytes.Buffer
+	readErr error
+	once    sync.Once
+	finish  func(raw []byte, readErr error)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.buf.Write(p[:n])
+	if err != nil {
+		if err != io.EOF {
+			b.readErr = err
+		}
+		b.done()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.body.Close()
+	b.done()
+	return err
+}
+
+func (b *recordingBody) done() {
+	b.once.Do(func() { b.finish(b.buf.Bytes(), b.readErr) })
+}
+
+func writeTranscript(path string, entry transcript) error {
+	encoded, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(encoded, '\n'), 0o600)
+}
+
+func sanitizeHeaders(headers http.Header, redactor *redactor) map[string][]string {
+	out := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			out[name] = []string{redactedValue}
+			continue
+		}
+		out[name] = make([]string, len(values))
+		for i, value := range values {
+			out[name][i] = redactor.text(value)
+		}
+	}
+	return out
+}
+
+// transcriptBody keeps JSON bodies structured (with secrets redacted),
+// records NDJSON streams as an array of their lines, and falls back to the
+// raw text for anything else.
+func transcriptBody(raw []byte, contentType string, redactor *redactor) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == ndjsonContentType {
+		var lines []interface{}
+		for _, line := range bytes.Split(bytes.TrimSpace(raw), []byte("\n")) {
+			lines = append(lines, transcriptBody(line, "", redactor))
+		}
+		return lines
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return redactor.text(string(raw))
+	}
+	return redactor.value(decoded)
+}
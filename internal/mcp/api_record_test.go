@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordTranscriptsWritesSanitizedPairs(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"rows":[1,2],"accessToken":"srv-secret"}`))
+	}))
+	defer hs.Close()
+
+	dir := t.TempDir()
+	s := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "super-secret-key", httpClient: hs.Client()}}
+	if err := s.RecordTranscripts(dir); err != nil {
+		t.Fatalf("RecordTranscripts: %v", err)
+	}
+
+	data, err := s.client.call(context.Background(), http.MethodPost, "/v1/akuma/query", map[string]interface{}{
+		"prompt":   "top customers",
+		"password": "hunter2",
+	})
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if data["accessToken"] != "srv-secret" {
+		t.Fatalf("recording must not alter the response seen by the caller: %#v", data)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one transcript file, got %v (%v)", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+	for _, secret := range []string{"super-secret-key", "hunter2", "srv-secret", "session=abc"} {
+		if strings.Contains(string(raw), secret) {
+			t.Fatalf("transcript leaked %q:\n%s", secret, raw)
+		}
+	}
+
+	var entry transcript
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		t.Fatalf("decode transcript: %v", err)
+	}
+	if entry.Request.Method != http.MethodPost || !strings.HasSuffix(entry.Request.URL, "/v1/akuma/query") {
+		t.Fatalf("unexpected request: %#v", entry.Request)
+	}
+	body, _ := entry.Request.Body.(map[string]interface{})
+	if body["prompt"] != "top customers" {
+		t.Fatalf("expected request body to be recorded, got %#v", entry.Request.Body)
+	}
+	if entry.Response == nil || entry.Response.Status != http.StatusOK {
+		t.Fatalf("expected response to be recorded, got %#v", entry.Response)
+	}
+}
+
+func TestRecordTranscriptsRecordsTransportErrors(t *testing.T) {
+	dir := t.TempDir()
+	s := &Server{client: &kaizenAPIClient{baseURL: "http://127.0.0.1:1", apiKey: "k", httpClient: http.DefaultClient}}
+	if err := s.RecordTranscripts(dir); err != nil {
+		t.Fatalf("RecordTranscripts: %v", err)
+	}
+	if _, err := s.client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil); err == nil {
+		t.Fatalf("expected connection error")
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one transcript file, got %v", files)
+	}
+	raw, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(raw), `"error"`) {
+		t.Fatalf("expected transport error in transcript:\n%s", raw)
+	}
+}
+
+func TestRecordTranscriptsTeesStreamsWithoutBuffering(t *testing.T) {
+	firstSeen := make(chan struct{})
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		_, _ = w.Write([]byte(`{"batch":1,"apiToken":"srv-secret"}` + "\n"))
+		w.(http.Flusher).Flush()
+		// The second line is only sent once the caller has the first.
+		<-firstSeen
+		_, _ = w.Write([]byte(`{"batch":2}` + "\n"))
+	}))
+	defer hs.Close()
+
+	dir := t.TempDir()
+	s := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}}
+	if err := s.RecordTranscripts(dir); err != nil {
+		t.Fatalf("RecordTranscripts: %v", err)
+	}
+	var lines int
+	err := s.client.stream(context.Background(), http.MethodPost, "/v1/sozo/generate", map[string]interface{}{}, func([]byte) error {
+		if lines++; lines == 1 {
+			close(firstSeen)
+		}
+		return nil
+	})
+	if err != nil || lines != 2 {
+		t.Fatalf("stream: %d lines, %v", lines, err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one transcript file, got %v", files)
+	}
+	raw, _ := os.ReadFile(files[0])
+	var entry transcript
+	if err := json.Unmarshal(raw, &entry); err != nil || entry.Response == nil {
+		t.Fatalf("decode transcript: %v\n%s", err, raw)
+	}
+	body, _ := entry.Response.Body.([]interface{})
+	if len(body) != 2 || strings.Contains(string(raw), "srv-secret") {
+		t.Fatalf("expected both lines, redacted, in the transcript:\n%s", raw)
+	}
+}
@@ -0,0 +1,266 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+)
+
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// sigV4Signer signs Kaizen API requests for deployments fronted by AWS API
+// Gateway (KAIZEN_API_AUTH_MODE=sigv4). Credentials are resolved on every
+// request so rotated ambient credentials are picked up without a restart.
+type sigV4Signer struct {
+	region      string
+	service     string
+	credentials func() (awsCredentials, error)
+	now         func() time.Time
+}
+
+func newSigV4Signer() (*sigV4Signer, error) {
+	region := getEnv("KAIZEN_API_AWS_REGION", getEnv("AWS_REGION", getEnv("AWS_DEFAULT_REGION", "")))
+	if region == "" {
+		return nil, fmt.Errorf("KAIZEN_API_AUTH_MODE=sigv4 requires KAIZEN_API_AWS_REGION or AWS_REGION")
+	}
+	return &sigV4Signer{
+		region:      region,
+		service:     getEnv("KAIZEN_API_AWS_SERVICE", "execute-api"),
+		credentials: ambientAWSCredentials,
+		now:         time.Now,
+	}, nil
+}
+
+// wrap signs the outgoing request. It is installed beneath all client
+// middleware so the signature covers the final headers and body.
+func (s *sigV4Signer) wrap(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		creds, err := s.credentials()
+		if err != nil {
+			return nil, err
+		}
+		var body []byte
+		if req.Body != nil {
+			body, err = io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		s.sign(req, body, s.now().UTC(), creds)
+		return next(req)
+	}
+}
+
+func (s *sigV4Signer) sign(req *http.Request, body []byte, now time.Time, creds awsCredentials) {
+	amzDate := now.Format(sigV4TimeFormat)
+	day := amzDate[:8]
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	payloadHash := sha256Hex(body)
+	signedHeaders, canonicalHeaders := sigV4CanonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalURI(req.URL),
+		sigV4CanonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{day, s.region, s.service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{sigV4Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// sigV4CanonicalHeaders signs host, content-type and every x-amz-* header.
+// Other headers (User-Agent, Idempotency-Key, ...) stay unsigned so proxies
+// that touch them don't invalidate the signature.
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": req.Host}
+	if values["host"] == "" {
+		values["host"] = req.URL.Host
+	}
+	for name, vals := range req.Header {
+		lower := strings.ToLower(name)
+		if lower != "content-type" && !strings.HasPrefix(lower, "x-amz-") {
+			continue
+		}
+		trimmed := make([]string, len(vals))
+		for i, v := range vals {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		values[lower] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func sigV4CanonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	// Non-S3 services expect each segment to be encoded twice; EscapedPath
+	// is the first pass.
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = sigV4Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, sigV4Escape(key)+"="+sigV4Escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape percent-encodes everything except RFC 3986 unreserved
+// characters, as SigV4 requires (url.QueryEscape uses "+" for spaces).
+func sigV4Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// ambientAWSCredentials resolves credentials from the standard AWS env vars,
+// then the shared credentials file (AWS_SHARED_CREDENTIALS_FILE or
+// ~/.aws/credentials, profile AWS_PROFILE or "default").
+func ambientAWSCredentials() (awsCredentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return awsCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	path := getEnv("AWS_SHARED_CREDENTIALS_FILE", "")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("no AWS credentials found in environment and home directory is unknown: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := getEnv("AWS_PROFILE", "default")
+	creds, err := readSharedAWSCredentials(path, profile)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials found in environment or %s [%s]: %w", path, profile, err)
+	}
+	return creds, nil
+}
+
+func readSharedAWSCredentials(path, profile string) (awsCredentials, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	defer file.Close()
+
+	var (
+		creds   awsCredentials
+		section string
+	)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return awsCredentials{}, err
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("profile has no aws_access_key_id/aws_secret_access_key")
+	}
+	return creds, nil
+}
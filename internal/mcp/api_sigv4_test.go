@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigV4SignMatchesAWSReferenceVector(t *testing.T) {
+	// Reference request from the AWS SigV4 documentation (IAM ListUsers).
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signer := &sigV4Signer{region: "us-east-1", service: "iam"}
+	signer.sign(req, nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC), awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	})
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("unexpected Authorization:\n got %s\nwant %s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Fatalf("unexpected X-Amz-Date %q", got)
+	}
+}
+
+func TestKaizenAPIClientSignsRequestsInSigV4Mode(t *testing.T) {
+	var gotAuth, gotToken, gotBody string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotToken = r.Header.Get("X-Amz-Security-Token")
+		raw, _ := io.ReadAll(r.Body)
+		gotBody = string(raw)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", hs.URL)
+	t.Setenv("KAIZEN_API_KEY", "")
+	t.Setenv("KAIZEN_API_AUTH_MODE", "sigv4")
+	t.Setenv("KAIZEN_API_AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	if _, err := client.call(context.Background(), http.MethodPost, "/v1/sozo/generate", map[string]interface{}{"records": 1}); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDTEST/") || !strings.Contains(gotAuth, "/eu-west-1/execute-api/aws4_request") {
+		t.Fatalf("expected SigV4 Authorization, got %q", gotAuth)
+	}
+	if !strings.Contains(gotAuth, "x-amz-security-token") || gotToken != "session" {
+		t.Fatalf("expected signed session token, got auth=%q token=%q", gotAuth, gotToken)
+	}
+	if gotBody != `{"records":1}` {
+		t.Fatalf("signing must preserve the request body, got %q", gotBody)
+	}
+}
+
+func TestNewKaizenAPIClientSigV4RequiresRegion(t *testing.T) {
+	t.Setenv("KAIZEN_API_AUTH_MODE", "sigv4")
+	t.Setenv("KAIZEN_API_AWS_REGION", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if _, err := newKaizenAPIClient(); err == nil || !strings.Contains(err.Error(), "requires KAIZEN_API_AWS_REGION") {
+		t.Fatalf("expected region error, got %v", err)
+	}
+}
+
+func TestAmbientAWSCredentialsReadsSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	content := "[default]\naws_access_key_id = AKIDDEFAULT\naws_secret_access_key = s1\n\n[kaizen]\naws_access_key_id=AKIDKAIZEN\naws_secret_access_key=s2\naws_session_token=tok\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+	t.Setenv("AWS_PROFILE", "kaizen")
+
+	creds, err := ambientAWSCredentials()
+	if err != nil {
+		t.Fatalf("ambientAWSCredentials: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDKAIZEN" || creds.SecretAccessKey != "s2" || creds.SessionToken != "tok" {
+		t.Fatalf("unexpected credentials: %+v", creds)
+	}
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	// maxStreamLine bounds one NDJSON line so a misbehaving backend
+	// cannot make the scanner buffer grow without limit.
+	maxStreamLine = 16 << 20
+)
+
+// stream sends one request asking for an NDJSON response and hands each
+// non-empty line to onLine as it arrives. Unlike call, a stream is never
+// hedged or retried against another endpoint: lines already delivered
+// cannot be taken back.
+func (c *kaizenAPIClient) stream(ctx context.Context, method, path string, payload interface{}, onLine func([]byte) error) error {
+	if !c.hasCredentials(ctx) {
+		return c.missingCredentials()
+	}
+
+	var raw []byte
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request payload: %w", err)
+		}
+		raw = encoded
+	}
+
+	baseURL := c.baseURL
+	if c.failover != nil {
+		baseURL = c.failover.current()
+	}
+	req, err := c.newRequest(ctx, baseURL, method, path, raw)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", ndjsonContentType)
+
+	resp, err := c.roundTrip()(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(resp.Body)
+		decoded := map[string]interface{}{}
+		_ = json.Unmarshal(body, &decoded)
+		return newAPICallError(resp.StatusCode, decoded)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), maxStreamLine)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := onLine(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// newAPITransport builds the transport used for Kaizen API traffic only.
+// It clones http.DefaultTransport so connection pooling and timeouts keep
+// their defaults while TLS and proxy settings stay scoped to this client.
+// unixSockets maps synthetic base-URL hosts to socket paths (see
+// resolveBaseURL); those hosts are dialed over the socket and never proxied.
+func newAPITransport(unixSockets map[string]string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if caFile := getEnv("KAIZEN_API_CA_FILE", ""); caFile != "" {
+		pool, err := loadCABundle(caFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	// KAIZEN_API_PROXY replaces (rather than extends) the process-wide
+	// HTTP_PROXY/HTTPS_PROXY settings for Kaizen traffic only. When it is
+	// unset the cloned transport keeps http.ProxyFromEnvironment.
+	if rawProxy := getEnv("KAIZEN_API_PROXY", ""); rawProxy != "" {
+		proxy, err := newProxyFunc(rawProxy, getEnv("KAIZEN_API_NO_PROXY", ""))
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxy
+	}
+
+	if len(unixSockets) > 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		dialTCP := transport.DialContext
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, _, err := net.SplitHostPort(addr)
+			if err == nil {
+				if socketPath, ok := unixSockets[host]; ok {
+					return dialer.DialContext(ctx, "unix", socketPath)
+				}
+			}
+			return dialTCP(ctx, network, addr)
+		}
+		proxy := transport.Proxy
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if _, ok := unixSockets[req.URL.Hostname()]; ok || proxy == nil {
+				return nil, nil
+			}
+			return proxy(req)
+		}
+	}
+
+	return transport, nil
+}
+
+// resolveBaseURL turns a configured base URL into the HTTP base URL the
+// client requests against. unix:///path/to/kaizen.sock is rewritten to a
+// synthetic http://host and the socket path is returned for the dialer.
+func resolveBaseURL(raw string, index int) (baseURL, socketHost, socketPath string, err error) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	if !strings.HasPrefix(raw, "unix:") {
+		return raw, "", "", nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Path == "" || parsed.Host != "" {
+		return "", "", "", fmt.Errorf("invalid unix socket URL %q: expected unix:///path/to/kaizen.sock", raw)
+	}
+	socketHost = fmt.Sprintf("kaizen-unix-%d", index)
+	return "http://" + socketHost, socketHost, parsed.Path, nil
+}
+
+// loadCABundle appends the PEM certificates in path to the system pool so
+// internally-signed Kaizen endpoints verify without dropping public roots.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KAIZEN_API_CA_FILE: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("KAIZEN_API_CA_FILE %q contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+func newProxyFunc(rawProxy, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL, err := url.Parse(rawProxy)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid KAIZEN_API_PROXY %q: expected a URL such as http://proxy.internal:3128", rawProxy)
+	}
+	bypass := parseNoProxy(noProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		port := req.URL.Port()
+		if port == "" {
+			port = "80"
+			if req.URL.Scheme == "https" {
+				port = "443"
+			}
+		}
+		if bypass.matches(req.URL.Hostname(), port) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// noProxyList follows the common NO_PROXY conventions: "*" bypasses every
+// host, IPs and CIDRs match addresses, "example.com" matches the domain and
+// its subdomains, ".example.com" matches subdomains only, and an optional
+// ":port" suffix restricts any host entry to that port.
+type noProxyList struct {
+	all      bool
+	networks []*net.IPNet
+	ips      []noProxyEntry
+	domains  []noProxyEntry
+}
+
+type noProxyEntry struct {
+	host string
+	port string
+}
+
+func parseNoProxy(raw string) noProxyList {
+	var list noProxyList
+	for _, field := range strings.Split(raw, ",") {
+		entry := strings.ToLower(strings.TrimSpace(field))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			list.all = true
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			list.networks = append(list.networks, network)
+			continue
+		}
+		host, port := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, port = h, p
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			list.ips = append(list.ips, noProxyEntry{host: ip.String(), port: port})
+			continue
+		}
+		list.domains = append(list.domains, noProxyEntry{host: host, port: port})
+	}
+	return list
+}
+
+func (l noProxyList) matches(host, port string) bool {
+	if l.all {
+		return true
+	}
+	host = strings.ToLower(host)
+	if ip := net.ParseIP(host); ip != nil {
+		for _, network := range l.networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		for _, entry := range l.ips {
+			if entry.host == ip.String() && (entry.port == "" || entry.port == port) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, entry := range l.domains {
+		if entry.port != "" && entry.port != port {
+			continue
+		}
+		if strings.HasPrefix(entry.host, ".") {
+			if strings.HasSuffix(host, entry.host) {
+				return true
+			}
+			continue
+		}
+		if host == entry.host || strings.HasSuffix(host, "."+entry.host) {
+			return true
+		}
+	}
+	return false
+}
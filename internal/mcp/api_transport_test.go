@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeServerCABundle(t *testing.T, hs *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: hs.Certificate().Raw}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("write ca bundle: %v", err)
+	}
+	return path
+}
+
+func TestNewKaizenAPIClientTrustsCustomCABundle(t *testing.T) {
+	hs := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer hs.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", hs.URL)
+	t.Setenv("KAIZEN_API_KEY", "test")
+	t.Setenv("KAIZEN_API_CA_FILE", writeServerCABundle(t, hs))
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	data, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil {
+		t.Fatalf("expected custom CA to verify test server, got %v", err)
+	}
+	if data["ok"] != true {
+		t.Fatalf("unexpected response: %#v", data)
+	}
+}
+
+func TestNewKaizenAPIClientRejectsUnknownCAWithoutBundle(t *testing.T) {
+	hs := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", hs.URL)
+	t.Setenv("KAIZEN_API_KEY", "test")
+	t.Setenv("KAIZEN_API_CA_FILE", "")
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	if _, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil); err == nil {
+		t.Fatalf("expected certificate verification failure without a CA bundle")
+	}
+}
+
+func TestNewKaizenAPIClientRejectsInvalidCABundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write ca bundle: %v", err)
+	}
+	t.Setenv("KAIZEN_API_CA_FILE", path)
+
+	_, err := newKaizenAPIClient()
+	if err == nil || !strings.Contains(err.Error(), "contains no PEM certificates") {
+		t.Fatalf("expected invalid bundle error, got %v", err)
+	}
+}
+
+func TestNewKaizenAPIClientRoutesThroughConfiguredProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`{"via":"proxy"}`))
+	}))
+	defer proxy.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", "http://kaizen.internal.example")
+	t.Setenv("KAIZEN_API_KEY", "test")
+	t.Setenv("KAIZEN_API_PROXY", proxy.URL)
+	t.Setenv("KAIZEN_API_NO_PROXY", "")
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	data, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil {
+		t.Fatalf("call through proxy: %v", err)
+	}
+	if data["via"] != "proxy" || proxiedHost != "kaizen.internal.example" {
+		t.Fatalf("expected request to go through proxy, got host=%q data=%#v", proxiedHost, data)
+	}
+}
+
+func TestNewKaizenAPIClientBypassesProxyForNoProxyHosts(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request should have bypassed the proxy: %s", r.URL)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"via":"direct"}`))
+	}))
+	defer api.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", api.URL)
+	t.Setenv("KAIZEN_API_KEY", "test")
+	t.Setenv("KAIZEN_API_PROXY", proxy.URL)
+	t.Setenv("KAIZEN_API_NO_PROXY", "127.0.0.0/8")
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	data, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil {
+		t.Fatalf("direct call: %v", err)
+	}
+	if data["via"] != "direct" {
+		t.Fatalf("expected direct response, got %#v", data)
+	}
+}
+
+func TestNoProxyListMatches(t *testing.T) {
+	list := parseNoProxy("internal.example, .corp.example, 10.0.0.0/8, 192.168.1.5, api.example:8443")
+	tests := []struct {
+		host string
+		port string
+		want bool
+	}{
+		{"internal.example", "443", true},
+		{"svc.internal.example", "443", true},
+		{"notinternal.example", "443", false},
+		{"corp.example", "443", false},
+		{"api.corp.example", "443", true},
+		{"10.1.2.3", "80", true},
+		{"192.168.1.5", "80", true},
+		{"192.168.1.6", "80", false},
+		{"api.example", "8443", true},
+		{"api.example", "443", false},
+	}
+	for _, tt := range tests {
+		if got := list.matches(tt.host, tt.port); got != tt.want {
+			t.Errorf("matches(%q, %q) = %v, want %v", tt.host, tt.port, got, tt.want)
+		}
+	}
+	if !parseNoProxy("*").matches("anything.example", "443") {
+		t.Fatalf("expected * to bypass every host")
+	}
+}
+
+func TestNewKaizenAPIClientRejectsInvalidProxy(t *testing.T) {
+	t.Setenv("KAIZEN_API_PROXY", "not a url")
+	if _, err := newKaizenAPIClient(); err == nil || !strings.Contains(err.Error(), "invalid KAIZEN_API_PROXY") {
+		t.Fatalf("expected invalid proxy error, got %v", err)
+	}
+}
+
+func TestNewKaizenAPIClientDialsUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "kz")
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "kaizen.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	var gotPath string
+	hs := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"via":"unix"}`))
+	}))
+	hs.Listener = listener
+	hs.Start()
+	defer hs.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unix socket request should not be proxied: %s", r.URL)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("KAIZEN_API_BASE_URL", "unix://"+socketPath)
+	t.Setenv("KAIZEN_API_KEY", "test")
+	t.Setenv("KAIZEN_API_PROXY", proxy.URL)
+	t.Setenv("KAIZEN_API_NO_PROXY", "")
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		t.Fatalf("newKaizenAPIClient: %v", err)
+	}
+	data, err := client.call(context.Background(), http.MethodGet, "/v1/enzan/burn", nil)
+	if err != nil {
+		t.Fatalf("call over unix socket: %v", err)
+	}
+	if data["via"] != "unix" || gotPath != "/v1/enzan/burn" {
+		t.Fatalf("unexpected response path=%q data=%#v", gotPath, data)
+	}
+}
+
+func TestResolveBaseURLRejectsUnixURLWithoutPath(t *testing.T) {
+	if _, _, _, err := resolveBaseURL("unix://", 0); err == nil {
+		t.Fatalf("expected error for unix URL without a socket path")
+	}
+	baseURL, _, socketPath, err := resolveBaseURL("https://api.example/", 0)
+	if err != nil || baseURL != "https://api.example" || socketPath != "" {
+		t.Fatalf("unexpected http resolution: %q %q %v", baseURL, socketPath, err)
+	}
+}
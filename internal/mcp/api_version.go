@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// apiVersionWarning compares the version the backend advertises in its
+// health response with supportedAPIVersion. Only a newer backend warrants
+// a warning: it may expect request fields this server does not send yet.
+// Older, missing, or unparseable versions return "".
+func apiVersionWarning(advertised string) string {
+	backendMajor, backendMinor, ok := parseAPIVersion(advertised)
+	if !ok {
+		return ""
+	}
+	major, minor, _ := parseAPIVersion(supportedAPIVersion)
+	switch {
+	case backendMajor > major:
+		return fmt.Sprintf("Kaizen API version %s is a newer major version than this server supports (%s); tool calls may be rejected until kaizen-mcp is upgraded", advertised, supportedAPIVersion)
+	case backendMajor == major && backendMinor > minor:
+		return fmt.Sprintf("Kaizen API version %s is newer than this server supports (%s); newer request fields are not sent until kaizen-mcp is upgraded", advertised, supportedAPIVersion)
+	}
+	return ""
+}
+
+// parseAPIVersion reads "major.minor", tolerating a leading "v" and a
+// patch component.
+func parseAPIVersion(raw string) (int, int, bool) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(raw), "v"), ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAPIVersionWarning(t *testing.T) {
+	cases := []struct {
+		advertised string
+		want       string
+	}{
+		{"", ""},
+		{"garbage", ""},
+		{supportedAPIVersion, ""},
+		{"0.9", ""},
+		{"v1.0.7", ""},
+		{"1.3", "newer request fields are not sent"},
+		{"2.0", "newer major version"},
+	}
+	for _, tc := range cases {
+		got := apiVersionWarning(tc.advertised)
+		if (tc.want == "" && got != "") || (tc.want != "" && !strings.Contains(got, tc.want)) {
+			t.Fatalf("apiVersionWarning(%q) = %q, want %q", tc.advertised, got, tc.want)
+		}
+	}
+}
+
+func TestKaizenStatusReportsVersionCompatibility(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/health": `{"status":"ok","apiVersion":"1.4"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.status", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	warning, _ := data["compatibilityWarning"].(string)
+	if data["apiVersion"] != "1.4" || data["supportedApiVersion"] != supportedAPIVersion || !strings.Contains(warning, "1.4") {
+		t.Fatalf("expected a compatibility warning, got %#v", data)
+	}
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionHeader is the MCP Streamable HTTP session header; its value
+// identifies an HTTP caller's session in the audit log.
+const sessionHeader = "Mcp-Session-Id"
+
+type sessionContextKey struct{}
+
+// withSession tags a stdio connection or HTTP session for the audit log.
+func withSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+func sessionFrom(ctx context.Context) string {
+	session, _ := ctx.Value(sessionContextKey{}).(string)
+	return session
+}
+
+// auditEntry is one line of the audit log.
+type auditEntry struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session,omitempty"`
+	Tool    string    `json:"tool"`
+	// RequestID is the X-Request-ID sent to the backend for this call.
+	RequestID string `json:"requestId,omitempty"`
+	// Arguments is a digest: secret-looking fields are redacted, free text
+	// is replaced by a keyed hash, and arrays by their length and hash, so
+	// the log never holds prompts, records, or credentials.
+	Arguments map[string]interface{} `json:"arguments"`
+	// ArgumentsHash covers the redacted arguments, so an entry can be
+	// matched against a known call by whoever holds the audit key.
+	ArgumentsHash string `json:"argumentsHash"`
+	Mutating      bool   `json:"mutating,omitempty"`
+	Outcome       string `json:"outcome"`
+	Error         string `json:"error,omitempty"`
+	DurationMS    int64  `json:"durationMs"`
+}
+
+// auditKeyEnv names the per-deployment key for audit log hashes.
+const auditKeyEnv = "KAIZEN_MCP_AUDIT_KEY"
+
+// AuditLog appends one JSON line per tools/call to path, recording who
+// called which tool with what (digested) arguments, how it ended, and how
+// long it took, for compliance review of what agents did.
+//
+// Hashes are HMAC-SHA256 under KAIZEN_MCP_AUDIT_KEY: a plain digest of a
+// short prompt or email address is reversed by guessing. Without the key,
+// a random one is drawn, so hashes only match within one process.
+func (s *Server) AuditLog(path string) error {
+	key := []byte(os.Getenv(auditKeyEnv))
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate audit key: %w", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	s.redactor.addSecret(string(key))
+	s.UseToolMiddleware(newAuditLogger(file, auditHasher{key: key, redactor: s.redactor}, func(err error) {
+		if s.logger != nil {
+			s.logger.Warn("failed to write audit log entry", "error", err.Error())
+		}
+	}))
+	return nil
+}
+
+func newAuditLogger(file *os.File, hasher auditHasher, onError func(error)) ToolMiddleware {
+	var mu sync.Mutex
+	return func(next ToolFunc) ToolFunc {
+		return func(ctx context.Context, call ToolCall) map[string]interface{} {
+			started := time.Now()
+			entry := auditEntry{
+				Time:          started.UTC(),
+				Session:       sessionFrom(ctx),
+				Tool:          call.Name,
+				RequestID:     requestIDFrom(ctx),
+				Arguments:     hasher.digestArguments(call.tool, call.Arguments),
+				ArgumentsHash: hasher.hash(hasher.redactor.value(call.Arguments)),
+				Mutating:      call.Mutating,
+			}
+			result := next(ctx, call)
+			entry.DurationMS = time.Since(started).Milliseconds()
+			entry.Outcome = "ok"
+			if result["isError"] == true {
+				entry.Outcome = "error"
+				if content, _ := result["content"].([]map[string]string); len(content) > 0 {
+					entry.Error, _, _ = strings.Cut(content[0]["text"], "\n")
+				}
+			}
+
+			encoded, err := json.Marshal(entry)
+			if err == nil {
+				mu.Lock()
+				_, err = file.Write(append(encoded, '\n'))
+				mu.Unlock()
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+			return result
+		}
+	}
+}
+
+// auditHasher digests arguments for the audit log.
+type auditHasher struct {
+	key      []byte
+	redactor *redactor
+}
+
+// digestArguments keeps what a reviewer needs to see what kind of call
+// was made without logging its content. Numbers, booleans, and values of
+// enum arguments (dialect, mode, window, ...) are kept as-is.
+func (h auditHasher) digestArguments(tool registeredTool, args map[string]interface{}) map[string]interface{} {
+	properties, _ := tool.def.InputSchema["properties"].(map[string]interface{})
+	digest := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		property, _ := properties[key].(map[string]interface{})
+		if _, enum := property["enum"]; enum && !h.redactor.deniedField(key) {
+			if text, ok := value.(string); ok {
+				digest[key] = text
+				continue
+			}
+		}
+		digest[key] = h.digestValue(key, value)
+	}
+	return digest
+}
+
+func (h auditHasher) digestValue(key string, value interface{}) interface{} {
+	if h.redactor.deniedField(key) {
+		return redactedValue
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		digest := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			digest[k] = h.digestValue(k, item)
+		}
+		return digest
+	case []interface{}:
+		return fmt.Sprintf("[%d items] %s", len(v), h.hash(h.redactor.value(v)))
+	case string:
+		return h.hash(h.redactor.text(v))
+	}
+	return value
+}
+
+// hash is the HMAC of value's JSON, whose object keys are sorted, so equal
+// arguments always hash alike under one key.
+func (h auditHasher) hash(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return "hmac-sha256:" + hex.EncodeToString(hmacSHA256(h.key, string(encoded)))
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogRecordsDigestedToolCalls(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := s.AuditLog(path); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := withSession(context.Background(), "session-1")
+	for _, params := range []toolsCallParams{
+		{Name: "akuma.query", Arguments: map[string]interface{}{
+			"dialect":    "postgres",
+			"prompt":     "revenue for jane@example.com",
+			"maxRows":    10,
+			"guardrails": map[string]interface{}{"apiToken": "hunter2", "blockedTables": []interface{}{"payroll"}},
+		}},
+		{Name: "akuma.format", Arguments: map[string]interface{}{"sql": "select 'ssn-123-45"}},
+	} {
+		raw, _ := json.Marshal(params)
+		s.handleToolCall(ctx, raw)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"jane@example.com", "hunter2", "payroll", "ssn-123-45"} {
+		if strings.Contains(string(contents), secret) {
+			t.Fatalf("audit log leaks %q:\n%s", secret, contents)
+		}
+	}
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(strings.NewReader(string(contents)))
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want one per call", len(entries))
+	}
+	query := entries[0]
+	if query.Tool != "akuma.query" || query.Session != "session-1" || query.Outcome != "ok" {
+		t.Fatalf("unexpected entry: %+v", query)
+	}
+	if query.Arguments["dialect"] != "postgres" || query.Arguments["maxRows"] != 10.0 {
+		t.Fatalf("enum and numeric arguments should be kept: %#v", query.Arguments)
+	}
+	guardrails := query.Arguments["guardrails"].(map[string]interface{})
+	if guardrails["apiToken"] != redactedValue || !strings.HasPrefix(guardrails["blockedTables"].(string), "[1 items] hmac-sha256:") {
+		t.Fatalf("guardrails not digested: %#v", guardrails)
+	}
+	if !strings.HasPrefix(query.Arguments["prompt"].(string), "hmac-sha256:") || !strings.HasPrefix(query.ArgumentsHash, "hmac-sha256:") {
+		t.Fatalf("free text should be hashed: %#v", query)
+	}
+	if format := entries[1]; format.Outcome != "error" || format.Error == "" {
+		t.Fatalf("tool errors should be recorded: %+v", format)
+	}
+}
+
+func TestAuditLogHashesAreKeyedAndSkipRedactedFields(t *testing.T) {
+	t.Setenv(auditKeyEnv, "deployment-audit-key")
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := s.AuditLog(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"hunter2", "correct-horse"} {
+		raw, _ := json.Marshal(toolsCallParams{Name: "akuma.query", Arguments: map[string]interface{}{
+			"dialect":    "postgres",
+			"prompt":     "all orders",
+			"guardrails": map[string]interface{}{"apiToken": token},
+		}})
+		s.handleToolCall(context.Background(), raw)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	var first, second auditEntry
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &first) != nil || json.Unmarshal([]byte(lines[1]), &second) != nil {
+		t.Fatalf("unexpected audit log:\n%s", contents)
+	}
+	if first.ArgumentsHash != second.ArgumentsHash {
+		t.Fatalf("argumentsHash should ignore redacted fields: %q != %q", first.ArgumentsHash, second.ArgumentsHash)
+	}
+	unkeyed := "sha256:" + sha256Hex([]byte(`"all orders"`))
+	keyed := auditHasher{key: []byte("deployment-audit-key")}.hash("all orders")
+	if first.Arguments["prompt"] == unkeyed || first.Arguments["prompt"] != keyed {
+		t.Fatalf("prompt should be an HMAC under the audit key, got %v", first.Arguments["prompt"])
+	}
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+// budgetOverrideArgument lets a call run past its cost ceiling once the
+// user has agreed to the cost.
+const budgetOverrideArgument = "overrideBudget"
+
+// budgetGuarded lets a tool be capped by costCeilings in the config file
+// and adds the overrideBudget argument. guard picks the calls worth an
+// estimate round trip; nil means every call.
+func budgetGuarded(guard func(args map[string]interface{}) bool) toolOption {
+	if guard == nil {
+		guard = func(map[string]interface{}) bool { return true }
+	}
+	return func(t *registeredTool) {
+		t.budgetGuard = guard
+		t.addProperty(budgetOverrideArgument, map[string]interface{}{
+			"type":        "boolean",
+			"description": "Run even if the estimated cost exceeds the configured ceiling; set only after the user accepts the cost",
+		})
+	}
+}
+
+// sharesCeilingWith caps a budget-guarded tool by another tool's cost
+// ceiling when it has none of its own, so a ceiling cannot be sidestepped
+// through a tool that runs the same work another way.
+func sharesCeilingWith(name string) toolOption {
+	return func(t *registeredTool) {
+		t.sharedCeiling = name
+	}
+}
+
+// pricedAs prices a budget-guarded call as the sum of the estimates for
+// the calls estimates returns, for tools that fan out into several.
+func pricedAs(estimates func(args map[string]interface{}) []kaizen.BudgetEstimateRequest) toolOption {
+	return func(t *registeredTool) {
+		t.budgetEstimates = estimates
+	}
+}
+
+// returnsRows guards akuma queries that execute SQL, not just generate it.
+func returnsRows(args map[string]interface{}) bool {
+	mode, _ := args["mode"].(string)
+	return mode == "sql-and-results"
+}
+
+// guardBudget asks the backend to price an expensive call and refuses it
+// when the estimate exceeds the tool's ceiling or the remaining budget.
+func (s *Server) guardBudget(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if call.tool.budgetGuard == nil {
+			return next(ctx, call)
+		}
+		raw, args := takeArgument(call.Arguments, budgetOverrideArgument)
+		call.Arguments = args
+		ceiling, capped := s.costCeilings[call.Name]
+		if !capped && call.tool.sharedCeiling != "" {
+			ceiling, capped = s.costCeilings[call.tool.sharedCeiling]
+		}
+		if override, _ := raw.(bool); override || !capped || !call.tool.budgetGuard(args) {
+			return next(ctx, call)
+		}
+
+		estimate, err := s.estimateCost(ctx, call.tool, call.Name, args)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("%s could not be priced against its $%.2f cost ceiling: %v. Call again with %s: true to run it anyway.", call.Name, ceiling, err, budgetOverrideArgument))
+		}
+		if estimate.EstimatedCostUSD > ceiling {
+			return toolErrorResult(fmt.Sprintf("%s is estimated to cost $%.2f, above the $%.2f ceiling configured for it. Confirm the cost with the user, then call again with %s: true to run it anyway.", call.Name, estimate.EstimatedCostUSD, ceiling, budgetOverrideArgument))
+		}
+		if remaining := estimate.RemainingBudgetUSD; remaining != nil && estimate.EstimatedCostUSD > *remaining {
+			return toolErrorResult(fmt.Sprintf("%s is estimated to cost $%.2f, but only $%.2f of the budget remains. Confirm the cost with the user, then call again with %s: true to run it anyway.", call.Name, estimate.EstimatedCostUSD, *remaining, budgetOverrideArgument))
+		}
+		return next(ctx, call)
+	}
+}
+
+// estimateCost prices a call, summing the estimates of the calls it fans
+// out into. The remaining budget is the lowest one the backend reports.
+func (s *Server) estimateCost(ctx context.Context, tool registeredTool, name string, args map[string]interface{}) (*kaizen.BudgetEstimate, error) {
+	requests := []kaizen.BudgetEstimateRequest{{Tool: name, Arguments: args}}
+	if tool.budgetEstimates != nil {
+		requests = tool.budgetEstimates(args)
+	}
+	total := &kaizen.BudgetEstimate{}
+	for _, req := range requests {
+		estimate, err := s.kaizenClient(ctx).EstimateBudget(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		total.EstimatedCostUSD += estimate.EstimatedCostUSD
+		if remaining := estimate.RemainingBudgetUSD; remaining != nil && (total.RemainingBudgetUSD == nil || *remaining < *total.RemainingBudgetUSD) {
+			total.RemainingBudgetUSD = remaining
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGuardBudgetRefusesCallsOverTheCeiling(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/budget/estimate": `{"estimatedCostUSD":12.5,"remainingBudgetUSD":100}`,
+		"POST /v1/sozo/generate":   `{"records":[]}`,
+	})
+	defer cleanup()
+	s.costCeilings = map[string]float64{"sozo.generate": 10}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 1e7, "schemaName": "saas_customers"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if result.(map[string]interface{})["isError"] != true || !strings.Contains(content[0]["text"], "$12.50, above the $10.00 ceiling") {
+		t.Fatalf("expected a budget refusal, got %#v", result)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/budget/estimate" || !strings.Contains(captured[0].Body, `"tool":"sozo.generate"`) {
+		t.Fatalf("expected only the estimate request, got %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 1e7, "schemaName": "saas_customers", "overrideBudget": true}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("override should run the call, got %#v", result)
+	}
+	if len(captured) != 2 || captured[1].Path != "/v1/sozo/generate" || strings.Contains(captured[1].Body, "overrideBudget") {
+		t.Fatalf("override should skip the estimate and not be forwarded, got %+v", captured)
+	}
+}
+
+func TestGuardBudgetCapsJobsByTheGenerateCeiling(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/budget/estimate": `{"estimatedCostUSD":12.5}`,
+	})
+	defer cleanup()
+	s.costCeilings = map[string]float64{"sozo.generate": 10}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.jobs.start", Arguments: map[string]interface{}{"records": 1e7, "schemaName": "saas_customers"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if result.(map[string]interface{})["isError"] != true || !strings.Contains(content[0]["text"], "sozo.jobs.start is estimated to cost $12.50, above the $10.00 ceiling") {
+		t.Fatalf("expected a budget refusal, got %#v", result)
+	}
+	if len(captured) != 1 || !strings.Contains(captured[0].Body, `"tool":"sozo.jobs.start"`) {
+		t.Fatalf("expected only the estimate request, got %+v", captured)
+	}
+}
+
+func TestGuardBudgetChecksRemainingBudget(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/budget/estimate": `{"estimatedCostUSD":3,"remainingBudgetUSD":1.25}`,
+	})
+	defer cleanup()
+	s.costCeilings = map[string]float64{"akuma.query": 5}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.query", Arguments: map[string]interface{}{"dialect": "postgres", "prompt": "all orders", "mode": "sql-and-results"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if !strings.Contains(content[0]["text"], "only $1.25 of the budget remains") || len(captured) != 1 {
+		t.Fatalf("expected a remaining-budget refusal, got %#v (%+v)", result, captured)
+	}
+}
+
+func TestGuardBudgetSkipsCheapCalls(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/query": `{"sql":"SELECT 1"}`,
+	})
+	defer cleanup()
+	s.costCeilings = map[string]float64{"akuma.query": 5}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.query", Arguments: map[string]interface{}{"dialect": "postgres", "prompt": "all orders", "mode": "sql-only"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("unexpected tool error: %#v", result)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/query" {
+		t.Fatalf("sql-only calls should not be priced, got %+v", captured)
+	}
+}
+
+func TestGuardBudgetPricesAkumaBatchAgainstTheQueryCeiling(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/budget/estimate": `{"estimatedCostUSD":2}`,
+	})
+	defer cleanup()
+	s.costCeilings = map[string]float64{"akuma.query": 5}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.batch", Arguments: map[string]interface{}{
+		"dialect": "postgres",
+		"prompts": []interface{}{"orders", "refunds", "churn"},
+		"mode":    "sql-and-results",
+	}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if result.(map[string]interface{})["isError"] != true || !strings.Contains(content[0]["text"], "akuma.batch is estimated to cost $6.00, above the $5.00 ceiling") {
+		t.Fatalf("expected the whole batch to be refused, got %#v", result)
+	}
+	if len(captured) != 3 {
+		t.Fatalf("expected one estimate per prompt and no queries, got %+v", captured)
+	}
+	for _, req := range captured {
+		if req.Path != "/v1/budget/estimate" || !strings.Contains(req.Body, `"tool":"akuma.query"`) || strings.Contains(req.Body, "prompts") {
+			t.Fatalf("expected akuma.query estimates, got %+v", captured)
+		}
+	}
+}
+
+func TestGuardBudgetCapsInteractiveQueriesByTheQueryCeiling(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/budget/estimate": `{"estimatedCostUSD":8}`,
+	})
+	defer cleanup()
+	s.costCeilings = map[string]float64{"akuma.query": 5}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.query_interactive", Arguments: map[string]interface{}{"dialect": "postgres", "prompt": "all orders", "mode": "sql-and-results"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if result.(map[string]interface{})["isError"] != true || !strings.Contains(content[0]["text"], "akuma.query_interactive is estimated to cost $8.00, above the $5.00 ceiling") {
+		t.Fatalf("expected a budget refusal, got %#v", result)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/budget/estimate" {
+		t.Fatalf("expected only the estimate request, got %+v", captured)
+	}
+}
@@ -0,0 +1,33 @@
+package mcp
+
+import "fmt"
+
+// toolSlots holds one semaphore per tool with a toolConcurrency limit.
+type toolSlots map[string]chan struct{}
+
+func newToolSlots(limits map[string]int) toolSlots {
+	if len(limits) == 0 {
+		return nil
+	}
+	slots := make(toolSlots, len(limits))
+	for name, limit := range limits {
+		slots[name] = make(chan struct{}, limit)
+	}
+	return slots
+}
+
+// acquire takes a slot for one call to name without waiting. It returns
+// a release func, or an error with retry guidance when the tool is
+// already at its limit. Tools without a limit always succeed.
+func (t toolSlots) acquire(name string) (func(), error) {
+	slot, ok := t[name]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, nil
+	default:
+		return nil, fmt.Errorf("%s is busy: %d calls are already in progress, the configured limit; retry in a few seconds or once an earlier call finishes", name, cap(slot))
+	}
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToolSlotsRejectCallsOverLimit(t *testing.T) {
+	slots := newToolSlots(map[string]int{"sozo.generate": 2})
+	first, err := slots.acquire("sozo.generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := slots.acquire("sozo.generate"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := slots.acquire("sozo.generate"); err == nil || !strings.Contains(err.Error(), "busy") {
+		t.Fatalf("third call err = %v, want busy", err)
+	}
+	if _, err := slots.acquire("enzan.burn"); err != nil {
+		t.Fatalf("unlimited tool err = %v", err)
+	}
+	first()
+	if _, err := slots.acquire("sozo.generate"); err != nil {
+		t.Fatalf("slot should be free after release: %v", err)
+	}
+}
+
+func TestHandleToolCallReturnsBusyWhileLimitIsReached(t *testing.T) {
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-unblock
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+	s := &Server{
+		client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "test-key", httpClient: hs.Client()},
+		slots:  newToolSlots(map[string]int{"kaizen.usage": 1}),
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.usage", Arguments: map[string]interface{}{}})
+	done := make(chan interface{})
+	go func() {
+		result, _ := s.handleToolCall(context.Background(), raw)
+		done <- result
+	}()
+	<-entered
+
+	result, _ := s.handleToolCall(context.Background(), raw)
+	resultMap := result.(map[string]interface{})
+	content := resultMap["content"].([]map[string]string)
+	if resultMap["isError"] != true || !strings.Contains(content[0]["text"], "kaizen.usage is busy") {
+		t.Fatalf("expected busy error, got %#v", resultMap)
+	}
+
+	close(unblock)
+	if first := (<-done).(map[string]interface{}); first["isError"] == true {
+		t.Fatalf("first call failed: %#v", first)
+	}
+}
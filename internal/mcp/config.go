@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// serverConfig is the optional JSON file named by KAIZEN_MCP_CONFIG. It
+// holds settings that don't fit a single env var (per-tool maps and the
+// like); every field is optional and a missing file path means defaults.
+type serverConfig struct {
+	// ToolTimeouts overrides the client-wide backend deadline per tool,
+	// e.g. {"enzan.burn": "10s", "sozo.generate": "300s"}.
+	ToolTimeouts map[string]configDuration `json:"toolTimeouts,omitempty"`
+	// ToolConcurrency caps in-flight calls per tool, e.g.
+	// {"sozo.generate": 2}. Calls over the cap fail fast as busy.
+	ToolConcurrency map[string]int `json:"toolConcurrency,omitempty"`
+	// CostCeilings refuses expensive calls whose backend cost estimate
+	// exceeds a USD ceiling, e.g. {"akuma.query": 5, "sozo.generate": 20},
+	// unless the call passes overrideBudget.
+	CostCeilings map[string]float64 `json:"costCeilings,omitempty"`
+	// Profiles names alternate Kaizen backends (dev, staging, prod) that
+	// tools can target with the optional `profile` argument.
+	Profiles map[string]profileConfig `json:"profiles,omitempty"`
+	// DefaultProfile selects the profile used when a call omits `profile`.
+	// When empty, the KAIZEN_API_BASE_URL/KAIZEN_API_KEY client is used.
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+	// DefaultGuardrails are merged into every Akuma query; agents can
+	// only tighten them (see akumaGuardrails).
+	DefaultGuardrails *akumaGuardrails `json:"defaultGuardrails,omitempty"`
+	// Currency sets the default currency for Enzan cost figures and the
+	// rates used to convert them locally (see currencyConfig).
+	Currency *currencyConfig `json:"currency,omitempty"`
+	// RedactFields are regular expressions over field names, e.g. "^ssn$",
+	// whose values are never logged, recorded, or audited.
+	RedactFields []string `json:"redactFields,omitempty"`
+	// CustomTools declares extra REST-backed tools (see customToolConfig).
+	CustomTools []customToolConfig `json:"customTools,omitempty"`
+	// EnableTools and DisableTools are glob allow/deny lists over tool
+	// names; KAIZEN_TOOLS_ENABLE/KAIZEN_TOOLS_DISABLE override them.
+	EnableTools  []string `json:"enableTools,omitempty"`
+	DisableTools []string `json:"disableTools,omitempty"`
+	// ToolAliases maps alternate names to canonical tool names, e.g.
+	// {"akuma_query": "akuma.query"}.
+	ToolAliases map[string]string `json:"toolAliases,omitempty"`
+}
+
+// configDuration accepts Go duration strings ("90s", "5m") in the config
+// file so operators don't have to spell timeouts in nanoseconds.
+type configDuration time.Duration
+
+func (d *configDuration) UnmarshalJSON(raw []byte) error {
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\"")
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(parsed)
+	return nil
+}
+
+func loadServerConfig() (*serverConfig, error) {
+	path := getEnv("KAIZEN_MCP_CONFIG", "")
+	if path == "" {
+		return &serverConfig{}, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KAIZEN_MCP_CONFIG: %w", err)
+	}
+
+	var cfg serverConfig
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse KAIZEN_MCP_CONFIG %q: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_MCP_CONFIG %q: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (c *serverConfig) validate() error {
+	known := map[string]bool{}
+	for _, tool := range toolDefinitions() {
+		known[tool.Name] = true
+	}
+	builtin := make(map[string]bool, len(known))
+	for name := range known {
+		builtin[name] = true
+	}
+	for _, tool := range c.CustomTools {
+		if err := tool.validate(builtin); err != nil {
+			return fmt.Errorf("customTools: %w", err)
+		}
+		if known[tool.Name] {
+			return fmt.Errorf("customTools: %s is declared twice", tool.Name)
+		}
+		known[tool.Name] = true
+	}
+	for alias, canonical := range c.ToolAliases {
+		if strings.TrimSpace(alias) == "" {
+			return fmt.Errorf("toolAliases: alias must not be empty")
+		}
+		if known[alias] {
+			return fmt.Errorf("toolAliases: %s conflicts with an existing tool", alias)
+		}
+		if !known[canonical] {
+			return fmt.Errorf("toolAliases: %s targets unknown tool %q", alias, canonical)
+		}
+	}
+	for name, timeout := range c.ToolTimeouts {
+		if !known[name] {
+			return fmt.Errorf("toolTimeouts: unknown tool %q", name)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("toolTimeouts: %s must be positive", name)
+		}
+	}
+	for name, limit := range c.ToolConcurrency {
+		if !known[name] {
+			return fmt.Errorf("toolConcurrency: unknown tool %q", name)
+		}
+		if limit <= 0 {
+			return fmt.Errorf("toolConcurrency: %s must be positive", name)
+		}
+	}
+	for name, ceiling := range c.CostCeilings {
+		tool, ok := builtinTools.lookup(name)
+		if !ok {
+			return fmt.Errorf("costCeilings: unknown tool %q", name)
+		}
+		if tool.budgetGuard == nil {
+			return fmt.Errorf("costCeilings: %s does not support cost estimates", name)
+		}
+		if ceiling <= 0 {
+			return fmt.Errorf("costCeilings: %s must be positive", name)
+		}
+	}
+	if guardrails := c.DefaultGuardrails; guardrails != nil && guardrails.MaxRows != nil && *guardrails.MaxRows <= 0 {
+		return fmt.Errorf("defaultGuardrails: maxRows must be positive")
+	}
+	if _, err := newRedactor(c.RedactFields); err != nil {
+		return err
+	}
+	if c.Currency != nil {
+		if err := c.Currency.validate(); err != nil {
+			return err
+		}
+	}
+	for name, profile := range c.Profiles {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("profiles: name must not be empty")
+		}
+		parsed, err := url.Parse(profile.BaseURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("profiles: %s.baseUrl must be an absolute URL", name)
+		}
+		if err := validateWorkspace("profiles: "+name+".workspace", profile.Workspace); err != nil {
+			return err
+		}
+	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			return fmt.Errorf("defaultProfile: unknown profile %q", c.DefaultProfile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeServerConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kaizen-mcp.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("KAIZEN_MCP_CONFIG", path)
+	return path
+}
+
+func TestLoadServerConfigWithoutPathReturnsDefaults(t *testing.T) {
+	t.Setenv("KAIZEN_MCP_CONFIG", "")
+	cfg, err := loadServerConfig()
+	if err != nil {
+		t.Fatalf("loadServerConfig: %v", err)
+	}
+	if len(cfg.ToolTimeouts) != 0 {
+		t.Fatalf("expected no tool timeouts, got %#v", cfg.ToolTimeouts)
+	}
+}
+
+func TestLoadServerConfigParsesToolTimeouts(t *testing.T) {
+	writeServerConfig(t, `{"toolTimeouts":{"enzan.burn":"10s","sozo.generate":"5m"}}`)
+	cfg, err := loadServerConfig()
+	if err != nil {
+		t.Fatalf("loadServerConfig: %v", err)
+	}
+	if got := time.Duration(cfg.ToolTimeouts["enzan.burn"]); got != 10*time.Second {
+		t.Fatalf("enzan.burn timeout = %v", got)
+	}
+	if got := time.Duration(cfg.ToolTimeouts["sozo.generate"]); got != 5*time.Minute {
+		t.Fatalf("sozo.generate timeout = %v", got)
+	}
+}
+
+func TestLoadServerConfigRejectsInvalidFiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"unknown tool", `{"toolTimeouts":{"enzan.nope":"10s"}}`, `unknown tool "enzan.nope"`},
+		{"non-positive", `{"toolTimeouts":{"enzan.burn":"0s"}}`, "must be positive"},
+		{"numeric duration", `{"toolTimeouts":{"enzan.burn":10}}`, "duration must be a string"},
+		{"unknown field", `{"toolTimeout":{}}`, "unknown field"},
+		{"custom tool shadows builtin", `{"customTools":[{"name":"enzan.burn","method":"GET","path":"/v1/x"}]}`, "conflicts with a built-in tool"},
+		{"custom tool bad method", `{"customTools":[{"name":"x.y","method":"TRACE","path":"/v1/x"}]}`, "method must be"},
+		{"alias to unknown tool", `{"toolAliases":{"q":"akuma.nope"}}`, `targets unknown tool "akuma.nope"`},
+		{"alias shadows tool", `{"toolAliases":{"enzan.burn":"akuma.query"}}`, "conflicts with an existing tool"},
+		{"concurrency for unknown tool", `{"toolConcurrency":{"sozo.nope":2}}`, `unknown tool "sozo.nope"`},
+		{"non-positive concurrency", `{"toolConcurrency":{"sozo.generate":0}}`, "must be positive"},
+		{"ceiling for unguarded tool", `{"costCeilings":{"enzan.burn":5}}`, "does not support cost estimates"},
+		{"non-positive ceiling", `{"costCeilings":{"akuma.query":0}}`, "must be positive"},
+		{"non-positive guardrail row cap", `{"defaultGuardrails":{"maxRows":0}}`, "maxRows must be positive"},
+		{"lowercase default currency", `{"currency":{"default":"eur"}}`, "ISO 4217"},
+		{"non-positive currency rate", `{"currency":{"rates":{"EUR":0}}}`, "must be positive"},
+		{"invalid redact pattern", `{"redactFields":["("]}`, "redactFields: invalid pattern"},
+		{"custom tool relative path", `{"customTools":[{"name":"x.y","method":"GET","path":"v1/x"}]}`, "must start with /"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeServerConfig(t, tc.body)
+			_, err := loadServerConfig()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
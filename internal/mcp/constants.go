@@ -1,7 +1,16 @@
 package mcp
 
+import "time"
+
 const (
 	serverName    = "kaizen-mcp"
 	serverVersion = "1.0.0"
 	protocol      = "2024-11-05"
+
+	// supportedAPIVersion is the newest Kaizen API version whose request
+	// payloads this server knows how to build. Bump it together with the
+	// payload changes.
+	supportedAPIVersion = "1.0"
+
+	defaultAPITimeout = 60 * time.Second
 )
@@ -0,0 +1,228 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// currencyHeader asks the backend to report Enzan cost figures in another
+// currency when the server has no local rate for it.
+const currencyHeader = "X-Kaizen-Currency"
+
+// currencyRatesTTL is how long rates fetched from ratesUrl are reused.
+const currencyRatesTTL = time.Hour
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// currencyConfig is the config file's currency section. Rates are units of
+// the currency per US dollar.
+type currencyConfig struct {
+	// Default is the ISO 4217 code used when a call omits currency.
+	Default string `json:"default,omitempty"`
+	// Rates converts locally at fixed rates, e.g. {"EUR": 0.92}.
+	Rates map[string]float64 `json:"rates,omitempty"`
+	// RatesURL serves {"rates": {"EUR": 0.92, ...}} against USD. Its rates
+	// take precedence over Rates and are refreshed hourly.
+	RatesURL string `json:"ratesUrl,omitempty"`
+}
+
+func (c *currencyConfig) validate() error {
+	if c.Default != "" && !currencyCodePattern.MatchString(c.Default) {
+		return fmt.Errorf("currency: default must be an ISO 4217 code such as EUR")
+	}
+	for code, rate := range c.Rates {
+		if !currencyCodePattern.MatchString(code) {
+			return fmt.Errorf("currency: rates key %q must be an ISO 4217 code", code)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("currency: rate for %s must be positive", code)
+		}
+	}
+	if c.RatesURL != "" && !strings.HasPrefix(c.RatesURL, "https://") && !strings.HasPrefix(c.RatesURL, "http://") {
+		return fmt.Errorf("currency: ratesUrl must be an http(s) URL")
+	}
+	return nil
+}
+
+// currencyRates resolves conversion rates from the config file, caching
+// the rates fetched from ratesUrl.
+type currencyRates struct {
+	config     currencyConfig
+	httpClient *http.Client
+	// stats, when set, counts lookups served from the fetched rates.
+	stats *serverStats
+
+	mu        sync.Mutex
+	fetched   map[string]float64
+	fetchedAt time.Time
+}
+
+func newCurrencyRates(config *currencyConfig) *currencyRates {
+	if config == nil {
+		return nil
+	}
+	return &currencyRates{config: *config, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// rate returns the units of code per US dollar and where the rate came
+// from, or zero when only the backend can convert.
+func (r *currencyRates) rate(ctx context.Context, code string) (float64, string, error) {
+	if r == nil {
+		return 0, "", nil
+	}
+	if r.config.RatesURL != "" {
+		rates, err := r.remoteRates(ctx)
+		if rate, ok := rates[code]; ok && rate > 0 {
+			return rate, r.config.RatesURL, nil
+		}
+		if err != nil && r.config.Rates[code] == 0 {
+			return 0, "", err
+		}
+	}
+	if rate, ok := r.config.Rates[code]; ok {
+		return rate, "config", nil
+	}
+	return 0, "", nil
+}
+
+func (r *currencyRates) remoteRates(ctx context.Context) (map[string]float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cached := r.fetched != nil && time.Since(r.fetchedAt) < currencyRatesTTL
+	r.stats.recordLookup(cacheCurrencyRates, cached)
+	if cached {
+		return r.fetched, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.config.RatesURL, nil)
+	if err != nil {
+		return r.fetched, err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return r.fetched, fmt.Errorf("failed to fetch currency rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return r.fetched, fmt.Errorf("failed to fetch currency rates: status %d", resp.StatusCode)
+	}
+	var body struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return r.fetched, fmt.Errorf("failed to decode currency rates: %w", err)
+	}
+	r.fetched, r.fetchedAt = body.Rates, time.Now()
+	return r.fetched, nil
+}
+
+// costReporting adds the currency argument to an Enzan tool that reports
+// USD figures.
+func costReporting() toolOption {
+	return func(t *registeredTool) {
+		t.costReporting = true
+		t.addProperty("currency", map[string]interface{}{
+			"type":        "string",
+			"pattern":     currencyCodePattern.String(),
+			"description": "ISO 4217 code, e.g. EUR, to report cost figures in; defaults to the server's configured currency, else USD",
+		})
+	}
+}
+
+type currencyContextKey struct{}
+
+func withCurrency(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, currencyContextKey{}, code)
+}
+
+func requestedCurrency(ctx context.Context) string {
+	code, _ := ctx.Value(currencyContextKey{}).(string)
+	return code
+}
+
+// applyCurrency converts USD figures locally when a rate is configured,
+// and otherwise asks the backend to convert through currencyHeader.
+func (s *Server) applyCurrency(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if !call.tool.costReporting {
+			return next(ctx, call)
+		}
+		raw, args := takeArgument(call.Arguments, "currency")
+		call.Arguments = args
+		code, _ := raw.(string)
+		if code == "" && s.currency != nil {
+			code = s.currency.config.Default
+		}
+		if code == "" || code == "USD" {
+			return next(ctx, call)
+		}
+		rate, source, err := s.currency.rate(ctx, code)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("cannot convert to %s: %v", code, err))
+		}
+		if rate == 0 {
+			return next(withCurrency(ctx, code), call)
+		}
+
+		result := next(ctx, call)
+		data, ok := result["structuredContent"].(map[string]interface{})
+		if result["isError"] == true || !ok {
+			return result
+		}
+		converted := convertUSD(data, code, rate).(map[string]interface{})
+		converted["currency"] = map[string]interface{}{"code": code, "ratePerUSD": rate, "source": source}
+		result["structuredContent"] = converted
+		replaceTextContent(result, call.tool.renderText(call.Arguments, converted)...)
+		return result
+	}
+}
+
+// convertUSD multiplies every number, or array of numbers such as a daily
+// series, under a key containing "USD" by rate and renames the key for
+// code, e.g. costUSD becomes costEUR.
+func convertUSD(value interface{}, code string, rate float64) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if amounts, ok := convertAmounts(item, rate); ok && strings.Contains(key, "USD") {
+				converted[strings.Replace(key, "USD", code, 1)] = amounts
+				continue
+			}
+			converted[key] = convertUSD(item, code, rate)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = convertUSD(item, code, rate)
+		}
+		return converted
+	}
+	return value
+}
+
+// convertAmounts converts a number or an array made only of numbers (or
+// of such arrays). It reports false for anything else.
+func convertAmounts(value interface{}, rate float64) (interface{}, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v * rate, true
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			amount, ok := convertAmounts(item, rate)
+			if !ok {
+				return nil, false
+			}
+			converted[i] = amount
+		}
+		return converted, true
+	}
+	return nil, false
+}
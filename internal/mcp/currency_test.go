@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCurrencyConvertsLocallyWithConfiguredRate(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/burn": `{"burnRateUSDPerHour":10,"models":[{"model":"gpt-4o","costUSD":4,"tokens":100}]}`,
+	})
+	defer cleanup()
+	s.currency = newCurrencyRates(&currencyConfig{Default: "EUR", Rates: map[string]float64{"EUR": 0.5}})
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if structured["burnRateEURPerHour"] != 5.0 || structured["burnRateUSDPerHour"] != nil {
+		t.Fatalf("burn rate not converted: %#v", structured)
+	}
+	model := structured["models"].([]interface{})[0].(map[string]interface{})
+	if model["costEUR"] != 2.0 || model["tokens"] != 100.0 {
+		t.Fatalf("nested costs not converted: %#v", model)
+	}
+	if meta := structured["currency"].(map[string]interface{}); meta["code"] != "EUR" || meta["source"] != "config" {
+		t.Fatalf("currency = %#v", meta)
+	}
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	if !strings.Contains(text, "costEUR") {
+		t.Fatalf("text content not re-rendered: %s", text)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{"currency": "USD"}})
+	result, _ = s.handleToolCall(context.Background(), raw)
+	if structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); structured["burnRateUSDPerHour"] != 10.0 {
+		t.Fatalf("USD should be left as reported, got %#v", structured)
+	}
+}
+
+func TestCurrencyConvertsSeriesAndKeepsTheToolRendering(t *testing.T) {
+	registry := newToolRegistry()
+	registry.register("test.spend", toolDefinition{
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+	}, func(context.Context, *Server, map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"dailyCostUSD": []interface{}{10.0, 20.0}, "labelsUSD": []interface{}{"a", 1.0}}, nil
+	}, costReporting(), offline(), withTextRenderer(func(_, data map[string]interface{}) ([]string, bool) {
+		return []string{fmt.Sprintf("daily: %v", data["dailyCostEUR"])}, true
+	}))
+	s := &Server{tools: registry, currency: newCurrencyRates(&currencyConfig{Rates: map[string]float64{"EUR": 0.5}})}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "test.spend", Arguments: map[string]interface{}{"currency": "EUR"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if series, _ := structured["dailyCostEUR"].([]interface{}); len(series) != 2 || series[0] != 5.0 || series[1] != 10.0 {
+		t.Fatalf("numeric series not converted: %#v", structured)
+	}
+	if _, ok := structured["labelsUSD"]; !ok {
+		t.Fatalf("mixed arrays are not amounts and keep their key: %#v", structured)
+	}
+	if text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]; text != "daily: [5 10]" {
+		t.Fatalf("text should come from the tool's renderer, got %q", text)
+	}
+}
+
+func TestCurrencyWithoutRateAsksBackend(t *testing.T) {
+	var currencies []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		currencies = append(currencies, r.Header.Get(currencyHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backend.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client()}}
+
+	for _, args := range []map[string]interface{}{{}, {"currency": "JPY"}} {
+		raw, _ := json.Marshal(toolsCallParams{Name: "enzan.summary", Arguments: args})
+		if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] == true {
+			t.Fatalf("unexpected tool error: %#v", result)
+		}
+	}
+	if got := strings.Join(currencies, ","); got != ",JPY" {
+		t.Fatalf("currency headers = %q, want none then JPY", got)
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.summary", Arguments: map[string]interface{}{"currency": "euro"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(currencies) != 2 {
+		t.Fatalf("an invalid currency code should be rejected locally")
+	}
+}
+
+func TestCurrencyRatesURL(t *testing.T) {
+	fetches := 0
+	rates := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write([]byte(`{"rates":{"GBP":0.8}}`))
+	}))
+	defer rates.Close()
+	r := newCurrencyRates(&currencyConfig{RatesURL: rates.URL, Rates: map[string]float64{"GBP": 0.7, "EUR": 0.9}})
+
+	if rate, source, err := r.rate(context.Background(), "GBP"); err != nil || rate != 0.8 || source != rates.URL {
+		t.Fatalf("GBP = %v from %q (%v), want the fetched rate", rate, source, err)
+	}
+	if rate, source, _ := r.rate(context.Background(), "EUR"); rate != 0.9 || source != "config" {
+		t.Fatalf("EUR = %v from %q, want the configured fallback", rate, source)
+	}
+	if rate, _, _ := r.rate(context.Background(), "JPY"); rate != 0 {
+		t.Fatalf("JPY should be left to the backend, got %v", rate)
+	}
+	if fetches != 1 {
+		t.Fatalf("rates fetched %d times, want cached after the first", fetches)
+	}
+}
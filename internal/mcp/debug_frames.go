@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Frame directions in the debug dump, as seen from the server.
+const (
+	frameInbound  = "-->"
+	frameOutbound = "<--"
+)
+
+// frameDump writes every JSON-RPC frame the server reads or writes to a
+// separate file, pretty-printed and redacted, for debugging clients whose
+// framing or messages the server does not understand. Methods on a nil
+// *frameDump do nothing.
+type frameDump struct {
+	redactor *redactor
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// DebugFrames appends every inbound and outbound JSON-RPC frame to path.
+// Frames hold prompts and results, so this is meant for local debugging
+// only; credentials and denied fields are still redacted.
+func (s *Server) DebugFrames(path string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open debug frames file: %w", err)
+	}
+	s.frames = &frameDump{redactor: s.redactor, w: file}
+	return nil
+}
+
+// payload dumps a frame as received or sent. Payloads that are not JSON
+// are written verbatim (redacted), since those are usually the ones being
+// debugged.
+func (d *frameDump) payload(transport, direction string, payload []byte) {
+	if d == nil {
+		return
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		d.write(transport, direction, "(not JSON: "+err.Error()+")\n"+d.redactor.text(string(payload)))
+		return
+	}
+	pretty, _ := json.MarshalIndent(d.redactor.value(decoded), "", "  ")
+	d.write(transport, direction, string(pretty))
+}
+
+// message dumps a frame the server is about to encode.
+func (d *frameDump) message(transport, direction string, message interface{}) {
+	if d == nil {
+		return
+	}
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		d.write(transport, direction, "(failed to encode: "+err.Error()+")")
+		return
+	}
+	d.payload(transport, direction, encoded)
+}
+
+// readError records a frame the server could not read at all, such as a
+// bad Content-Length header.
+func (d *frameDump) readError(transport string, err error) {
+	if d != nil {
+		d.write(transport, frameInbound, "(unreadable frame: "+d.redactor.text(err.Error())+")")
+	}
+}
+
+func (d *frameDump) write(transport, direction, body string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = fmt.Fprintf(d.w, "%s %s %s\n%s\n\n", direction, time.Now().UTC().Format(time.RFC3339Nano), transport, body)
+}
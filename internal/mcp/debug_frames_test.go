@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDebugFramesDumpsStdioFramesRedacted(t *testing.T) {
+	redactor, _ := newRedactor(nil)
+	redactor.addSecret("sk-live-supersecret")
+	s := &Server{redactor: redactor}
+	path := filepath.Join(t.TempDir(), "frames.log")
+	if err := s.DebugFrames(path); err != nil {
+		t.Fatalf("DebugFrames: %v", err)
+	}
+	var out bytes.Buffer
+	s.reader = bufio.NewReader(strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"ping","params":{"apiKey":"abc","note":"sk-live-supersecret"}}` + "\n" +
+			"Content-Length: nope\r\n\r\n"))
+	s.writer = bufio.NewWriter(&out)
+	if err := s.Serve(); err == nil {
+		t.Fatal("expected the bad Content-Length frame to end the session")
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read frames: %v", err)
+	}
+	dump := string(contents)
+	for _, want := range []string{
+		"--> ", " stdio\n{\n  \"id\": 1,",
+		`"apiKey": "[REDACTED]"`, `"note": "[REDACTED]"`,
+		"<-- ", `"result": {}`,
+		"(unreadable frame: ",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("frames dump missing %q:\n%s", want, dump)
+		}
+	}
+	if strings.Contains(dump, "sk-live-supersecret") || strings.Contains(dump, `"abc"`) {
+		t.Fatalf("frames dump leaked a secret:\n%s", dump)
+	}
+}
+
+func TestDebugFramesDumpsHTTPFrames(t *testing.T) {
+	s := &Server{}
+	path := filepath.Join(t.TempDir(), "frames.log")
+	if err := s.DebugFrames(path); err != nil {
+		t.Fatalf("DebugFrames: %v", err)
+	}
+	hs := newHTTPTestServer(t, s)
+	postMCP(t, hs.URL, "", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	postMCP(t, hs.URL, "", `not json`)
+
+	contents, _ := os.ReadFile(path)
+	dump := string(contents)
+	for _, want := range []string{" http\n{\n  \"id\": 2,", `"result": {}`, "(not JSON: ", "\nnot json\n"} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("frames dump missing %q:\n%s", want, dump)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// setupInstructions explains how to leave degraded mode, the state the
+// server starts in when it has no Kaizen credentials. Tools are still
+// listed so clients can be set up before the key is issued.
+const setupInstructions = "The Kaizen MCP server has no API credentials. Set KAIZEN_API_KEY to a Kaizen API key in the environment the MCP client starts the server with, then restart the server. KAIZEN_API_BASE_URL selects the backend (default http://localhost:8080). Call kaizen.status to check the setup."
+
+// offline marks a tool that works without the Kaizen API, so degraded
+// mode leaves it usable.
+func offline() toolOption {
+	return func(t *registeredTool) { t.offline = true }
+}
+
+// needsConfiguration reports whether calls in ctx have no credential to
+// send: no key on the selected client, no SigV4 signer, and no caller
+// token under passthrough auth.
+func (s *Server) needsConfiguration(ctx context.Context) bool {
+	return !s.apiClient(ctx).hasCredentials(ctx)
+}
+
+// annotateUnconfigured flags backend tools with _meta.configurationNeeded
+// in degraded mode.
+func (s *Server) annotateUnconfigured(ctx context.Context, defs []toolDefinition) []toolDefinition {
+	if !s.needsConfiguration(ctx) {
+		return defs
+	}
+	annotated := make([]toolDefinition, len(defs))
+	for i, def := range defs {
+		annotated[i] = def
+		if tool, _ := s.lookupTool(def.Name); tool.offline {
+			continue
+		}
+		// Meta is shared with the registry; copy before flagging.
+		meta := toolMeta{}
+		if def.Meta != nil {
+			meta = *def.Meta
+		}
+		meta.ConfigurationNeeded = true
+		annotated[i].Meta = &meta
+	}
+	return annotated
+}
+
+// requireConfiguration refuses backend tools in degraded mode with setup
+// instructions instead of a bare missing-key error. It runs after
+// routeProfile, since a profile may carry its own key, and after argument
+// validation, so mistakes in a call are still reported first.
+func (s *Server) requireConfiguration(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if !call.tool.offline && s.needsConfiguration(ctx) {
+			if s.passthroughAuth {
+				return toolErrorResult(fmt.Sprintf("%s needs the Kaizen API: %v", call.Name, s.apiClient(ctx).missingCredentials()))
+			}
+			return toolErrorResult(fmt.Sprintf("%s needs the Kaizen API. %s", call.Name, setupInstructions))
+		}
+		return next(ctx, call)
+	}
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDegradedModeWithoutAPIKey(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+	s.client.apiKey = ""
+
+	resp := s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	if instructions, _ := resp.Result.(map[string]interface{})["instructions"].(string); !strings.Contains(instructions, "KAIZEN_API_KEY") {
+		t.Fatalf("initialize should carry setup instructions, got %#v", resp.Result)
+	}
+
+	resp = s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+	for _, def := range resp.Result.(map[string]interface{})["tools"].([]toolDefinition) {
+		flagged := def.Meta != nil && def.Meta.ConfigurationNeeded
+		if offline := def.Name == "akuma.format" || def.Name == "kaizen.status" || def.Name == "mcp.stats" || def.Name == resultPageTool; flagged == offline {
+			t.Fatalf("%s: configurationNeeded = %v", def.Name, flagged)
+		}
+	}
+	if def, _ := builtinTools.lookup("akuma.query"); def.def.Meta != nil && def.def.Meta.ConfigurationNeeded {
+		t.Fatalf("annotation leaked into the shared registry")
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.status", Arguments: map[string]interface{}{}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if structured["configured"] != false || structured["setup"] != setupInstructions {
+		t.Fatalf("kaizen.status should explain the setup, got %#v", structured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "akuma.format", Arguments: map[string]interface{}{"sql": "select 1"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("offline tools should still work, got %#v", result)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{}})
+	result, _ = s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if result.(map[string]interface{})["isError"] != true || !strings.HasPrefix(content[0]["text"], "enzan.burn needs the Kaizen API. ") {
+		t.Fatalf("backend tools should fail with setup instructions, got %#v", result)
+	}
+	if len(captured) != 0 {
+		t.Fatalf("degraded mode reached the backend: %+v", captured)
+	}
+}
+
+func TestConfiguredServerIsNotAnnotated(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+
+	resp := s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	if _, ok := resp.Result.(map[string]interface{})["instructions"]; ok {
+		t.Fatalf("configured server should not send setup instructions")
+	}
+	resp = s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+	for _, def := range resp.Result.(map[string]interface{})["tools"].([]toolDefinition) {
+		if def.Meta != nil && def.Meta.ConfigurationNeeded {
+			t.Fatalf("%s flagged although a key is set", def.Name)
+		}
+	}
+}
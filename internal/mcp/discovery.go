@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	toolsDiscoveryPath    = "/v1/tools"
+	toolsDiscoveryTimeout = 10 * time.Second
+)
+
+// toolsDiscoveryResponse is the /v1/tools payload. Each entry uses the
+// same shape as a customTools config entry.
+type toolsDiscoveryResponse struct {
+	Tools []customToolConfig `json:"tools"`
+}
+
+// refreshDiscoveredTools fetches the backend's tool declarations and
+// replaces the discovered set, reporting whether tools/list changed.
+// Declarations that shadow a built-in, custom, or OpenAPI tool, repeat a
+// name, fail validation, or are excluded by the tool filter are skipped
+// with a warning.
+func (s *Server) refreshDiscoveredTools(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, toolsDiscoveryTimeout)
+	defer cancel()
+	fetched, err := s.client.call(ctx, http.MethodGet, toolsDiscoveryPath, nil)
+	if err != nil {
+		return false, err
+	}
+	raw, err := json.Marshal(fetched)
+	if err != nil {
+		return false, err
+	}
+	var decl toolsDiscoveryResponse
+	if err := json.Unmarshal(raw, &decl); err != nil {
+		return false, fmt.Errorf("invalid %s response: %w", toolsDiscoveryPath, err)
+	}
+
+	seen := map[string]bool{}
+	var accepted []customToolConfig
+	for _, tool := range decl.Tools {
+		if _, exists := s.registry().lookup(tool.Name); exists || seen[tool.Name] {
+			s.warnDiscovery("skipping discovered tool that shadows an existing tool", tool.Name, nil)
+			continue
+		}
+		if err := tool.validate(nil); err != nil {
+			s.warnDiscovery("skipping invalid discovered tool", tool.Name, err)
+			continue
+		}
+		if !s.toolFilter.allows(tool.Name) {
+			continue
+		}
+		seen[tool.Name] = true
+		accepted = append(accepted, tool)
+	}
+	next := newToolRegistry()
+	registerCustomTools(next, accepted)
+
+	previous := s.discovered.Load()
+	if previous != nil && sameDefinitions(previous.definitions(), next.definitions()) {
+		return false, nil
+	}
+	s.discovered.Store(next)
+	return previous != nil || len(accepted) > 0, nil
+}
+
+// watchDiscoveredTools re-fetches discovered tools every
+// toolsDiscoveryInterval until ctx is done. When the set changes and
+// notify is non-nil, the client is told to re-list.
+func (s *Server) watchDiscoveredTools(ctx context.Context, notify notifier) {
+	if !s.toolsDiscovery || s.toolsDiscoveryInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.toolsDiscoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := s.refreshDiscoveredTools(ctx)
+		if err != nil {
+			s.warnDiscovery("failed to refresh discovered tools", "", err)
+			continue
+		}
+		if changed && notify != nil {
+			notify("notifications/tools/list_changed", nil)
+		}
+	}
+}
+
+// lookupTool resolves name against the configured registry first, then
+// the discovered tools.
+func (s *Server) lookupTool(name string) (registeredTool, bool) {
+	if unprefixed := s.unprefixedToolName(name); unprefixed != name {
+		if tool, ok := s.lookupTool(unprefixed); ok {
+			return tool, true
+		}
+	}
+	if tool, ok := s.registry().lookup(name); ok {
+		return tool, true
+	}
+	if discovered := s.discovered.Load(); discovered != nil {
+		return discovered.lookup(name)
+	}
+	return registeredTool{}, false
+}
+
+func (s *Server) warnDiscovery(msg, tool string, err error) {
+	if s.logger == nil {
+		return
+	}
+	attrs := []interface{}{}
+	if tool != "" {
+		attrs = append(attrs, "tool", tool)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	s.logger.Warn(msg, attrs...)
+}
+
+func sameDefinitions(a, b []toolDefinition) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
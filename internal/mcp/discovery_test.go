@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const discoveredToolsBody = `{"tools":[
+	{"name":"kaizen.reports","description":"List reports","method":"GET","path":"/v1/reports"},
+	{"name":"kaizen.report_run","description":"Run a report","method":"POST","path":"/v1/reports/{id}/run"},
+	{"name":"akuma.query","description":"Shadow","method":"GET","path":"/v1/shadow"},
+	{"name":"kaizen.broken","method":"GET","path":"reports"}
+]}`
+
+func TestRefreshDiscoveredToolsMergesBackendTools(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/tools":   discoveredToolsBody,
+		"GET /v1/reports": `{"reports":[]}`,
+	})
+	defer cleanup()
+
+	changed, err := s.refreshDiscoveredTools(context.Background())
+	if err != nil || !changed {
+		t.Fatalf("refresh = %v, %v; want changed", changed, err)
+	}
+
+	listed := map[string]int{}
+	for _, def := range s.listTools() {
+		listed[def.Name]++
+	}
+	if listed["kaizen.reports"] != 1 || listed["kaizen.report_run"] != 1 {
+		t.Fatalf("discovered tools missing from tools/list: %v", listed)
+	}
+	if listed["akuma.query"] != 1 || listed["kaizen.broken"] != 0 {
+		t.Fatalf("shadowing or invalid tools should be skipped: %v", listed)
+	}
+	if tool, _ := s.lookupTool("akuma.query"); tool.def.Description == "Shadow" {
+		t.Fatal("built-in akuma.query was replaced by a discovered tool")
+	}
+	if tool, _ := s.lookupTool("kaizen.report_run"); !tool.mutating {
+		t.Fatal("discovered POST tool should be mutating")
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.reports", Arguments: map[string]interface{}{}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	last := captured[len(captured)-1]
+	if last.Method != http.MethodGet || last.Path != "/v1/reports" {
+		t.Fatalf("unexpected request: %+v", last)
+	}
+
+	changed, err = s.refreshDiscoveredTools(context.Background())
+	if err != nil || changed {
+		t.Fatalf("second refresh = %v, %v; want unchanged", changed, err)
+	}
+}
+
+func TestRefreshDiscoveredToolsHonorsToolFilter(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{"GET /v1/tools": discoveredToolsBody})
+	defer cleanup()
+	s.toolFilter = toolFilter{disable: []string{"kaizen.report_*"}}
+
+	if _, err := s.refreshDiscoveredTools(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.lookupTool("kaizen.report_run"); ok {
+		t.Fatal("disabled discovered tool should not be registered")
+	}
+	if _, ok := s.lookupTool("kaizen.reports"); !ok {
+		t.Fatal("kaizen.reports should be registered")
+	}
+}
+
+func TestWatchDiscoveredToolsNotifiesOnChange(t *testing.T) {
+	body := `{"tools":[]}`
+	var captured []capturedRequest
+	responses := map[string]string{"GET /v1/tools": body}
+	s, cleanup := newPricingTestServer(t, &captured, responses)
+	defer cleanup()
+	if _, err := s.refreshDiscoveredTools(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	responses["GET /v1/tools"] = discoveredToolsBody
+	s.toolsDiscovery = true
+	s.toolsDiscoveryInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notified := make(chan string, 1)
+	go s.watchDiscoveredTools(ctx, func(method string, _ interface{}) {
+		select {
+		case notified <- method:
+		default:
+		}
+	})
+
+	select {
+	case method := <-notified:
+		if method != "notifications/tools/list_changed" {
+			t.Fatalf("method = %q", method)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no list_changed notification after the tool set changed")
+	}
+	if _, ok := s.lookupTool("kaizen.reports"); !ok {
+		t.Fatal("refreshed tools should be callable")
+	}
+}
+
+func TestListChangedIsAdvertisedOnlyWhereItCanBeSent(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}, toolsDiscovery: true}
+	initialize := []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	for name, ctx := range map[string]context.Context{
+		"stdio": withNotifier(context.Background(), func(string, interface{}) {}),
+		"http":  context.Background(),
+	} {
+		resp := s.handleMessage(ctx, initialize)
+		tools := resp.Result.(map[string]interface{})["capabilities"].(map[string]interface{})["tools"].(map[string]interface{})
+		if want := name == "stdio"; tools["listChanged"] != want {
+			t.Fatalf("%s: listChanged = %v, want %v", name, tools["listChanged"], want)
+		}
+	}
+}
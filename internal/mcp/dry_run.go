@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// dryRunHeader asks the backend to validate a state-changing request and
+// report its effect without applying it. A backend that honors it echoes
+// the header in its response or sets "dryRun": true in the body.
+const dryRunHeader = "X-Kaizen-Dry-Run"
+
+// dryRunNotice leads the result of a dry-run call so the model does not
+// mistake the reported effect for an applied change.
+const dryRunNotice = "Dry run: the backend validated this call and reported its effect; nothing was changed."
+
+// dryRunnable adds the standard dryRun argument to a mutating tool whose
+// backend endpoints honor dryRunHeader.
+func dryRunnable() toolOption {
+	return func(t *registeredTool) {
+		t.dryRun = true
+		t.addProperty("dryRun", map[string]interface{}{
+			"type":        "boolean",
+			"description": "Validate the change and report its effect without applying it",
+		})
+	}
+}
+
+type dryRunContextKey struct{}
+
+// dryRunCall records whether the backend confirmed a dry-run tool call.
+type dryRunCall struct {
+	acknowledged atomic.Bool
+}
+
+// withDryRun marks every backend request made for the rest of one tool
+// call as a dry run.
+func withDryRun(ctx context.Context) (context.Context, *dryRunCall) {
+	call := &dryRunCall{}
+	return context.WithValue(ctx, dryRunContextKey{}, call), call
+}
+
+func dryRunFrom(ctx context.Context) *dryRunCall {
+	call, _ := ctx.Value(dryRunContextKey{}).(*dryRunCall)
+	return call
+}
+
+func isDryRun(ctx context.Context) bool {
+	return dryRunFrom(ctx) != nil
+}
+
+// dryRunIgnoredError reports a successful response to a dry-run request
+// that does not show the backend honored dryRunHeader, so the change may
+// have been applied.
+type dryRunIgnoredError struct{}
+
+func (*dryRunIgnoredError) Error() string {
+	return fmt.Sprintf("the backend did not confirm the dry run (no %s response header or \"dryRun\": true in the body), so the change may have been applied; check the current state before retrying", dryRunHeader)
+}
+
+// confirmDryRun checks a successful response to a dry-run request for
+// the backend's acknowledgement and records it on the call.
+func confirmDryRun(ctx context.Context, header http.Header, body []byte) error {
+	call := dryRunFrom(ctx)
+	if call == nil {
+		return nil
+	}
+	acknowledged := strings.EqualFold(header.Get(dryRunHeader), "true")
+	if !acknowledged {
+		var envelope struct {
+			DryRun bool `json:"dryRun"`
+		}
+		acknowledged = json.Unmarshal(body, &envelope) == nil && envelope.DryRun
+	}
+	if !acknowledged {
+		return &dryRunIgnoredError{}
+	}
+	call.acknowledged.Store(true)
+	return nil
+}
+
+// takeDryRun removes the dryRun argument, which handlers never see, and
+// reports whether it was set.
+func takeDryRun(args map[string]interface{}) (bool, map[string]interface{}) {
+	raw, stripped := takeArgument(args, "dryRun")
+	dryRun, _ := raw.(bool)
+	return dryRun, stripped
+}
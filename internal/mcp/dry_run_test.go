@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDryRunSendsHeaderAndStripsArgument(t *testing.T) {
+	var header, body string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(dryRunHeader)
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(dryRunHeader, "true")
+		_, _ = w.Write([]byte(`{"applied":false,"changes":["add table orders"]}`))
+	}))
+	defer hs.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "test-key", httpClient: hs.Client()}}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.schema", Arguments: map[string]interface{}{
+		"dialect": "postgres",
+		"tables":  []interface{}{map[string]interface{}{"name": "orders"}},
+		"dryRun":  true,
+	}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	resultMap := result.(map[string]interface{})
+	if resultMap["isError"] == true {
+		t.Fatalf("unexpected tool error: %#v", resultMap)
+	}
+	if header != "true" {
+		t.Fatalf("%s = %q, want true", dryRunHeader, header)
+	}
+	if strings.Contains(body, "dryRun") {
+		t.Fatalf("dryRun should not be forwarded in the body: %s", body)
+	}
+	content := resultMap["content"].([]map[string]string)
+	if content[0]["text"] != dryRunNotice {
+		t.Fatalf("first content block = %q, want dry-run notice", content[0]["text"])
+	}
+}
+
+func TestDryRunFailsClosedWithoutBackendAcknowledgement(t *testing.T) {
+	for name, tc := range map[string]struct {
+		header string
+		body   string
+		ok     bool
+	}{
+		"echoed header":      {header: "true", body: `{"changes":[]}`, ok: true},
+		"dryRun in the body": {body: `{"dryRun":true,"changes":[]}`, ok: true},
+		"no acknowledgement": {body: `{"applied":true}`},
+		"dryRun false":       {body: `{"dryRun":false}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.header != "" {
+					w.Header().Set(dryRunHeader, tc.header)
+				}
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer hs.Close()
+			s := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "test-key", httpClient: hs.Client()}}
+
+			raw, _ := json.Marshal(toolsCallParams{Name: "sozo.schema.delete", Arguments: map[string]interface{}{"name": "checkout-events", "dryRun": true}})
+			result, rpcErr := s.handleToolCall(context.Background(), raw)
+			if rpcErr != nil {
+				t.Fatalf("rpc error: %+v", rpcErr)
+			}
+			resultMap := result.(map[string]interface{})
+			text := resultMap["content"].([]map[string]string)[0]["text"]
+			if tc.ok {
+				if resultMap["isError"] == true || text != dryRunNotice {
+					t.Fatalf("acknowledged dry run reported as %#v", resultMap)
+				}
+				return
+			}
+			if resultMap["isError"] != true || !strings.Contains(text, "did not confirm the dry run") {
+				t.Fatalf("unacknowledged dry run reported as %#v", resultMap)
+			}
+		})
+	}
+}
+
+func TestDryRunIsNotRetriedOnAnotherEndpoint(t *testing.T) {
+	hits := 0
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(`{"deleted":true}`))
+	}))
+	defer hs.Close()
+	client := &kaizenAPIClient{baseURL: hs.URL, apiKey: "test-key", httpClient: hs.Client(), failover: &endpointPool{urls: []string{hs.URL, hs.URL}}}
+
+	ctx, _ := withDryRun(context.Background())
+	if _, err := client.call(ctx, http.MethodDelete, "/v1/enzan/alerts/a1", nil); err == nil {
+		t.Fatal("expected an unacknowledged dry run to fail")
+	}
+	if hits != 1 {
+		t.Fatalf("backend hit %d times, want 1", hits)
+	}
+}
+
+func TestDryRunHeaderAbsentWithoutArgument(t *testing.T) {
+	header := "unset"
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(dryRunHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "test-key", httpClient: hs.Client()}}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.set_budget", Arguments: map[string]interface{}{
+		"amountUSD": 100,
+		"period":    "monthly",
+	}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	if result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("unexpected tool error: %#v", result)
+	}
+	if header != "" {
+		t.Fatalf("%s = %q, want empty", dryRunHeader, header)
+	}
+}
+
+func TestDryRunOnlyAdvertisedOnSupportingTools(t *testing.T) {
+	for _, def := range toolDefinitions() {
+		properties, _ := def.InputSchema["properties"].(map[string]interface{})
+		_, hasDryRun := properties["dryRun"]
+		tool, _ := builtinTools.lookup(def.Name)
+		if hasDryRun != tool.dryRun {
+			t.Fatalf("%s: dryRun property = %v, dryRun support = %v", def.Name, hasDryRun, tool.dryRun)
+		}
+		if tool.dryRun && !tool.mutating {
+			t.Fatalf("%s accepts dryRun but is not mutating", def.Name)
+		}
+	}
+	if tool, _ := builtinTools.lookup("akuma.schema"); !tool.dryRun {
+		t.Fatal("akuma.schema should accept dryRun")
+	}
+}
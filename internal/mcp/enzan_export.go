@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// enzanExportInlineLimit is the largest CSV the backend returns inline;
+// bigger reports come back as a short-lived download URL instead so the
+// tool result stays small enough for a model context.
+const enzanExportInlineLimit = 256 << 10
+
+// renderEnzanExport returns inline CSV verbatim so it can be pasted
+// straight into a spreadsheet, and otherwise introduces the resource link
+// callEnzanExport attaches for the download.
+func renderEnzanExport(_, data map[string]interface{}) ([]string, bool) {
+	if csv, ok := data["csv"].(string); ok {
+		return []string{csv}, true
+	}
+	if link, _ := data["downloadUrl"].(string); link == "" {
+		return nil, false
+	}
+	var b strings.Builder
+	b.WriteString("The report is too large to return inline")
+	if rows, ok := data["rowCount"].(float64); ok {
+		fmt.Fprintf(&b, " (%d rows)", int64(rows))
+	}
+	b.WriteString("; download the CSV from the linked resource.")
+	if expires, _ := data["expiresAt"].(string); expires != "" {
+		fmt.Fprintf(&b, " The link expires at %s.", expires)
+	}
+	return []string{b.String()}, true
+}
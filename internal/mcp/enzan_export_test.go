@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHandleToolCallEnzanExportReturnsInlineCSV(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/enzan/export": `{"format":"csv","rowCount":2,"csv":"project,costUSD\nvision,480.25\nnlp,120\n"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.export", Arguments: map[string]interface{}{"window": "30d", "groupBy": []interface{}{"project"}}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"window":"30d","groupBy":["project"],"maxInlineBytes":262144}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	if text != "project,costUSD\nvision,480.25\nnlp,120\n" {
+		t.Fatalf("expected raw CSV text, got %q", text)
+	}
+}
+
+func TestEnzanExportLinksLargeReports(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/enzan/export": `{"format":"csv","rowCount":48210,"downloadUrl":"https://exports.example/r/abc.csv","expiresAt":"2024-06-01T12:00:00Z"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.export", Arguments: map[string]interface{}{"window": "30d"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if len(content) != 2 || !strings.Contains(content[0]["text"], "48210 rows") || !strings.Contains(content[0]["text"], "2024-06-01T12:00:00Z") {
+		t.Fatalf("expected a summary ahead of the link, got %#v", content)
+	}
+	if strings.Contains(content[0]["text"], "https://") {
+		t.Fatalf("the URL belongs in the resource link, not the text: %q", content[0]["text"])
+	}
+	if link := content[1]; link["type"] != "resource_link" || link["uri"] != "https://exports.example/r/abc.csv" || link["mimeType"] != "text/csv" {
+		t.Fatalf("expected a resource link to the download, got %#v", link)
+	}
+	if _, ok := renderEnzanExport(nil, map[string]interface{}{"error": "x"}); ok {
+		t.Fatalf("expected fallback to JSON for unrecognized responses")
+	}
+}
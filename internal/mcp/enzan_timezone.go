@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// zoned adds the timezone argument to an Enzan tool whose windows and
+// daily buckets the backend can align to a business day instead of UTC.
+func zoned() toolOption {
+	return func(t *registeredTool) {
+		t.zoned = true
+		t.addProperty("timezone", map[string]interface{}{
+			"type":        "string",
+			"description": "IANA time zone such as Europe/Berlin that windows and daily groupings align to; defaults to the server's KAIZEN_ENZAN_TIMEZONE, else UTC",
+		})
+	}
+}
+
+func validateTimezone(name string) error {
+	if _, err := time.LoadLocation(name); err != nil || name == "" || name == "Local" {
+		return fmt.Errorf("timezone must be an IANA time zone such as Europe/Berlin, got %q", name)
+	}
+	return nil
+}
+
+// applyTimezone checks the timezone argument and fills in the server
+// default when the call has none.
+func (s *Server) applyTimezone(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if !call.tool.zoned {
+			return next(ctx, call)
+		}
+		if timezone, ok := call.Arguments["timezone"].(string); ok {
+			if err := validateTimezone(timezone); err != nil {
+				return toolErrorResult(err.Error())
+			}
+			return next(ctx, call)
+		}
+		if s.enzanTimezone != "" {
+			args := make(map[string]interface{}, len(call.Arguments)+1)
+			for key, value := range call.Arguments {
+				args[key] = value
+			}
+			args["timezone"] = s.enzanTimezone
+			call.Arguments = args
+		}
+		return next(ctx, call)
+	}
+}
@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestEnzanTimezoneArgumentAndDefault(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+
+	call := func(name string, args map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		raw, _ := json.Marshal(toolsCallParams{Name: name, Arguments: args})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		return result.(map[string]interface{})
+	}
+
+	call("enzan.summary", map[string]interface{}{"window": "24h"})
+	if captured[0].Body != `{"window":"24h"}` {
+		t.Fatalf("no timezone should be sent without a default: %s", captured[0].Body)
+	}
+
+	s.enzanTimezone = "America/New_York"
+	call("enzan.summary", map[string]interface{}{"window": "24h"})
+	call("enzan.breakdown", map[string]interface{}{"dimension": "team", "timezone": "Asia/Tokyo"})
+	if captured[1].Body != `{"window":"24h","timezone":"America/New_York"}` {
+		t.Fatalf("server default not applied: %s", captured[1].Body)
+	}
+	if captured[2].Query != "dimension=team&timezone=Asia%2FTokyo" {
+		t.Fatalf("argument should override the default: %s", captured[2].Query)
+	}
+
+	if result := call("enzan.summary", map[string]interface{}{"timezone": "Mars/Olympus"}); result["isError"] != true || len(captured) != 3 {
+		t.Fatalf("unknown timezone should be rejected locally, got %#v", result)
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// readinessTimeout bounds the backend check behind /readyz, below the
+	// usual Kubernetes probe timeout.
+	readinessTimeout = 5 * time.Second
+	// readinessCacheTTL lets frequent probes from several load balancers
+	// share one backend check.
+	readinessCacheTTL = 5 * time.Second
+)
+
+// readiness caches the outcome of the last /readyz backend check.
+type readiness struct {
+	mu        sync.Mutex
+	err       error
+	checkedAt time.Time
+}
+
+// handleHealthz reports that the process is alive and serving; it never
+// touches the backend, so a Kaizen outage does not get the pod restarted.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz reports whether the Kaizen API is reachable and accepts the
+// configured credentials, so load balancers stop routing to an instance
+// whose tool calls would all fail.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkReadiness(r.Context()); err != nil {
+		writeHealth(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": s.redactor.text(err.Error())})
+		return
+	}
+	writeHealth(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func (s *Server) checkReadiness(ctx context.Context) error {
+	s.readiness.mu.Lock()
+	defer s.readiness.mu.Unlock()
+	cached := !s.readiness.checkedAt.IsZero() && time.Since(s.readiness.checkedAt) < readinessCacheTTL
+	s.stats.recordLookup(cacheReadiness, cached)
+	if cached {
+		return s.readiness.err
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	var err error
+	if s.passthroughAuth {
+		err = s.probeReachable(ctx)
+	} else {
+		_, err = s.probeBackend(ctx)
+	}
+	s.readiness.err, s.readiness.checkedAt = err, time.Now()
+	return err
+}
+
+// probeReachable checks that the Kaizen API answers its health endpoint
+// without sending credentials. Under passthrough auth callers bring their
+// own, so there may be no shared key to validate, and a 401 or 403 still
+// means the backend is up.
+func (s *Server) probeReachable(ctx context.Context) error {
+	status, body, err := s.client.do(ctx, http.MethodGet, healthPath, nil)
+	if err != nil {
+		return diagnoseBackendError(s.client.baseURL, err)
+	}
+	if status >= http.StatusBadRequest && status != http.StatusUnauthorized && status != http.StatusForbidden {
+		decoded := map[string]interface{}{}
+		_ = json.Unmarshal(body, &decoded)
+		return diagnoseBackendError(s.client.baseURL, newAPICallError(status, decoded))
+	}
+	return nil
+}
+
+func writeHealth(w http.ResponseWriter, status int, body map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthAndReadinessEndpoints(t *testing.T) {
+	status := http.StatusOK
+	probes := 0
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer backend.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client()}}
+	hs := newHTTPTestServer(t, s)
+
+	get := func(path string) (int, map[string]string) {
+		t.Helper()
+		resp, err := http.Get(hs.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	if code, body := get("/healthz"); code != http.StatusOK || body["status"] != "ok" || probes != 0 {
+		t.Fatalf("/healthz = %d %v after %d probes", code, body, probes)
+	}
+	if code, body := get("/readyz"); code != http.StatusOK || body["status"] != "ready" {
+		t.Fatalf("/readyz = %d %v", code, body)
+	}
+	get("/readyz")
+	if probes != 1 {
+		t.Fatalf("probes = %d, want repeated checks served from cache", probes)
+	}
+
+	status = http.StatusUnauthorized
+	s.readiness.checkedAt = s.readiness.checkedAt.Add(-readinessCacheTTL)
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || body["error"] == "" {
+		t.Fatalf("/readyz with rejected credentials = %d %v", code, body)
+	}
+
+	s.passthroughAuth = true
+	s.readiness.checkedAt = s.readiness.checkedAt.Add(-readinessCacheTTL)
+	if code, _ := get("/readyz"); code != http.StatusOK {
+		t.Fatalf("passthrough mode has no shared key to reject, got %d", code)
+	}
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Fatalf("/healthz must not require caller credentials, got %d", code)
+	}
+}
+
+func TestReadinessUnderPassthroughWithoutSharedKey(t *testing.T) {
+	var gotAuth []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"missing credentials"}`))
+	}))
+	s := &Server{client: &kaizenAPIClient{baseURL: backend.URL, httpClient: backend.Client(), passthroughAuth: true}, passthroughAuth: true}
+	hs := newHTTPTestServer(t, s)
+
+	resp, err := http.Get(hs.URL + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/readyz = %d, want ready when the backend answers without a shared key", resp.StatusCode)
+	}
+	if len(gotAuth) != 1 || gotAuth[0] != "" {
+		t.Fatalf("probe should reach the backend without credentials, got %q", gotAuth)
+	}
+
+	backend.Close()
+	s.readiness.checkedAt = s.readiness.checkedAt.Add(-readinessCacheTTL)
+	resp, err = http.Get(hs.URL + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("/readyz with the backend down = %d, want 503", resp.StatusCode)
+	}
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxHTTPMessageBytes = 4 << 20
+
+// ListenAndServe serves MCP over HTTP on addr. Clients POST one JSON-RPC
+// message per request to /mcp and receive the response as JSON; messages
+// without an id (notifications) are acknowledged with 202 Accepted.
+func (s *Server) ListenAndServe(addr string) error {
+	if !s.passthroughAuth && s.httpToken == "" {
+		// Every call would run with the shared key, so without a token
+		// only local clients may reach the server.
+		if err := checkLoopback(addr); err != nil {
+			return fmt.Errorf("invalid http address %q without KAIZEN_MCP_HTTP_TOKEN or KAIZEN_API_PASSTHROUGH_AUTH: %w", addr, err)
+		}
+	}
+	s.logger.Info("serving mcp over http", "addr", addr, "passthrough_auth", s.passthroughAuth)
+	// HTTP has no channel for server-initiated messages; clients see
+	// refreshed tools on their next tools/list.
+	go s.watchDiscoveredTools(context.Background(), nil)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.httpHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+func (s *Server) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", s.handleHTTPMessage)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/metrics", s.requireHTTPToken(s.handleMetrics))
+	return mux
+}
+
+func (s *Server) handleHTTPMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeHTTPError(w, http.StatusMethodNotAllowed, "use POST to send JSON-RPC messages")
+		return
+	}
+
+	// Continue the caller's trace, e.g. an agent gateway that is itself
+	// instrumented.
+	ctx := withTraceparent(r.Context(), r.Header.Get(traceparentHeader))
+	authenticated := true
+	switch {
+	case s.passthroughAuth:
+		// Each caller authenticates to Kaizen as themselves; never fall
+		// back to the shared key, or backend authz would stop reflecting
+		// the actual user.
+		token := bearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeHTTPError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		ctx = withCallerCredential(ctx, token)
+	case s.httpToken != "":
+		if !s.validHTTPToken(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeHTTPError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+	default:
+		// A loopback-only server: anyone local may call it.
+		authenticated = false
+	}
+	if id := strings.TrimSpace(r.Header.Get(requestIDHeader)); id != "" && !strings.ContainsAny(id, "\r\n") {
+		ctx = withRequestID(ctx, id)
+	}
+	if session := strings.TrimSpace(r.Header.Get(sessionHeader)); session != "" {
+		ctx = withSession(ctx, session)
+	}
+	if workspace := strings.TrimSpace(r.Header.Get(workspaceHeader)); workspace != "" && authenticated {
+		// Lets a multi-tenant gateway pick the workspace for its session.
+		ctx = withWorkspace(ctx, workspace)
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxHTTPMessageBytes+1))
+	if err != nil {
+		s.frames.readError("http", err)
+		writeHTTPError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	if len(payload) > maxHTTPMessageBytes {
+		writeHTTPError(w, http.StatusRequestEntityTooLarge, "message exceeds "+strconv.Itoa(maxHTTPMessageBytes)+" bytes")
+		return
+	}
+
+	s.frames.payload("http", frameInbound, payload)
+
+	resp := s.handleMessage(ctx, payload)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	s.frames.message("http", frameOutbound, *resp)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Warn("failed to write http response", "error", err)
+	}
+}
+
+// requireHTTPToken guards an endpoint with KAIZEN_MCP_HTTP_TOKEN when one
+// is configured.
+func (s *Server) requireHTTPToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.httpToken != "" && !s.validHTTPToken(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeHTTPError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) validHTTPToken(r *http.Request) bool {
+	token := bearerToken(r.Header.Get("Authorization"))
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.httpToken)) == 1
+}
+
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
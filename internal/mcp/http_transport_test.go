@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newHTTPTestServer(t *testing.T, srv *Server) *httptest.Server {
+	t.Helper()
+	if srv.logger == nil {
+		srv.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	hs := httptest.NewServer(srv.httpHandler())
+	t.Cleanup(hs.Close)
+	return hs
+}
+
+func postMCP(t *testing.T, url, auth, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+"/mcp", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHTTPTransportHandlesRequestsAndNotifications(t *testing.T) {
+	hs := newHTTPTestServer(t, &Server{})
+
+	resp := postMCP(t, hs.URL, "", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var decoded struct {
+		ID     int `json:"id"`
+		Result struct {
+			Tools []toolDefinition `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.ID != 1 || len(decoded.Result.Tools) == 0 {
+		t.Fatalf("unexpected tools/list response: %+v", decoded)
+	}
+
+	resp = postMCP(t, hs.URL, "", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202 for notification, got %d", resp.StatusCode)
+	}
+
+	get, err := http.Get(hs.URL + "/mcp")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	get.Body.Close()
+	if get.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", get.StatusCode)
+	}
+}
+
+func TestHTTPTransportPassesThroughCallerCredentials(t *testing.T) {
+	var gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	srv := &Server{
+		client:          &kaizenAPIClient{baseURL: api.URL, apiKey: "shared-key", httpClient: api.Client()},
+		passthroughAuth: true,
+	}
+	hs := newHTTPTestServer(t, srv)
+	call := `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"enzan.burn","arguments":{}}}`
+
+	resp := postMCP(t, hs.URL, "Bearer user-token", call)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if gotAuth != "Bearer user-token" {
+		t.Fatalf("expected caller token to reach the Kaizen API, got %q", gotAuth)
+	}
+
+	gotAuth = ""
+	resp = postMCP(t, hs.URL, "", call)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without caller token, got %d", resp.StatusCode)
+	}
+	if gotAuth != "" {
+		t.Fatalf("shared key must not be used when passthrough is enabled, got %q", gotAuth)
+	}
+}
+
+func TestHTTPTransportUsesSharedKeyWithoutPassthrough(t *testing.T) {
+	var gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	srv := &Server{client: &kaizenAPIClient{baseURL: api.URL, apiKey: "shared-key", httpClient: api.Client()}}
+	hs := newHTTPTestServer(t, srv)
+	postMCP(t, hs.URL, "Bearer user-token", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"enzan.burn","arguments":{}}}`)
+	if gotAuth != "Bearer shared-key" {
+		t.Fatalf("expected shared key without passthrough, got %q", gotAuth)
+	}
+}
+
+func TestHTTPTransportRequiresTheHTTPTokenWithoutPassthrough(t *testing.T) {
+	var workspaces []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspaces = append(workspaces, r.Header.Get(workspaceHeader))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	srv := &Server{client: &kaizenAPIClient{baseURL: api.URL, apiKey: "shared-key", httpClient: api.Client()}, httpToken: "gateway-token"}
+	hs := newHTTPTestServer(t, srv)
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"enzan.burn","arguments":{}}}`
+
+	for _, auth := range []string{"", "Bearer wrong"} {
+		if resp := postMCP(t, hs.URL, auth, call); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("auth %q: expected 401, got %d", auth, resp.StatusCode)
+		}
+	}
+	metrics, err := http.Get(hs.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics.Body.Close()
+	if metrics.StatusCode != http.StatusUnauthorized || len(workspaces) != 0 {
+		t.Fatalf("expected /metrics to require the token too, got %d", metrics.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, hs.URL+"/mcp", strings.NewReader(call))
+	req.Header.Set("Authorization", "Bearer gateway-token")
+	req.Header.Set(workspaceHeader, "globex")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(workspaces) != 1 || workspaces[0] != "globex" {
+		t.Fatalf("authenticated call: status %d, workspaces %v", resp.StatusCode, workspaces)
+	}
+}
+
+func TestHTTPTransportWithoutTokenIsLoopbackOnly(t *testing.T) {
+	srv := &Server{client: &kaizenAPIClient{apiKey: "shared-key"}}
+	if err := srv.ListenAndServe(":0"); err == nil || !strings.Contains(err.Error(), "KAIZEN_MCP_HTTP_TOKEN") {
+		t.Fatalf("expected a non-loopback address to be refused, got %v", err)
+	}
+
+	var workspaces []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspaces = append(workspaces, r.Header.Get(workspaceHeader))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	srv.client = &kaizenAPIClient{baseURL: api.URL, apiKey: "shared-key", httpClient: api.Client(), workspace: "acme"}
+	hs := newHTTPTestServer(t, srv)
+	req, _ := http.NewRequest(http.MethodPost, hs.URL+"/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"enzan.burn","arguments":{}}}`))
+	req.Header.Set(workspaceHeader, "globex")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(workspaces) != 1 || workspaces[0] != "acme" {
+		t.Fatalf("an unauthenticated caller must not pick the workspace, got %v", workspaces)
+	}
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// akumaFormatRequest holds the arguments of the local akuma.format tool.
+type akumaFormatRequest struct {
+	SQL         string `json:"sql"`
+	Dialect     string `json:"dialect,omitempty"`
+	KeywordCase string `json:"keywordCase,omitempty"`
+	Indent      int    `json:"indent,omitempty"`
+}
+
+// decodeToolArgs maps tool arguments onto a typed request struct. Fields
+// already set on dst act as defaults when the argument is absent. Type
+// mismatches are reported per argument so agents can self-correct.
+func decodeToolArgs(args map[string]interface{}, dst interface{}) error {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return fmt.Errorf("%s must be %s, got %s", typeErr.Field, jsonTypeName(typeErr.Type.Kind().String()), typeErr.Value)
+		}
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func jsonTypeName(kind string) string {
+	switch kind {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "an integer"
+	case "float32", "float64":
+		return "a number"
+	case "string":
+		return "a string"
+	case "bool":
+		return "a boolean"
+	case "slice", "array":
+		return "an array"
+	case "map", "struct":
+		return "an object"
+	default:
+		return kind
+	}
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+func TestBuildAkumaQueryPayloadDecodesTypedRequest(t *testing.T) {
+	req, err := buildAkumaQueryPayload(map[string]interface{}{
+		"dialect":    "postgres",
+		"prompt":     "top customers",
+		"maxRows":    50.0,
+		"guardrails": map[string]interface{}{"blockedTables": []interface{}{"secrets"}},
+	})
+	if err != nil {
+		t.Fatalf("buildAkumaQueryPayload: %v", err)
+	}
+	if req.MaxRows == nil || *req.MaxRows != 50 {
+		t.Fatalf("expected maxRows=50, got %v", req.MaxRows)
+	}
+	raw, _ := json.Marshal(req)
+	var wire map[string]interface{}
+	_ = json.Unmarshal(raw, &wire)
+	if _, ok := wire["mode"]; ok {
+		t.Fatalf("unset optional fields must be omitted on the wire, got %s", raw)
+	}
+	if wire["dialect"] != "postgres" || wire["prompt"] != "top customers" {
+		t.Fatalf("unexpected wire payload: %s", raw)
+	}
+}
+
+func TestDecodeToolArgsReportsFieldTypeErrors(t *testing.T) {
+	_, err := buildAkumaQueryPayload(map[string]interface{}{
+		"dialect": "postgres",
+		"prompt":  "top customers",
+		"maxRows": "fifty",
+	})
+	if err == nil || err.Error() != "maxRows must be an integer, got string" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeToolArgsKeepsDefaults(t *testing.T) {
+	req := kaizen.EnzanSummaryRequest{Window: "24h"}
+	if err := decodeToolArgs(map[string]interface{}{"groupBy": []interface{}{"model"}}, &req); err != nil {
+		t.Fatalf("decodeToolArgs: %v", err)
+	}
+	if req.Window != "24h" || len(req.GroupBy) != 1 || req.GroupBy[0] != "model" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// logExporter ships log records to an OpenTelemetry collector as OTLP/HTTP
+// JSON, batched like spans, so logs land in the same pipeline as traces.
+// A nil logExporter (log export off) exports nothing.
+type logExporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	httpClient  *http.Client
+	onError     func(error)
+
+	mu      sync.Mutex
+	pending []map[string]interface{}
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	stopped sync.Once
+	done    chan struct{}
+}
+
+// newLogExporterFromEnv configures log export from the standard
+// OpenTelemetry variables. Unlike tracing it is opt-in even when
+// OTEL_EXPORTER_OTLP_ENDPOINT is set: it needs OTEL_LOGS_EXPORTER=otlp or
+// a logs-specific endpoint.
+func newLogExporterFromEnv(onError func(error)) (*logExporter, error) {
+	if getEnv("OTEL_LOGS_EXPORTER", "") != "otlp" && getEnv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", "") == "" {
+		return nil, nil
+	}
+	endpoint, headers, err := otlpEndpointFromEnv("logs")
+	if endpoint == "" || err != nil {
+		return nil, err
+	}
+	e := newLogExporter(endpoint, headers, getEnv("OTEL_SERVICE_NAME", serverName), onError)
+	go e.run()
+	return e, nil
+}
+
+func newLogExporter(endpoint string, headers map[string]string, serviceName string, onError func(error)) *logExporter {
+	return &logExporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		httpClient:  &http.Client{Timeout: traceExportTimeout},
+		onError:     onError,
+		flushCh:     make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (e *logExporter) enqueue(record map[string]interface{}) {
+	e.mu.Lock()
+	e.pending = append(e.pending, record)
+	full := len(e.pending) >= traceExportBatch
+	e.mu.Unlock()
+	if full {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *logExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(traceExportEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flushCh:
+		case <-e.stopCh:
+			e.flush()
+			return
+		}
+		e.flush()
+	}
+}
+
+// shutdown exports the records still pending and stops the exporter.
+func (e *logExporter) shutdown() {
+	if e == nil {
+		return
+	}
+	e.stopped.Do(func() {
+		close(e.stopCh)
+		<-e.done
+	})
+}
+
+// flush exports every pending record; records that fail to export are
+// dropped, as they are still on stderr.
+func (e *logExporter) flush() {
+	e.mu.Lock()
+	batch := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+	err := postOTLP(e.httpClient, e.endpoint, e.headers, "logs", map[string]interface{}{
+		"resourceLogs": []interface{}{map[string]interface{}{
+			"resource": otlpResource(e.serviceName),
+			"scopeLogs": []interface{}{map[string]interface{}{
+				"scope":      otlpScope(),
+				"logRecords": batch,
+			}},
+		}},
+	})
+	if err != nil && e.onError != nil {
+		e.onError(err)
+	}
+}
+
+// otlpLogHandler is the slog.Handler that feeds a logExporter. It applies
+// the redactor itself, since the JSON handler's ReplaceAttr hook only
+// covers what reaches stderr.
+type otlpLogHandler struct {
+	exporter *logExporter
+	redactor *redactor
+	level    slog.Leveler
+	attrs    map[string]interface{}
+	prefix   string
+}
+
+func newOTLPLogHandler(exporter *logExporter, redactor *redactor, level slog.Leveler) *otlpLogHandler {
+	return &otlpLogHandler{exporter: exporter, redactor: redactor, level: level, attrs: map[string]interface{}{}}
+}
+
+func (h *otlpLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *otlpLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	attributes := make(map[string]interface{}, len(h.attrs)+record.NumAttrs())
+	for key, value := range h.attrs {
+		attributes[key] = value
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		h.addAttr(attributes, h.prefix, attr)
+		return true
+	})
+	// OTLP severity numbers put DEBUG, INFO, WARN, and ERROR at 5, 9, 13,
+	// and 17, four apart like slog's levels.
+	severity := int(record.Level) + 9
+	if severity < 1 {
+		severity = 1
+	}
+	encoded := map[string]interface{}{
+		"timeUnixNano":         strconv.FormatInt(record.Time.UnixNano(), 10),
+		"observedTimeUnixNano": strconv.FormatInt(time.Now().UnixNano(), 10),
+		"severityNumber":       severity,
+		"severityText":         record.Level.String(),
+		"body":                 map[string]interface{}{"stringValue": h.redactor.text(record.Message)},
+		"attributes":           otlpAttributes(attributes),
+	}
+	if sp, ok := ctx.Value(spanContextKey{}).(*span); ok {
+		encoded["traceId"], encoded["spanId"] = sp.traceID, sp.spanID
+	}
+	h.exporter.enqueue(encoded)
+	return nil
+}
+
+func (h *otlpLogHandler) addAttr(attributes map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, member := range attr.Value.Group() {
+			h.addAttr(attributes, prefix+attr.Key+".", member)
+		}
+		return
+	}
+	attr = h.redactor.replaceAttr(nil, attr)
+	switch attr.Value.Kind() {
+	case slog.KindInt64:
+		attributes[prefix+attr.Key] = int(attr.Value.Int64())
+	case slog.KindBool:
+		attributes[prefix+attr.Key] = attr.Value.Bool()
+	default:
+		attributes[prefix+attr.Key] = attr.Value.String()
+	}
+}
+
+func (h *otlpLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.attrs = make(map[string]interface{}, len(h.attrs)+len(attrs))
+	for key, value := range h.attrs {
+		derived.attrs[key] = value
+	}
+	for _, attr := range attrs {
+		h.addAttr(derived.attrs, h.prefix, attr)
+	}
+	return &derived
+}
+
+func (h *otlpLogHandler) WithGroup(name string) slog.Handler {
+	derived := *h
+	derived.prefix = h.prefix + name + "."
+	return &derived
+}
+
+// teeHandler sends every record to each of its handlers, e.g. stderr and
+// an OTLP collector.
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := make(teeHandler, len(t))
+	for i, h := range t {
+		derived[i] = h.WithAttrs(attrs)
+	}
+	return derived
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	derived := make(teeHandler, len(t))
+	for i, h := range t {
+		derived[i] = h.WithGroup(name)
+	}
+	return derived
+}
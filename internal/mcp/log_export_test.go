@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogExportShipsRedactedRecords(t *testing.T) {
+	var exports []map[string]interface{}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("X-Honeycomb-Team") != "team" {
+			t.Errorf("unexpected export %s with headers %v", r.URL.Path, r.Header)
+		}
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		exports = append(exports, body)
+	}))
+	defer collector.Close()
+
+	redactor, _ := newRedactor(nil)
+	redactor.addSecret("sk-live-supersecret")
+	exporter := newLogExporter(collector.URL+"/v1/logs", map[string]string{"X-Honeycomb-Team": "team"}, "kaizen-mcp-test", nil)
+	var stderr bytes.Buffer
+	logger := slog.New(teeHandler{
+		slog.NewJSONHandler(&stderr, &slog.HandlerOptions{ReplaceAttr: redactor.replaceAttr}),
+		newOTLPLogHandler(exporter, redactor, slog.LevelInfo),
+	}).With("component", "test")
+
+	logger.Debug("dropped below the level")
+	logger.Warn("backend rejected sk-live-supersecret", "status", 401, "api_key", "abc", "retry", false)
+	ctx, sp := newTracer("http://127.0.0.1:0", nil, "kaizen-mcp-test", nil).start(context.Background(), "tools/call", spanKindServer)
+	logger.InfoContext(ctx, "tool call", slog.Group("http", "path", "/v1/enzan/burn"))
+	exporter.flush()
+
+	if !strings.Contains(stderr.String(), "backend rejected") {
+		t.Fatalf("stderr lost the record: %s", stderr.String())
+	}
+	if len(exports) != 1 {
+		t.Fatalf("exports = %d, want one batch", len(exports))
+	}
+	encoded := mustJSON(t, exports[0])
+	if strings.Contains(encoded, "sk-live-supersecret") || strings.Contains(encoded, `"abc"`) || strings.Contains(encoded, "dropped below") {
+		t.Fatalf("export leaked a secret or a filtered record: %s", encoded)
+	}
+	resource := exports[0]["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	records := resource["scopeLogs"].([]interface{})[0].(map[string]interface{})["logRecords"].([]interface{})
+	if len(records) != 2 {
+		t.Fatalf("records = %v", records)
+	}
+	warn, info := records[0].(map[string]interface{}), records[1].(map[string]interface{})
+	if warn["severityText"] != "WARN" || warn["severityNumber"] != 13.0 || info["severityNumber"] != 9.0 {
+		t.Fatalf("unexpected severities: %v / %v", warn, info)
+	}
+	for _, want := range []string{
+		`{"key":"component","value":{"stringValue":"test"}}`,
+		`{"key":"status","value":{"intValue":"401"}}`,
+		`{"key":"api_key","value":{"stringValue":"[REDACTED]"}}`,
+		`{"key":"retry","value":{"boolValue":false}}`,
+	} {
+		if !strings.Contains(mustJSON(t, warn["attributes"]), want) {
+			t.Fatalf("warn attributes lack %s: %v", want, warn["attributes"])
+		}
+	}
+	if !strings.Contains(mustJSON(t, info["attributes"]), `{"key":"http.path","value":{"stringValue":"/v1/enzan/burn"}}`) {
+		t.Fatalf("group not flattened: %v", info["attributes"])
+	}
+	if info["traceId"] != sp.traceID || info["spanId"] != sp.spanID {
+		t.Fatalf("record not correlated with its span: %v", info)
+	}
+}
+
+func TestLogExportIsOptIn(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", "")
+	t.Setenv("OTEL_LOGS_EXPORTER", "")
+	if e, err := newLogExporterFromEnv(nil); e != nil || err != nil {
+		t.Fatalf("log export should need OTEL_LOGS_EXPORTER=otlp, got %v, %v", e, err)
+	}
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+	e, err := newLogExporterFromEnv(nil)
+	if err != nil || e == nil || e.endpoint != "http://collector:4318/v1/logs" {
+		t.Fatalf("exporter = %+v, %v", e, err)
+	}
+	e.shutdown()
+}
+
+func TestShutdownExportsTheFatalLogLine(t *testing.T) {
+	var exported bytes.Buffer
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = exported.ReadFrom(r.Body)
+	}))
+	defer collector.Close()
+
+	exporter := newLogExporter(collector.URL+"/v1/logs", nil, "kaizen-mcp-test", nil)
+	go exporter.run()
+	s := &Server{logExporter: exporter, logger: slog.New(newOTLPLogHandler(exporter, nil, slog.LevelInfo))}
+	s.LogFatal(context.Canceled)
+	s.Shutdown()
+	if !strings.Contains(exported.String(), "mcp server stopped with error") {
+		t.Fatalf("the fatal record was not exported: %s", exported.String())
+	}
+}
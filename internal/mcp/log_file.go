@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogMaxBytes   = 10 << 20
+	defaultLogMaxBackups = 5
+)
+
+// logOutput is where the server logs: stderr, plus KAIZEN_MCP_LOG_FILE
+// when set, since some MCP clients discard a server's stderr.
+func logOutput() (io.Writer, error) {
+	path := getEnv("KAIZEN_MCP_LOG_FILE", "")
+	if path == "" {
+		return os.Stderr, nil
+	}
+	maxBytes, err := strconv.ParseInt(getEnv("KAIZEN_MCP_LOG_MAX_BYTES", strconv.Itoa(defaultLogMaxBytes)), 10, 64)
+	if err != nil || maxBytes < 0 {
+		return nil, fmt.Errorf("invalid KAIZEN_MCP_LOG_MAX_BYTES: must be a non-negative byte count")
+	}
+	var maxAge time.Duration
+	if raw := getEnv("KAIZEN_MCP_LOG_MAX_AGE", ""); raw != "" {
+		if maxAge, err = time.ParseDuration(raw); err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("invalid KAIZEN_MCP_LOG_MAX_AGE %q: expected a positive duration such as 24h", raw)
+		}
+	}
+	maxBackups, err := strconv.Atoi(getEnv("KAIZEN_MCP_LOG_MAX_BACKUPS", strconv.Itoa(defaultLogMaxBackups)))
+	if err != nil || maxBackups < 0 {
+		return nil, fmt.Errorf("invalid KAIZEN_MCP_LOG_MAX_BACKUPS: must be a non-negative count")
+	}
+	file, err := openRotatingFile(path, maxBytes, maxAge, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return io.MultiWriter(os.Stderr, file), nil
+}
+
+// rotatingFile is a log file that is renamed aside once it grows past
+// maxBytes or is older than maxAge, keeping at most maxBackups old files.
+// Zero maxBytes or maxAge disables that trigger.
+type rotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func openRotatingFile(path string, maxBytes int64, maxAge time.Duration, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxBytes: maxBytes, maxAge: maxAge, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	// An existing file keeps aging from when it was last modified, so
+	// restarts do not postpone age-based rotation forever.
+	f.file, f.size, f.openedAt = file, info.Size(), time.Now()
+	if info.Size() > 0 {
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Write appends p, rotating first when p would push the file past
+// maxBytes or the file has outlived maxAge. Every log line is written
+// whole to one file.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	oversized := f.maxBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxBytes
+	expired := f.maxAge > 0 && time.Since(f.openedAt) > f.maxAge
+	if oversized || expired {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	backup := f.path + "." + time.Now().UTC().Format("20060102T150405.000000000Z")
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.openedAt = time.Now()
+	return f.pruneBackups()
+}
+
+// pruneBackups removes the oldest rotated files beyond maxBackups. The
+// timestamp suffix sorts chronologically.
+func (f *rotatingFile) pruneBackups() error {
+	backups, err := filepath.Glob(f.path + ".[0-9]*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups)
+	for len(backups) > f.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("failed to remove old log file: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kaizen-mcp.log")
+	f, err := openRotatingFile(path, 20, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := f.Write([]byte("0123456789abcdef\n")); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want the two newest", backups)
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "0123456789abcdef\n" {
+		t.Fatalf("current file = %q, want only the last line", current)
+	}
+}
+
+func TestRotatingFileRotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kaizen-mcp.log")
+	f, err := openRotatingFile(path, 0, time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte("old\n"))
+	f.openedAt = time.Now().Add(-2 * time.Hour)
+	_, _ = f.Write([]byte("new\n"))
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want one", backups)
+	}
+	if old, _ := os.ReadFile(backups[0]); string(old) != "old\n" {
+		t.Fatalf("backup = %q", old)
+	}
+	if current, _ := os.ReadFile(path); string(current) != "new\n" {
+		t.Fatalf("current file = %q", current)
+	}
+}
+
+func TestLogOutputRejectsInvalidSettings(t *testing.T) {
+	t.Setenv("KAIZEN_MCP_LOG_FILE", filepath.Join(t.TempDir(), "kaizen-mcp.log"))
+	t.Setenv("KAIZEN_MCP_LOG_MAX_AGE", "soon")
+	if _, err := logOutput(); err == nil || !strings.Contains(err.Error(), "KAIZEN_MCP_LOG_MAX_AGE") {
+		t.Fatalf("expected a KAIZEN_MCP_LOG_MAX_AGE error, got %v", err)
+	}
+}
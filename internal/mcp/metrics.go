@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// handleMetrics serves the mcp.stats counters in the Prometheus text
+// format, with a latency histogram and error counter per tool so SLOs can
+// be set on each tool separately.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	s.stats.writePrometheus(w)
+}
+
+func (st *serverStats) writePrometheus(w io.Writer) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	names := make([]string, 0, len(st.tools))
+	for name := range st.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP kaizen_mcp_tool_calls_total Tool calls handled, by tool.")
+	fmt.Fprintln(w, "# TYPE kaizen_mcp_tool_calls_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "kaizen_mcp_tool_calls_total{tool=%q} %d\n", name, st.tools[name].calls)
+	}
+	fmt.Fprintln(w, "# HELP kaizen_mcp_tool_errors_total Tool calls that returned a tool error, by tool.")
+	fmt.Fprintln(w, "# TYPE kaizen_mcp_tool_errors_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "kaizen_mcp_tool_errors_total{tool=%q} %d\n", name, st.tools[name].errors)
+	}
+	fmt.Fprintln(w, "# HELP kaizen_mcp_tool_duration_seconds Tool call latency, by tool.")
+	fmt.Fprintln(w, "# TYPE kaizen_mcp_tool_duration_seconds histogram")
+	for _, name := range names {
+		entry := st.tools[name]
+		for i, count := range entry.cumulativeBuckets() {
+			le := strconv.FormatFloat(latencyBuckets[i].Seconds(), 'g', -1, 64)
+			fmt.Fprintf(w, "kaizen_mcp_tool_duration_seconds_bucket{tool=%q,le=%q} %d\n", name, le, count)
+		}
+		fmt.Fprintf(w, "kaizen_mcp_tool_duration_seconds_bucket{tool=%q,le=\"+Inf\"} %d\n", name, entry.calls)
+		fmt.Fprintf(w, "kaizen_mcp_tool_duration_seconds_sum{tool=%q} %g\n", name, entry.elapsed.Seconds())
+		fmt.Fprintf(w, "kaizen_mcp_tool_duration_seconds_count{tool=%q} %d\n", name, entry.calls)
+	}
+
+	caches := make([]string, 0, len(st.caches))
+	for name := range st.caches {
+		caches = append(caches, name)
+	}
+	sort.Strings(caches)
+	fmt.Fprintln(w, "# HELP kaizen_mcp_cache_lookups_total Cache lookups, by cache and result.")
+	fmt.Fprintln(w, "# TYPE kaizen_mcp_cache_lookups_total counter")
+	for _, name := range caches {
+		fmt.Fprintf(w, "kaizen_mcp_cache_lookups_total{cache=%q,result=\"hit\"} %d\n", name, st.caches[name].hits)
+		fmt.Fprintf(w, "kaizen_mcp_cache_lookups_total{cache=%q,result=\"miss\"} %d\n", name, st.caches[name].misses)
+	}
+}
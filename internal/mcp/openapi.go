@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// openAPIRemote tells KAIZEN_API_OPENAPI to fetch the spec from the
+	// backend instead of reading a file.
+	openAPIRemote   = "api"
+	openAPISpecPath = "/openapi.json"
+	// openAPIRefDepth bounds $ref inlining so recursive schemas terminate.
+	openAPIRefDepth = 16
+)
+
+var openAPIMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// loadOpenAPITools generates tools from the Kaizen OpenAPI document named
+// by KAIZEN_API_OPENAPI (a JSON file path, or "api" to fetch /openapi.json
+// from the backend). Only operations carrying one of the tags in
+// KAIZEN_API_OPENAPI_TAGS (default "mcp") are exposed. Tools that are
+// already registered win over generated ones.
+func loadOpenAPITools(client *kaizenAPIClient, registry *Registry, logger *slog.Logger) error {
+	source := getEnv("KAIZEN_API_OPENAPI", "")
+	if source == "" {
+		return nil
+	}
+
+	var doc map[string]interface{}
+	if source == openAPIRemote {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		fetched, err := client.call(ctx, http.MethodGet, openAPISpecPath, nil)
+		if err != nil {
+			// The backend may simply be unavailable right now; keep
+			// serving the built-in tools rather than refusing to start.
+			logger.Warn("failed to fetch kaizen openapi spec", "error", err.Error())
+			return nil
+		}
+		doc = fetched
+	} else {
+		raw, err := os.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("failed to read KAIZEN_API_OPENAPI: %w", err)
+		}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return fmt.Errorf("failed to parse KAIZEN_API_OPENAPI %q: %w", source, err)
+		}
+	}
+
+	tags := map[string]bool{}
+	for _, tag := range strings.Split(getEnv("KAIZEN_API_OPENAPI_TAGS", "mcp"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags[tag] = true
+		}
+	}
+
+	for _, op := range openAPIOperations(doc, tags) {
+		if _, exists := registry.lookup(op.name); exists {
+			logger.Warn("skipping openapi operation that shadows an existing tool", "tool", op.name)
+			continue
+		}
+		var opts []toolOption
+		if op.mutating {
+			opts = append(opts, mutating())
+		}
+		if op.deprecated {
+			opts = append(opts, deprecated(op.replacedBy))
+		}
+		registry.register(op.name, op.def, op.tool.handler(), opts...)
+	}
+	return nil
+}
+
+type openAPIOperation struct {
+	name     string
+	def      toolDefinition
+	tool     restTool
+	mutating bool
+	// deprecated mirrors the operation's standard deprecated flag;
+	// x-mcp-replaced-by names the successor tool.
+	deprecated bool
+	replacedBy string
+}
+
+// openAPIOperations converts tagged operations, in path then method
+// order, into tool definitions and REST handlers.
+func openAPIOperations(doc map[string]interface{}, tags map[string]bool) []openAPIOperation {
+	paths, _ := doc["paths"].(map[string]interface{})
+	pathKeys := make([]string, 0, len(paths))
+	for path := range paths {
+		pathKeys = append(pathKeys, path)
+	}
+	sort.Strings(pathKeys)
+
+	var ops []openAPIOperation
+	for _, path := range pathKeys {
+		item, _ := resolveOpenAPIRefs(doc, paths[path], 0).(map[string]interface{})
+		for _, method := range openAPIMethods {
+			op, ok := item[strings.ToLower(method)].(map[string]interface{})
+			if !ok || !hasOpenAPITag(op, tags) {
+				continue
+			}
+			name := openAPIToolName(op)
+			if name == "" {
+				continue
+			}
+			shared, _ := item["parameters"].([]interface{})
+			ops = append(ops, buildOpenAPIOperation(doc, name, method, path, op, shared))
+		}
+	}
+	return ops
+}
+
+func buildOpenAPIOperation(doc map[string]interface{}, name, method, path string, op map[string]interface{}, shared []interface{}) openAPIOperation {
+	properties := map[string]interface{}{}
+	required := []string{}
+	queryArgs := []string{}
+
+	params, _ := op["parameters"].([]interface{})
+	for _, raw := range append(append([]interface{}{}, shared...), params...) {
+		param, ok := resolveOpenAPIRefs(doc, raw, 0).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		paramName, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if paramName == "" || (in != "path" && in != "query") {
+			continue
+		}
+		schema, _ := param["schema"].(map[string]interface{})
+		if schema == nil {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		if desc, ok := param["description"].(string); ok && desc != "" {
+			schema = withDescription(schema, desc)
+		}
+		properties[paramName] = schema
+		if isRequired, _ := param["required"].(bool); isRequired || in == "path" {
+			required = append(required, paramName)
+		}
+		if in == "query" {
+			queryArgs = append(queryArgs, paramName)
+		}
+	}
+
+	tool := restTool{method: method, path: path, queryArgs: queryArgs}
+	if body := openAPIJSONBodySchema(doc, op); body != nil {
+		bodyProps, isObject := body["properties"].(map[string]interface{})
+		if isObject && !hasPropertyConflict(properties, bodyProps) {
+			for key, value := range bodyProps {
+				properties[key] = value
+			}
+			if bodyRequired, ok := body["required"].([]interface{}); ok {
+				for _, item := range bodyRequired {
+					if text, ok := item.(string); ok {
+						required = append(required, text)
+					}
+				}
+			}
+		} else {
+			tool.bodyArg = "body"
+			properties["body"] = body
+			required = append(required, "body")
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	description, _ := op["summary"].(string)
+	if text, _ := op["description"].(string); text != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += text
+	}
+	if description == "" {
+		description = fmt.Sprintf("%s %s", method, path)
+	}
+	// Non-GET operations are assumed to change state unless the spec
+	// says otherwise with x-mcp-read-only.
+	readOnly, _ := op["x-mcp-read-only"].(bool)
+	isDeprecated, _ := op["deprecated"].(bool)
+	replacedBy, _ := op["x-mcp-replaced-by"].(string)
+	return openAPIOperation{
+		name:       name,
+		def:        toolDefinition{Description: description, InputSchema: schema},
+		tool:       tool,
+		mutating:   method != http.MethodGet && !readOnly,
+		deprecated: isDeprecated,
+		replacedBy: replacedBy,
+	}
+}
+
+func hasOpenAPITag(op map[string]interface{}, tags map[string]bool) bool {
+	list, _ := op["tags"].([]interface{})
+	for _, tag := range list {
+		if text, ok := tag.(string); ok && tags[text] {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIToolName prefers the x-mcp-name extension, then operationId.
+func openAPIToolName(op map[string]interface{}) string {
+	if name, _ := op["x-mcp-name"].(string); name != "" {
+		return name
+	}
+	name, _ := op["operationId"].(string)
+	return name
+}
+
+func openAPIJSONBodySchema(doc map[string]interface{}, op map[string]interface{}) map[string]interface{} {
+	body, ok := resolveOpenAPIRefs(doc, op["requestBody"], 0).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	content, _ := body["content"].(map[string]interface{})
+	media, _ := content["application/json"].(map[string]interface{})
+	schema, _ := media["schema"].(map[string]interface{})
+	return schema
+}
+
+func hasPropertyConflict(a, b map[string]interface{}) bool {
+	for key := range b {
+		if _, ok := a[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func withDescription(schema map[string]interface{}, description string) map[string]interface{} {
+	out := make(map[string]interface{}, len(schema)+1)
+	for key, value := range schema {
+		out[key] = value
+	}
+	if _, ok := out["description"]; !ok {
+		out["description"] = description
+	}
+	return out
+}
+
+// resolveOpenAPIRefs inlines local "#/..." references. References nested
+// deeper than openAPIRefDepth are replaced by an unconstrained schema.
+func resolveOpenAPIRefs(doc map[string]interface{}, node interface{}, depth int) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if depth >= openAPIRefDepth {
+				return map[string]interface{}{}
+			}
+			return resolveOpenAPIRefs(doc, lookupOpenAPIRef(doc, ref), depth+1)
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = resolveOpenAPIRefs(doc, item, depth)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = resolveOpenAPIRefs(doc, item, depth)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func lookupOpenAPIRef(doc map[string]interface{}, ref string) interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return map[string]interface{}{}
+	}
+	var node interface{} = doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		node = obj[part]
+	}
+	if node == nil {
+		return map[string]interface{}{}
+	}
+	return node
+}
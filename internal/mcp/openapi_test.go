@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testOpenAPISpec = `{
+  "openapi": "3.0.3",
+  "paths": {
+    "/v1/sozo/jobs/{jobId}": {
+      "parameters": [{"name": "jobId", "in": "path", "required": true, "schema": {"type": "string"}}],
+      "get": {
+        "operationId": "getSozoJob",
+        "x-mcp-name": "sozo.job",
+        "tags": ["mcp"],
+        "summary": "Fetch a Sozo job.",
+        "parameters": [{"name": "verbose", "in": "query", "schema": {"type": "boolean"}}]
+      }
+    },
+    "/v1/akuma/annotations": {
+      "post": {
+        "operationId": "akuma.annotate",
+        "tags": ["mcp"],
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Annotation"}}}}
+      }
+    },
+    "/v1/enzan/burn": {
+      "get": {"operationId": "enzan.burn", "tags": ["mcp"]}
+    },
+    "/v1/internal/admin": {
+      "delete": {"operationId": "admin.purge", "tags": ["internal"]}
+    }
+  },
+  "components": {
+    "schemas": {
+      "Annotation": {
+        "type": "object",
+        "properties": {"table": {"type": "string"}, "note": {"type": "string"}},
+        "required": ["table"]
+      }
+    }
+  }
+}`
+
+func TestLoadOpenAPIToolsGeneratesTaggedOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, []byte(testOpenAPISpec), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	t.Setenv("KAIZEN_API_OPENAPI", path)
+	t.Setenv("KAIZEN_API_OPENAPI_TAGS", "")
+
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, nil)
+	defer cleanup()
+	s.tools = newBuiltinToolRegistry()
+	builtinCount := len(s.tools.definitions())
+	if err := loadOpenAPITools(s.client, s.tools, slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+		t.Fatalf("loadOpenAPITools: %v", err)
+	}
+
+	// enzan.burn is already built in and admin.purge is not tagged mcp.
+	if got := len(s.tools.definitions()) - builtinCount; got != 2 {
+		t.Fatalf("expected 2 generated tools, got %d", got)
+	}
+	job, ok := s.tools.lookup("sozo.job")
+	if !ok {
+		t.Fatalf("expected x-mcp-name to name the tool")
+	}
+	if required := job.def.InputSchema["required"].([]string); len(required) != 1 || required[0] != "jobId" {
+		t.Fatalf("unexpected required list: %#v", required)
+	}
+	annotate, _ := s.tools.lookup("akuma.annotate")
+	if _, ok := annotate.def.InputSchema["properties"].(map[string]interface{})["note"]; !ok {
+		t.Fatalf("expected $ref body properties to be flattened: %#v", annotate.def.InputSchema)
+	}
+
+	if _, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"sozo.job","arguments":{"jobId":"j1","verbose":true}}`)); rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	if _, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"akuma.annotate","arguments":{"table":"orders","note":"pii"}}`)); rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	if got := captured[0]; got.Method != "GET" || got.Path != "/v1/sozo/jobs/j1" || got.Query != "verbose=true" || got.Body != "" {
+		t.Fatalf("unexpected GET request: %#v", got)
+	}
+	if got := captured[1]; got.Method != "POST" || got.Body != `{"note":"pii","table":"orders"}` {
+		t.Fatalf("unexpected POST request: %#v", got)
+	}
+}
+
+func TestLoadOpenAPIToolsRejectsUnreadableSpec(t *testing.T) {
+	t.Setenv("KAIZEN_API_OPENAPI", filepath.Join(t.TempDir(), "missing.json"))
+	err := loadOpenAPITools(&kaizenAPIClient{}, newToolRegistry(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err == nil {
+		t.Fatalf("expected error for a missing spec file")
+	}
+}
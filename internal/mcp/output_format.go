@@ -0,0 +1,192 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	outputFormatJSON     = "json"
+	outputFormatMarkdown = "markdown"
+)
+
+// tabular marks a tool whose results are mostly rows and adds the
+// outputFormat argument, so chat clients can ask for markdown tables
+// instead of pretty-printed JSON.
+func tabular() toolOption {
+	return func(t *registeredTool) {
+		t.tabular = true
+		t.addProperty("outputFormat", map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{outputFormatJSON, outputFormatMarkdown},
+			"description": "Text rendering of the result; markdown shows rows as tables. structuredContent is unaffected",
+		})
+	}
+}
+
+// applyOutputFormat renders tabular results as markdown when the call or
+// the server default (KAIZEN_MCP_OUTPUT_FORMAT) asks for it.
+func (s *Server) applyOutputFormat(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if !call.tool.tabular {
+			return next(ctx, call)
+		}
+		raw, args := takeArgument(call.Arguments, "outputFormat")
+		call.Arguments = args
+		format, _ := raw.(string)
+		if format == "" {
+			format = s.outputFormat
+		}
+		result := next(ctx, call)
+		if format != outputFormatMarkdown || result["isError"] == true {
+			return result
+		}
+		data, _ := result["structuredContent"].(map[string]interface{})
+		if text, ok := renderMarkdown(data); ok {
+			replaceTextContent(result, text)
+		}
+		return result
+	}
+}
+
+// renderMarkdown lays data out as markdown: scalar fields as a list, a
+// columns/rows pair and every array of objects as tables, and anything
+// else as inline JSON. It returns false when data holds no table, since
+// markdown would then read no better than JSON.
+func renderMarkdown(data map[string]interface{}) (string, bool) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields, tables []string
+	if columns, rows, ok := columnRows(data); ok {
+		tables = append(tables, markdownTable(columns, rows))
+	}
+	for _, key := range keys {
+		if (key == "columns" || key == "rows") && len(tables) > 0 {
+			continue
+		}
+		if objects, ok := objectList(data[key]); ok {
+			columns, rows := objectRows(objects)
+			tables = append(tables, "### "+key+"\n\n"+markdownTable(columns, rows))
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("- **%s**: %s", key, markdownCell(data[key])))
+	}
+	if len(tables) == 0 {
+		return "", false
+	}
+	parts := tables
+	if len(fields) > 0 {
+		parts = append([]string{strings.Join(fields, "\n")}, tables...)
+	}
+	return strings.Join(parts, "\n\n"), true
+}
+
+// columnRows reads the {"columns": [...], "rows": [[...]]} shape Akuma
+// uses for query results.
+func columnRows(data map[string]interface{}) ([]string, [][]interface{}, bool) {
+	rawColumns, ok := data["columns"].([]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	rawRows, ok := data["rows"].([]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	columns := make([]string, len(rawColumns))
+	for i, column := range rawColumns {
+		columns[i] = fmt.Sprint(column)
+	}
+	rows := make([][]interface{}, 0, len(rawRows))
+	for _, raw := range rawRows {
+		row, ok := raw.([]interface{})
+		if !ok {
+			return nil, nil, false
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, true
+}
+
+// objectList matches a non-empty array whose items are all objects.
+func objectList(value interface{}) ([]map[string]interface{}, bool) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, false
+	}
+	objects := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		if objects[i], ok = item.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return objects, true
+}
+
+// objectRows turns objects into rows over the union of their keys. Keys
+// are sorted, since JSON objects arrive without an order.
+func objectRows(objects []map[string]interface{}) ([]string, [][]interface{}) {
+	seen := map[string]bool{}
+	var columns []string
+	for _, object := range objects {
+		for key := range object {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	rows := make([][]interface{}, len(objects))
+	for i, object := range objects {
+		rows[i] = make([]interface{}, len(columns))
+		for j, column := range columns {
+			rows[i][j] = object[column]
+		}
+	}
+	return columns, rows
+}
+
+func markdownTable(columns []string, rows [][]interface{}) string {
+	var b strings.Builder
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = markdownCell(column)
+	}
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(columns)))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i := range columns {
+			if i < len(row) {
+				cells[i] = markdownCell(row[i])
+			}
+		}
+		b.WriteString("\n| " + strings.Join(cells, " | ") + " |")
+	}
+	return b.String()
+}
+
+// markdownCell renders one value on a single line. Strings are shown
+// bare and everything else as compact JSON; pipes are escaped so they
+// cannot split a table cell.
+func markdownCell(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		text = v
+	default:
+		encoded, _ := json.Marshal(v)
+		text = string(encoded)
+	}
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
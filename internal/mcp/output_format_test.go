@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want string
+		ok   bool
+	}{
+		{
+			name: "akuma rows",
+			data: `{"sql":"SELECT day, n FROM t","columns":["day","n"],"rows":[["2024-06-03",412],["2024-06-04",null]]}`,
+			want: "- **sql**: SELECT day, n FROM t\n\n| day | n |\n| --- | --- |\n| 2024-06-03 | 412 |\n| 2024-06-04 |  |",
+			ok:   true,
+		},
+		{
+			name: "array of objects",
+			data: `{"dimension":"team","groups":[{"key":"ml|research","costUSD":920.5},{"key":"infra","tags":["a"]}]}`,
+			want: "- **dimension**: team\n\n### groups\n\n| costUSD | key | tags |\n| --- | --- | --- |\n| 920.5 | ml\\|research |  |\n|  | infra | [\"a\"] |",
+			ok:   true,
+		},
+		{
+			name: "no table",
+			data: `{"status":"ok","total":3}`,
+			ok:   false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.data), &data); err != nil {
+				t.Fatal(err)
+			}
+			got, ok := renderMarkdown(data)
+			if ok != tc.ok || got != tc.want {
+				t.Fatalf("got (%v):\n%s\nwant (%v):\n%s", ok, got, tc.ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutputFormatArgumentAndDefault(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/preview": `{"records":[{"id":1,"email":"a@example.com"}]}`,
+	})
+	defer cleanup()
+
+	text := func(args map[string]interface{}) string {
+		t.Helper()
+		raw, _ := json.Marshal(toolsCallParams{Name: "sozo.preview", Arguments: args})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		content := result.(map[string]interface{})["content"].([]map[string]string)
+		if _, ok := result.(map[string]interface{})["structuredContent"]; !ok {
+			t.Fatalf("structuredContent missing: %#v", result)
+		}
+		return content[0]["text"]
+	}
+
+	table := "### records\n\n| email | id |\n| --- | --- |\n| a@example.com | 1 |"
+	if got := text(map[string]interface{}{"schemaName": "users", "outputFormat": "markdown"}); got != table {
+		t.Fatalf("markdown text = %q", got)
+	}
+	if captured[0].Body != `{"schemaName":"users","records":5}` {
+		t.Fatalf("outputFormat should not be forwarded: %s", captured[0].Body)
+	}
+	if got := text(map[string]interface{}{"schemaName": "users"}); got == table {
+		t.Fatalf("json should stay the default")
+	}
+
+	s.outputFormat = outputFormatMarkdown
+	if got := text(map[string]interface{}{"schemaName": "users"}); got != table {
+		t.Fatalf("server default ignored, text = %q", got)
+	}
+	if got := text(map[string]interface{}{"schemaName": "users", "outputFormat": "json"}); got == table {
+		t.Fatalf("argument should override the server default")
+	}
+}
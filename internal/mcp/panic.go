@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+type toolPanicContextKey struct{}
+
+// toolPanic records a panic recovered inside the middleware chain, so
+// handleToolCall can answer the call with a JSON-RPC internal error.
+type toolPanic struct {
+	recovered bool
+	requestID string
+}
+
+// withToolPanic lets recoverToolPanics report a panic for the rest of one
+// tool call.
+func withToolPanic(ctx context.Context) (context.Context, *toolPanic) {
+	p := &toolPanic{}
+	return context.WithValue(ctx, toolPanicContextKey{}, p), p
+}
+
+// recoverToolPanics recovers a panic in a handler or middleware so that it
+// fails only its own call instead of the stdio session of every other one.
+// It runs just inside the request ID, tracing, and stats layers, so a
+// panicking call is still logged, traced, and counted as an error;
+// handleToolCall then turns it into a JSON-RPC internal error.
+func (s *Server) recoverToolPanics(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) (result map[string]interface{}) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				s.logPanic(ctx, call.Name, recovered, debug.Stack())
+				result = toolPanicked(ctx, call.Name)
+			}
+		}()
+		return next(ctx, call)
+	}
+}
+
+// toolPanicked reports a recovered panic to handleToolCall and returns the
+// tool error the middleware layers outside the recovery see.
+func toolPanicked(ctx context.Context, tool string) map[string]interface{} {
+	if p, _ := ctx.Value(toolPanicContextKey{}).(*toolPanic); p != nil {
+		p.recovered, p.requestID = true, requestIDFrom(ctx)
+	}
+	return toolErrorResult(fmt.Sprintf("%s failed unexpectedly", tool))
+}
+
+// panicError is a panic recovered on a goroutine the tool call does not
+// run on, such as a hedged request attempt, returned as that goroutine's
+// error. It keeps the stack for logPanic.
+type panicError struct {
+	recovered interface{}
+	stack     []byte
+}
+
+func recoveredPanic(recovered interface{}) *panicError {
+	return &panicError{recovered: recovered, stack: debug.Stack()}
+}
+
+func (e *panicError) Error() string {
+	return "request failed unexpectedly"
+}
+
+// toolPanicError is the JSON-RPC internal error for a call that panicked.
+func toolPanicError(tool, requestID string) *jsonRPCError {
+	data := fmt.Sprintf("%s failed unexpectedly; see the server log", tool)
+	if requestID != "" {
+		data += " for request " + requestID
+	}
+	return &jsonRPCError{Code: -32603, Message: "internal error", Data: data}
+}
+
+// logPanic logs a recovered panic with its stack. The panic value stays in
+// the (redacted) log: it may quote arguments or backend data the caller
+// should not see.
+func (s *Server) logPanic(ctx context.Context, tool string, recovered interface{}, stack []byte) {
+	if s.logger != nil {
+		s.logger.ErrorContext(ctx, "tool call panicked", "tool", tool, "request_id", requestIDFrom(ctx), "panic", fmt.Sprint(recovered), "stack", string(stack))
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolPanicBecomesInternalError(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+	var logs bytes.Buffer
+	s.logger = slog.New(slog.NewJSONHandler(&logs, nil))
+	s.UseToolMiddleware(func(next ToolFunc) ToolFunc {
+		return func(ctx context.Context, call ToolCall) map[string]interface{} {
+			if call.Name == "enzan.burn" {
+				var missing map[string]interface{}
+				_ = missing["window"].(string)
+			}
+			return next(ctx, call)
+		}
+	})
+
+	response := s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"enzan.burn","arguments":{}}}`))
+	if response == nil || response.Error == nil || response.Error.Code != -32603 {
+		t.Fatalf("response = %#v, want a JSON-RPC internal error", response)
+	}
+	data, _ := response.Error.Data.(string)
+	if !strings.Contains(data, "enzan.burn failed unexpectedly") {
+		t.Fatalf("error = %#v", response.Error)
+	}
+	entry := panicLogEntry(logs.String())
+	if entry == nil || entry["tool"] != "enzan.burn" || entry["request_id"] == "" || entry["request_id"] == nil || !strings.Contains(entry["stack"].(string), "goroutine") {
+		t.Fatalf("panic not logged with a request ID and stack: %s", logs.String())
+	}
+	if !strings.Contains(data, entry["request_id"].(string)) {
+		t.Fatalf("error should name the request ID %v: %q", entry["request_id"], data)
+	}
+	burn, _ := s.stats.snapshot()["tools"].(map[string]interface{})["enzan.burn"].(map[string]interface{})
+	if burn["calls"] != 1 || burn["errors"] != 1 {
+		t.Fatalf("panicking call not counted: %#v", burn)
+	}
+
+	// The server keeps serving other calls.
+	raw, _ := json.Marshal(toolsCallParams{Name: "mcp.stats", Arguments: map[string]interface{}{}})
+	if result, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("follow-up call failed: %#v %#v", result, rpcErr)
+	}
+}
+
+func TestHedgedRequestPanicBecomesInternalError(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{baseURL: "http://unused.invalid", apiKey: "k", httpClient: http.DefaultClient, hedgeDelay: time.Hour}}
+	s.UseClientMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(*http.Request) (*http.Response, error) { panic("middleware bug") }
+	})
+	var logs bytes.Buffer
+	s.logger = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	_, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"enzan.burn","arguments":{}}`))
+	if rpcErr == nil || rpcErr.Code != -32603 {
+		t.Fatalf("rpc error = %#v, want a JSON-RPC internal error", rpcErr)
+	}
+	entry := panicLogEntry(logs.String())
+	if entry == nil || entry["panic"] != "middleware bug" || !strings.Contains(entry["stack"].(string), "doHedged") {
+		t.Fatalf("hedged panic not logged with its stack: %s", logs.String())
+	}
+}
+
+func panicLogEntry(logs string) map[string]interface{} {
+	for _, line := range strings.Split(strings.TrimSpace(logs), "\n") {
+		var entry map[string]interface{}
+		if json.Unmarshal([]byte(line), &entry) == nil && entry["msg"] == "tool call panicked" {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestAkumaBatchRecoversPanickingPrompts(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{baseURL: "http://unused.invalid", apiKey: "k", httpClient: http.DefaultClient}}
+	s.UseClientMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(*http.Request) (*http.Response, error) { panic("middleware bug") }
+	})
+	var logs bytes.Buffer
+	s.logger = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	result, err := s.callAkumaBatch(context.Background(), map[string]interface{}{"dialect": "postgres", "prompts": []interface{}{"all orders"}})
+	if err != nil || result["failed"] != 1 {
+		t.Fatalf("result = %#v, err = %v", result, err)
+	}
+	if panicLogEntry(logs.String()) == nil {
+		t.Fatalf("panic not logged: %s", logs.String())
+	}
+}
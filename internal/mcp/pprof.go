@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// ServePprof serves the net/http/pprof profiles on addr in the background.
+// Profiles expose memory contents, including credentials, so addr must be
+// a loopback address; reach it remotely through a port forward.
+func (s *Server) ServePprof(addr string) error {
+	if err := checkLoopback(addr); err != nil {
+		return fmt.Errorf("invalid pprof address %q: %w", addr, err)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for pprof: %w", err)
+	}
+	srv := &http.Server{Handler: pprofHandler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(listener); err != nil && s.logger != nil {
+			s.logger.Warn("pprof server stopped", "error", err.Error())
+		}
+	}()
+	if s.logger != nil {
+		s.logger.Info("serving pprof", "addr", listener.Addr().String())
+	}
+	return nil
+}
+
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// checkLoopback accepts host:port addresses whose host is localhost or a
+// loopback IP. An empty host would listen on every interface.
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("must listen on localhost, 127.0.0.1, or [::1]")
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckLoopbackRejectsExposedAddresses(t *testing.T) {
+	for _, addr := range []string{"localhost:6060", "127.0.0.1:6060", "[::1]:6060", "127.0.0.1:0"} {
+		if err := checkLoopback(addr); err != nil {
+			t.Errorf("checkLoopback(%q) = %v, want nil", addr, err)
+		}
+	}
+	for _, addr := range []string{":6060", "0.0.0.0:6060", "[::]:6060", "10.0.0.5:6060", "example.com:6060", "6060"} {
+		if err := checkLoopback(addr); err == nil {
+			t.Errorf("checkLoopback(%q) = nil, want an error", addr)
+		}
+	}
+}
+
+func TestServePprofRefusesNonLoopback(t *testing.T) {
+	s := &Server{}
+	if err := s.ServePprof(":0"); err == nil || !strings.Contains(err.Error(), "invalid pprof address") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestPprofHandlerServesProfiles(t *testing.T) {
+	hs := httptest.NewServer(pprofHandler())
+	defer hs.Close()
+	resp, err := http.Get(hs.URL + "/debug/pprof/heap?debug=1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "heap profile") {
+		t.Fatalf("status = %d, body = %.200s", resp.StatusCode, body)
+	}
+}
+
+func TestServePprofListensOnLoopback(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no loopback listener: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	if err := (&Server{}).ServePprof(addr); err != nil {
+		t.Fatalf("ServePprof: %v", err)
+	}
+	resp, err := http.Get("http://" + addr + "/debug/pprof/")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+const (
+	healthPath          = "/v1/health"
+	startupProbeTimeout = 10 * time.Second
+)
+
+// RunStartupProbe checks backend connectivity and credentials once at
+// startup when KAIZEN_API_STARTUP_PROBE is enabled. Failures are logged
+// with an actionable hint but never stop the server, so a transient
+// outage at launch doesn't take the MCP session down with it.
+func (s *Server) RunStartupProbe() {
+	enabled, _ := strconv.ParseBool(getEnv("KAIZEN_API_STARTUP_PROBE", "false"))
+	if !enabled {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), startupProbeTimeout)
+	defer cancel()
+	health, err := s.probeBackend(ctx)
+	if err != nil {
+		s.logger.Error("kaizen api startup probe failed", "api_base_url", s.client.baseURL, "error", err)
+		return
+	}
+	s.logger.Info("kaizen api startup probe succeeded", "api_base_url", s.client.baseURL)
+	advertised := health.APIVersion
+	if warning := apiVersionWarning(advertised); warning != "" {
+		s.logger.Warn(warning, "api_version", advertised, "supported_api_version", supportedAPIVersion)
+	}
+}
+
+// probeBackend hits the cheap health endpoint and translates the failure
+// into the most likely misconfiguration.
+func (s *Server) probeBackend(ctx context.Context) (*kaizen.HealthResponse, error) {
+	health, err := s.client.typed().Health(ctx)
+	if err != nil {
+		return nil, diagnoseBackendError(s.client.baseURL, err)
+	}
+	return health, nil
+}
+
+func diagnoseBackendError(baseURL string, err error) error {
+	var apiErr *apiCallError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Errorf("Kaizen API rejected the configured credentials (status=%d); check KAIZEN_API_KEY: %w", apiErr.Status, err)
+		case http.StatusNotFound:
+			return fmt.Errorf("%s%s was not found; check KAIZEN_API_BASE_URL points at the Kaizen API: %w", baseURL, healthPath, err)
+		default:
+			return fmt.Errorf("Kaizen API health check failed (status=%d): %w", apiErr.Status, err)
+		}
+	}
+
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		invalidCert      x509.CertificateInvalidError
+	)
+	switch {
+	case errors.As(err, &unknownAuthority), errors.As(err, &invalidCert):
+		return fmt.Errorf("TLS verification of %s failed; set KAIZEN_API_CA_FILE if the endpoint uses an internal CA: %w", baseURL, err)
+	case errors.As(err, &hostnameErr):
+		return fmt.Errorf("TLS certificate for %s does not match its hostname; check KAIZEN_API_BASE_URL: %w", baseURL, err)
+	case strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		// net/http flattens tls.RecordHeaderError into this message.
+		return fmt.Errorf("%s did not speak TLS; check the KAIZEN_API_BASE_URL scheme (http vs https): %w", baseURL, err)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("%s did not return JSON; check KAIZEN_API_BASE_URL points at the Kaizen API: %w", baseURL, err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out reaching %s; check KAIZEN_API_BASE_URL and network access: %w", baseURL, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return fmt.Errorf("cannot reach %s; check KAIZEN_API_BASE_URL and network access: %w", baseURL, err)
+	}
+	return err
+}
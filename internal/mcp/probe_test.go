@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProbeBackendDiagnosesFailures(t *testing.T) {
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid api key"}`))
+	}))
+	defer unauthorized.Close()
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	htmlPage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html>login</html>`))
+	}))
+	defer htmlPage.Close()
+	untrusted := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer untrusted.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := closed.URL
+	closed.Close()
+
+	cases := []struct {
+		name    string
+		baseURL string
+		apiKey  string
+		want    string
+	}{
+		{"missing key", unauthorized.URL, "", "KAIZEN_API_KEY is not set"},
+		{"bad key", unauthorized.URL, "k", "rejected the configured credentials (status=401)"},
+		{"wrong path", notFound.URL, "k", "was not found; check KAIZEN_API_BASE_URL"},
+		{"not json", htmlPage.URL, "k", "did not return JSON"},
+		{"untrusted tls", untrusted.URL, "k", "set KAIZEN_API_CA_FILE"},
+		{"plain http to tls", strings.Replace(unauthorized.URL, "http://", "https://", 1), "k", "did not speak TLS"},
+		{"unreachable", closedURL, "k", "cannot reach"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := &Server{client: &kaizenAPIClient{baseURL: tc.baseURL, apiKey: tc.apiKey, httpClient: &http.Client{}}}
+			_, err := srv.probeBackend(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestProbeBackendSucceeds(t *testing.T) {
+	var gotPath string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer hs.Close()
+
+	srv := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}}
+	if _, err := srv.probeBackend(context.Background()); err != nil {
+		t.Fatalf("probeBackend: %v", err)
+	}
+	if gotPath != healthPath {
+		t.Fatalf("expected probe to hit %s, got %s", healthPath, gotPath)
+	}
+}
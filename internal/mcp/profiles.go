@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+// profileConfig names one Kaizen backend in the config file. API keys are
+// referenced by env var name so the config file itself stays secret-free.
+type profileConfig struct {
+	BaseURL   string `json:"baseUrl"`
+	APIKeyEnv string `json:"apiKeyEnv,omitempty"`
+	// Workspace overrides KAIZEN_WORKSPACE for this profile.
+	Workspace string `json:"workspace,omitempty"`
+}
+
+type apiClientContextKey struct{}
+
+// withAPIClient pins the backend client for the rest of one tool call.
+func withAPIClient(ctx context.Context, client *kaizenAPIClient) context.Context {
+	return context.WithValue(ctx, apiClientContextKey{}, client)
+}
+
+// call sends one Kaizen API request using the client selected for the
+// current tool call (a named profile), falling back to the default client.
+func (s *Server) call(ctx context.Context, method, path string, payload interface{}) (map[string]interface{}, error) {
+	return s.apiClient(ctx).call(ctx, method, path, payload)
+}
+
+// stream is call for NDJSON responses, using the same client selection.
+func (s *Server) stream(ctx context.Context, method, path string, payload interface{}, onLine func([]byte) error) error {
+	return s.apiClient(ctx).stream(ctx, method, path, payload, onLine)
+}
+
+// fetch is call for file responses, using the same client selection.
+func (s *Server) fetch(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	return s.apiClient(ctx).fetch(ctx, method, path, payload)
+}
+
+func (s *Server) apiClient(ctx context.Context) *kaizenAPIClient {
+	if client, ok := ctx.Value(apiClientContextKey{}).(*kaizenAPIClient); ok && client != nil {
+		return client
+	}
+	return s.client
+}
+
+// newProfileClients builds one client per configured profile. They share
+// the base client's transport, timeout, and middleware so TLS and proxy
+// settings apply uniformly; only the endpoint and credentials differ.
+func newProfileClients(base *kaizenAPIClient, profiles map[string]profileConfig) map[string]*kaizenAPIClient {
+	clients := make(map[string]*kaizenAPIClient, len(profiles))
+	for name, profile := range profiles {
+		client := *base
+		client.baseURL = strings.TrimRight(profile.BaseURL, "/")
+		client.failover = nil
+		if profile.APIKeyEnv != "" {
+			client.apiKey = getEnv(profile.APIKeyEnv, "")
+		}
+		if profile.Workspace != "" {
+			client.workspace = profile.Workspace
+		}
+		clients[name] = &client
+	}
+	return clients
+}
+
+// selectProfile resolves and strips the optional `profile` argument so
+// tool handlers never see it.
+func (s *Server) selectProfile(args map[string]interface{}) (*kaizenAPIClient, map[string]interface{}, error) {
+	raw, ok := args["profile"]
+	if !ok {
+		return s.client, args, nil
+	}
+	name, _ := raw.(string)
+	client, found := s.profiles[name]
+	if !found {
+		if len(s.profiles) == 0 {
+			return nil, nil, fmt.Errorf("profile is not supported: no profiles are configured")
+		}
+		return nil, nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(s.profileNames(), ", "))
+	}
+	stripped := make(map[string]interface{}, len(args)-1)
+	for key, value := range args {
+		if key != "profile" {
+			stripped[key] = value
+		}
+	}
+	return client, stripped, nil
+}
+
+func (s *Server) profileNames() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// listTools returns the tools/list payload. When profiles or workspaces
+// are configured every tool advertises an optional `profile` or
+// `workspace` argument.
+func (s *Server) listTools() []toolDefinition {
+	tools := s.registry().definitions()
+	if discovered := s.discovered.Load(); discovered != nil {
+		tools = append(tools, discovered.definitions()...)
+	}
+	if s.readOnly {
+		visible := tools[:0]
+		for _, def := range tools {
+			if tool, _ := s.lookupTool(def.Name); !tool.mutating {
+				visible = append(visible, def)
+			}
+		}
+		tools = visible
+	}
+	for i, def := range tools {
+		if tool, ok := s.lookupTool(def.Name); ok {
+			if notice := tool.deprecationWarning(s.toolPrefix); notice != "" {
+				tools[i].Description = notice + " " + def.Description
+			}
+		}
+	}
+	routing := map[string]interface{}{}
+	if len(s.profiles) > 0 {
+		description := "Kaizen backend profile to target"
+		if s.defaultProfile != "" {
+			description += "; defaults to " + s.defaultProfile
+		}
+		routing["profile"] = map[string]interface{}{
+			"type":        "string",
+			"enum":        s.profileNames(),
+			"description": description,
+		}
+	}
+	if s.usesWorkspaces() {
+		routing["workspace"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Kaizen workspace to act in; defaults to the configured workspace",
+		}
+	}
+	if len(routing) == 0 {
+		return tools
+	}
+	for i, tool := range tools {
+		properties, ok := tool.InputSchema["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Definitions are shared with the registry; copy before adding
+		// the per-server routing properties.
+		schema := make(map[string]interface{}, len(tool.InputSchema))
+		for key, value := range tool.InputSchema {
+			schema[key] = value
+		}
+		extended := make(map[string]interface{}, len(properties)+len(routing))
+		for key, value := range properties {
+			extended[key] = value
+		}
+		for key, value := range routing {
+			extended[key] = value
+		}
+		schema["properties"] = extended
+		tools[i].InputSchema = schema
+	}
+	return tools
+}
+
+// kaizenClient is the typed API client for the current call, over the
+// same client selection as call.
+func (s *Server) kaizenClient(ctx context.Context) *kaizen.Client {
+	return s.apiClient(ctx).typed()
+}
+
+// fielder is implemented by every kaizen response type.
+type fielder interface {
+	Fields() map[string]interface{}
+}
+
+// fieldsOf returns a typed response's whole decoded body, for handlers
+// that pass the response on to the agent unchanged.
+func fieldsOf[R fielder](resp R, err error) (map[string]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return resp.Fields(), nil
+}
@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newProfileTestBackend(t *testing.T, name string, gotAuth *string) *httptest.Server {
+	t.Helper()
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"backend":"` + name + `"}`))
+	}))
+	t.Cleanup(hs.Close)
+	return hs
+}
+
+func TestHandleToolCallRoutesToSelectedProfile(t *testing.T) {
+	var prodAuth, stagingAuth string
+	prod := newProfileTestBackend(t, "prod", &prodAuth)
+	staging := newProfileTestBackend(t, "staging", &stagingAuth)
+
+	t.Setenv("KAIZEN_STAGING_API_KEY", "staging-key")
+	base := &kaizenAPIClient{baseURL: prod.URL, apiKey: "prod-key", httpClient: http.DefaultClient}
+	profiles := newProfileClients(base, map[string]profileConfig{
+		"prod":    {BaseURL: prod.URL},
+		"staging": {BaseURL: staging.URL + "/", APIKeyEnv: "KAIZEN_STAGING_API_KEY"},
+	})
+	srv := &Server{client: profiles["prod"], profiles: profiles, defaultProfile: "prod"}
+
+	call := func(args map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: args})
+		result, rpcErr := srv.handleToolCall(context.Background(), raw)
+		if rpcErr != nil {
+			t.Fatalf("rpc error: %+v", rpcErr)
+		}
+		resp, _ := result.(map[string]interface{})
+		return resp
+	}
+
+	resp := call(map[string]interface{}{"profile": "staging"})
+	structured, _ := resp["structuredContent"].(map[string]interface{})
+	if structured["backend"] != "staging" || stagingAuth != "Bearer staging-key" {
+		t.Fatalf("expected staging backend with staging key, got %#v auth=%q", resp, stagingAuth)
+	}
+
+	resp = call(map[string]interface{}{})
+	structured, _ = resp["structuredContent"].(map[string]interface{})
+	if structured["backend"] != "prod" || prodAuth != "Bearer prod-key" {
+		t.Fatalf("expected default prod backend, got %#v auth=%q", resp, prodAuth)
+	}
+
+	resp = call(map[string]interface{}{"profile": "dev"})
+	if resp["isError"] != true {
+		t.Fatalf("expected unknown profile tool error, got %#v", resp)
+	}
+	content, _ := resp["content"].([]map[string]string)
+	if len(content) != 1 || !strings.Contains(content[0]["text"], `unknown profile "dev" (available: prod, staging)`) {
+		t.Fatalf("unexpected error text: %#v", resp["content"])
+	}
+}
+
+func TestListToolsAdvertisesProfileArgument(t *testing.T) {
+	plain := (&Server{}).listTools()
+	for _, tool := range plain {
+		properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+		if _, ok := properties["profile"]; ok {
+			t.Fatalf("%s should not advertise profile without configured profiles", tool.Name)
+		}
+	}
+
+	srv := &Server{
+		profiles:       map[string]*kaizenAPIClient{"prod": {}, "staging": {}},
+		defaultProfile: "prod",
+	}
+	for _, tool := range srv.listTools() {
+		properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+		profile, ok := properties["profile"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s missing profile argument", tool.Name)
+		}
+		enum, _ := profile["enum"].([]string)
+		if strings.Join(enum, ",") != "prod,staging" {
+			t.Fatalf("%s profile enum = %v", tool.Name, enum)
+		}
+	}
+}
+
+func TestLoadServerConfigValidatesProfiles(t *testing.T) {
+	writeServerConfig(t, `{"profiles":{"staging":{"baseUrl":"staging.internal"}}}`)
+	if _, err := loadServerConfig(); err == nil || !strings.Contains(err.Error(), "staging.baseUrl must be an absolute URL") {
+		t.Fatalf("expected baseUrl validation error, got %v", err)
+	}
+
+	writeServerConfig(t, `{"profiles":{"prod":{"baseUrl":"https://api.example"}},"defaultProfile":"staging"}`)
+	if _, err := loadServerConfig(); err == nil || !strings.Contains(err.Error(), `unknown profile "staging"`) {
+		t.Fatalf("expected defaultProfile validation error, got %v", err)
+	}
+}
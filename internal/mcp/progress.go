@@ -0,0 +1,55 @@
+package mcp
+
+import "context"
+
+// notifier pushes a JSON-RPC notification to the client. Only transports
+// that can send messages in the middle of a call (stdio) provide one.
+type notifier func(method string, params interface{})
+
+type notifierContextKey struct{}
+
+func withNotifier(ctx context.Context, notify notifier) context.Context {
+	return context.WithValue(ctx, notifierContextKey{}, notify)
+}
+
+// canNotify reports whether the transport serving ctx can push
+// notifications.
+func canNotify(ctx context.Context) bool {
+	notify, _ := ctx.Value(notifierContextKey{}).(notifier)
+	return notify != nil
+}
+
+type progressContextKey struct{}
+
+type progressReporter struct {
+	token  interface{}
+	notify notifier
+}
+
+// withProgress routes reportProgress calls made while serving a tool call
+// to the client as notifications/progress. It does nothing unless the
+// caller sent _meta.progressToken and the transport can push messages.
+func withProgress(ctx context.Context, token interface{}) context.Context {
+	notify, _ := ctx.Value(notifierContextKey{}).(notifier)
+	if notify == nil || token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressContextKey{}, progressReporter{token: token, notify: notify})
+}
+
+// reportProgress tells the client how far a long tool call has got. A
+// zero total means the total is unknown.
+func reportProgress(ctx context.Context, progress, total float64) {
+	reporter, ok := ctx.Value(progressContextKey{}).(progressReporter)
+	if !ok {
+		return
+	}
+	params := map[string]interface{}{
+		"progressToken": reporter.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	reporter.notify("notifications/progress", params)
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redactedValue = "[REDACTED]"
+
+// minSecretLength keeps very short configured secrets from being scrubbed
+// out of unrelated text.
+const minSecretLength = 8
+
+var bearerPattern = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+
+// redactor is the one place that decides what must never be written out:
+// the Kaizen API keys and other credentials the server holds, bearer
+// tokens, and fields whose names look secret or match the config file's
+// redactFields. It is applied to logs, backend transcripts, audit entries,
+// and tool error text. A nil redactor still hides secret-looking fields
+// and bearer tokens.
+type redactor struct {
+	fields []*regexp.Regexp
+
+	mu      sync.RWMutex
+	secrets []string
+}
+
+func newRedactor(patterns []string) (*redactor, error) {
+	r := &redactor{}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redactFields: invalid pattern %q: %w", pattern, err)
+		}
+		r.fields = append(r.fields, compiled)
+	}
+	return r, nil
+}
+
+// addSecret registers credential values to scrub from any text.
+func (r *redactor) addSecret(values ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, value := range values {
+		if len(value) >= minSecretLength {
+			r.secrets = append(r.secrets, value)
+		}
+	}
+}
+
+// deniedField reports whether a field's value must be hidden by name.
+func (r *redactor) deniedField(key string) bool {
+	if isSecretKey(key) {
+		return true
+	}
+	if r == nil {
+		return false
+	}
+	for _, pattern := range r.fields {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// text scrubs known secrets and bearer tokens from free text.
+func (r *redactor) text(text string) string {
+	text = bearerPattern.ReplaceAllString(text, "${1}"+redactedValue)
+	if r == nil {
+		return text
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, redactedValue)
+	}
+	return text
+}
+
+// value returns a copy of a decoded JSON value with denied fields replaced
+// and secrets scrubbed from strings.
+func (r *redactor) value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if r.deniedField(key) {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = r.value(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.value(item)
+		}
+		return redacted
+	case string:
+		return r.text(v)
+	}
+	return value
+}
+
+// replaceAttr is the slog hook that applies the redactor to every log
+// record, including its message.
+func (r *redactor) replaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	if r.deniedField(attr.Key) {
+		return slog.String(attr.Key, redactedValue)
+	}
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(r.text(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			attr.Value = slog.StringValue(r.text(err.Error()))
+		}
+	}
+	return attr
+}
+
+// redactToolErrors scrubs the text of failed tool calls, which often
+// quotes backend error bodies or the arguments that were rejected. An
+// HTTP caller's own bearer token is scrubbed as well.
+func (s *Server) redactToolErrors(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		result := next(ctx, call)
+		if result["isError"] != true {
+			return result
+		}
+		token, _ := ctx.Value(callerCredentialContextKey{}).(string)
+		if content, ok := result["content"].([]map[string]string); ok {
+			redacted := make([]map[string]string, len(content))
+			for i, item := range content {
+				redacted[i] = map[string]string{}
+				for key, text := range item {
+					if key == "text" {
+						text = s.redactor.text(text)
+						if len(token) >= minSecretLength {
+							text = strings.ReplaceAll(text, token, redactedValue)
+						}
+					}
+					redacted[i][key] = text
+				}
+			}
+			result["content"] = redacted
+		}
+		return result
+	}
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range []string{"password", "secret", "token", "apikey", "api_key", "credential"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testAPIKey = "kz_live_0123456789"
+
+func TestRedactorScrubsLogs(t *testing.T) {
+	r, err := newRedactor([]string{"(?i)^ssn$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.addSecret(testAPIKey, "short")
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{ReplaceAttr: r.replaceAttr}))
+	logger.Warn("request with "+testAPIKey+" failed",
+		"error", errors.New("401: Authorization: Bearer abc.def.ghi"),
+		"ssn", "123-45-6789",
+		"tool", "akuma.query",
+	)
+	out := logs.String()
+	for _, leak := range []string{testAPIKey, "abc.def.ghi", "123-45-6789"} {
+		if strings.Contains(out, leak) {
+			t.Fatalf("log leaks %q: %s", leak, out)
+		}
+	}
+	if !strings.Contains(out, `"tool":"akuma.query"`) {
+		t.Fatalf("unrelated attributes should be kept: %s", out)
+	}
+	if r.text("a short note") != "a short note" {
+		t.Fatalf("secrets below the minimum length should not be scrubbed")
+	}
+}
+
+func TestRedactorScrubsToolErrorsAndAuditDigests(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"key ` + testAPIKey + ` is revoked"}`))
+	}))
+	defer backend.Close()
+	r, _ := newRedactor([]string{"^customerEmail$"})
+	r.addSecret(testAPIKey)
+	s := &Server{redactor: r, client: &kaizenAPIClient{baseURL: backend.URL, apiKey: testAPIKey, httpClient: backend.Client()}}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	if result.(map[string]interface{})["isError"] != true || strings.Contains(text, testAPIKey) || !strings.Contains(text, redactedValue) {
+		t.Fatalf("tool error should be scrubbed, got %q", text)
+	}
+
+	digest := auditHasher{key: []byte("audit-key"), redactor: r}.digestArguments(registeredTool{}, map[string]interface{}{"customerEmail": "jane@example.com", "limit": 5.0})
+	if digest["customerEmail"] != redactedValue || digest["limit"] != 5.0 {
+		t.Fatalf("denied fields should be redacted from audit digests: %#v", digest)
+	}
+}
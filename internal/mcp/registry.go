@@ -0,0 +1,371 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+// toolHandler serves one tools/call. Handlers receive the arguments with
+// routing-only keys such as `profile` already removed, and a context that
+// carries the selected API client, idempotency key, and tool deadline.
+type toolHandler func(ctx context.Context, s *Server, args map[string]interface{}) (map[string]interface{}, error)
+
+// serverMethod adapts a Server method expression, the form built-in
+// handlers are written in, to a toolHandler.
+func serverMethod(method func(*Server, context.Context, map[string]interface{}) (map[string]interface{}, error)) toolHandler {
+	return func(ctx context.Context, s *Server, args map[string]interface{}) (map[string]interface{}, error) {
+		return method(s, ctx, args)
+	}
+}
+
+type registeredTool struct {
+	def     toolDefinition
+	handler toolHandler
+	// mutating marks tools that change backend state; read-only mode
+	// hides and refuses them.
+	mutating bool
+	// schema is def.InputSchema normalized for validateArguments.
+	schema map[string]interface{}
+	// render, when set, may replace the pretty-printed JSON text content
+	// of a successful result. structuredContent is unaffected.
+	render textRenderer
+	// dryRun marks tools that accept the standard dryRun argument.
+	dryRun bool
+	// budgetGuard, when set, reports whether a call is expensive enough
+	// to check against the tool's cost ceiling first.
+	budgetGuard func(args map[string]interface{}) bool
+	// sharedCeiling names the tool whose cost ceiling applies when this
+	// one has none of its own.
+	sharedCeiling string
+	// budgetEstimates, when set, splits a call into the calls whose
+	// estimates add up to its cost.
+	budgetEstimates func(args map[string]interface{}) []kaizen.BudgetEstimateRequest
+	// offline marks tools that never call the Kaizen API.
+	offline bool
+	// tabular marks tools that accept the outputFormat argument.
+	tabular bool
+	// zoned marks Enzan tools that accept the timezone argument.
+	zoned bool
+	// costReporting marks Enzan tools that accept the currency argument.
+	costReporting bool
+}
+
+// textRenderer formats a successful result as one or more text content
+// blocks. It returns false to fall back to pretty-printed JSON.
+type textRenderer func(args, data map[string]interface{}) ([]string, bool)
+
+// toolOption adjusts a tool at registration.
+type toolOption func(*registeredTool)
+
+// mutating marks a tool as changing backend state.
+func mutating() toolOption {
+	return func(t *registeredTool) { t.mutating = true }
+}
+
+// deprecated marks a tool as scheduled for removal. It stays callable, but
+// its listed description and every result lead with a notice naming
+// replacedBy, when given, so agents migrate before the tool disappears.
+func deprecated(replacedBy string) toolOption {
+	return func(t *registeredTool) {
+		meta := toolMeta{}
+		if t.def.Meta != nil {
+			meta = *t.def.Meta
+		}
+		meta.Deprecated, meta.ReplacedBy = true, replacedBy
+		t.def.Meta = &meta
+	}
+}
+
+// withTextRenderer sets a custom text rendering for successful results.
+func withTextRenderer(render textRenderer) toolOption {
+	return func(t *registeredTool) { t.render = render }
+}
+
+// Registry keeps tool definitions and their handlers together, in
+// registration order, so tools/list and tools/call stay in sync.
+type Registry struct {
+	order []string
+	tools map[string]registeredTool
+	// aliases maps alternate names to canonical tool names. An aliased
+	// tool is listed under its aliases instead of its canonical name, for
+	// clients that reject dotted names; calls accept either.
+	aliases map[string]string
+}
+
+// builtinTools backs servers constructed without an explicit registry
+// (tests build Server literals). It must not be mutated.
+var builtinTools = newBuiltinToolRegistry()
+
+func newToolRegistry() *Registry {
+	return &Registry{tools: map[string]registeredTool{}, aliases: map[string]string{}}
+}
+
+func newBuiltinToolRegistry() *Registry {
+	r := newToolRegistry()
+	registerBuiltinTools(r)
+	r.attachExamples(builtinToolExamples)
+	return r
+}
+
+// register adds a tool. Registering the same name twice is a programming
+// error and panics, like http.ServeMux.Handle.
+func (r *Registry) register(name string, def toolDefinition, handler toolHandler, opts ...toolOption) {
+	if name == "" || handler == nil {
+		panic("mcp: tool registration requires a name and handler")
+	}
+	if _, exists := r.tools[name]; exists {
+		panic(fmt.Sprintf("mcp: tool %q registered twice", name))
+	}
+	def.Name = name
+	tool := registeredTool{def: def, handler: handler}
+	for _, opt := range opts {
+		opt(&tool)
+	}
+	tool.schema = normalizeSchema(tool.def.InputSchema)
+	r.order = append(r.order, name)
+	r.tools[name] = tool
+}
+
+// ToolHandler serves one call of a tool added with Registry.Register. It
+// receives the call's arguments, already checked against the tool's input
+// schema, and returns the structured result.
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error)
+
+// ToolDefinition describes a tool added with Registry.Register.
+type ToolDefinition struct {
+	Description string
+	// InputSchema is the JSON Schema of the arguments; nil accepts an
+	// object with any properties.
+	InputSchema map[string]interface{}
+	// Mutating marks tools that change state, so read-only mode hides
+	// and refuses them.
+	Mutating bool
+}
+
+// Register adds a tool to the registry, listed and called alongside the
+// built-in tools. It fails if name is invalid or already taken by a tool
+// or alias.
+func (r *Registry) Register(name string, def ToolDefinition, handler ToolHandler) error {
+	if !toolNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid tool name %q: use letters, digits, '.', '_', and '-' only", name)
+	}
+	if handler == nil {
+		return fmt.Errorf("tool %q has no handler", name)
+	}
+	if _, exists := r.lookup(name); exists {
+		return fmt.Errorf("tool %q is already registered", name)
+	}
+	schema := def.InputSchema
+	if schema == nil {
+		schema = map[string]interface{}{"type": "object"}
+	}
+	var opts []toolOption
+	if def.Mutating {
+		opts = append(opts, mutating())
+	}
+	r.register(name, toolDefinition{Description: def.Description, InputSchema: schema}, func(ctx context.Context, _ *Server, args map[string]interface{}) (map[string]interface{}, error) {
+		return handler(ctx, args)
+	}, opts...)
+	return nil
+}
+
+// retain drops every tool for which keep returns false.
+func (r *Registry) retain(keep func(name string) bool) {
+	order := r.order[:0]
+	for _, name := range r.order {
+		if keep(name) {
+			order = append(order, name)
+			continue
+		}
+		delete(r.tools, name)
+	}
+	r.order = order
+	for alias, canonical := range r.aliases {
+		if _, ok := r.tools[canonical]; !ok {
+			delete(r.aliases, alias)
+		}
+	}
+}
+
+// alias makes alias an alternate name for the canonical tool.
+func (r *Registry) alias(alias, canonical string) error {
+	if _, ok := r.tools[canonical]; !ok {
+		return fmt.Errorf("alias %q targets unknown tool %q", alias, canonical)
+	}
+	if _, ok := r.tools[alias]; ok {
+		return fmt.Errorf("alias %q conflicts with an existing tool", alias)
+	}
+	if existing, ok := r.aliases[alias]; ok && existing != canonical {
+		return fmt.Errorf("alias %q already targets %q", alias, existing)
+	}
+	r.aliases[alias] = canonical
+	return nil
+}
+
+func (r *Registry) lookup(name string) (registeredTool, bool) {
+	if canonical, ok := r.aliases[name]; ok {
+		name = canonical
+	}
+	tool, ok := r.tools[name]
+	return tool, ok
+}
+
+func (r *Registry) definitions() []toolDefinition {
+	aliased := map[string][]string{}
+	for alias, canonical := range r.aliases {
+		aliased[canonical] = append(aliased[canonical], alias)
+	}
+	defs := make([]toolDefinition, 0, len(r.order))
+	for _, name := range r.order {
+		def := r.tools[name].def
+		names := aliased[name]
+		if len(names) == 0 {
+			defs = append(defs, def)
+			continue
+		}
+		sort.Strings(names)
+		for _, alias := range names {
+			aliasDef := def
+			aliasDef.Name = alias
+			defs = append(defs, aliasDef)
+		}
+	}
+	return defs
+}
+
+// applyToolAliases registers configured aliases and, when underscore is
+// set, an underscore-separated alias (akuma_query) for every dotted name.
+// Aliases whose target was filtered out are skipped.
+func (r *Registry) applyToolAliases(aliases map[string]string, underscore bool) error {
+	if underscore {
+		for _, name := range append([]string{}, r.order...) {
+			if alias := strings.ReplaceAll(name, ".", "_"); alias != name {
+				if err := r.alias(alias, name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	for _, alias := range names {
+		canonical := aliases[alias]
+		if _, ok := r.tools[canonical]; !ok {
+			continue
+		}
+		if err := r.alias(alias, canonical); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addProperty adds an argument that the server, not the handler, consumes
+// to the tool's input schema. The schema maps are copied because tool
+// definitions often share them.
+func (t *registeredTool) addProperty(name string, property map[string]interface{}) {
+	properties, _ := t.def.InputSchema["properties"].(map[string]interface{})
+	schema := make(map[string]interface{}, len(t.def.InputSchema))
+	for key, value := range t.def.InputSchema {
+		schema[key] = value
+	}
+	extended := make(map[string]interface{}, len(properties)+1)
+	for key, value := range properties {
+		extended[key] = value
+	}
+	extended[name] = property
+	schema["properties"] = extended
+	t.def.InputSchema = schema
+}
+
+// takeArgument removes key from args without modifying args and returns
+// its value, or nil when it was absent.
+func takeArgument(args map[string]interface{}, key string) (interface{}, map[string]interface{}) {
+	raw, ok := args[key]
+	if !ok {
+		return nil, args
+	}
+	stripped := make(map[string]interface{}, len(args)-1)
+	for name, value := range args {
+		if name != key {
+			stripped[name] = value
+		}
+	}
+	return raw, stripped
+}
+
+// deprecationWarning is the notice shown for deprecated tools, or "". It
+// names tools as clients see them, under prefix (KAIZEN_TOOLS_PREFIX).
+func (t registeredTool) deprecationWarning(prefix string) string {
+	if t.def.Meta == nil || !t.def.Meta.Deprecated {
+		return ""
+	}
+	if t.def.Meta.ReplacedBy != "" {
+		return fmt.Sprintf("Deprecated: %s will be removed; use %s instead.", prefix+t.def.Name, prefix+t.def.Meta.ReplacedBy)
+	}
+	return fmt.Sprintf("Deprecated: %s will be removed.", prefix+t.def.Name)
+}
+
+// clientTool is the handler for tools that call a kaizen.Client method
+// taking no arguments.
+func clientTool[R fielder](method func(*kaizen.Client, context.Context) (R, error)) toolHandler {
+	return func(ctx context.Context, s *Server, _ map[string]interface{}) (map[string]interface{}, error) {
+		return fieldsOf(method(s.kaizenClient(ctx), ctx))
+	}
+}
+
+// clientQueryTool is clientTool for methods that take the tool's
+// arguments as the query string.
+func clientQueryTool[R fielder](method func(*kaizen.Client, context.Context, url.Values) (R, error)) toolHandler {
+	return func(ctx context.Context, s *Server, args map[string]interface{}) (map[string]interface{}, error) {
+		return fieldsOf(method(s.kaizenClient(ctx), ctx, queryValues(args)))
+	}
+}
+
+// clientPathTool is clientTool for methods that address one resource,
+// named by the required argument arg.
+func clientPathTool[R fielder](arg string, method func(*kaizen.Client, context.Context, string) (R, error)) toolHandler {
+	return func(ctx context.Context, s *Server, args map[string]interface{}) (map[string]interface{}, error) {
+		value, ok := args[arg]
+		if !ok || value == nil || value == "" {
+			return nil, fmt.Errorf("%s is required", arg)
+		}
+		return fieldsOf(method(s.kaizenClient(ctx), ctx, fmt.Sprint(value)))
+	}
+}
+
+// toolDefinitions lists the built-in tools.
+func toolDefinitions() []toolDefinition {
+	return builtinTools.definitions()
+}
+
+func (s *Server) registry() *Registry {
+	if s.tools != nil {
+		return s.tools
+	}
+	return builtinTools
+}
+
+// Tools returns the server's tool registry, for adding tools with
+// Register. Call it before Serve.
+func (s *Server) Tools() *Registry {
+	if s.tools == nil {
+		s.tools = newBuiltinToolRegistry()
+	}
+	return s.tools
+}
+
+// SetReadOnly hides tools that change backend state from tools/list and
+// refuses calls to them, so untrusted agents can be pointed at production.
+// Call it before Serve.
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
@@ -0,0 +1,220 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+)
+
+func TestBuiltinRegistryHandlesEveryListedTool(t *testing.T) {
+	seen := map[string]bool{}
+	for _, def := range toolDefinitions() {
+		if seen[def.Name] {
+			t.Fatalf("tool %q listed twice", def.Name)
+		}
+		seen[def.Name] = true
+		if tool, ok := builtinTools.lookup(def.Name); !ok || tool.handler == nil {
+			t.Fatalf("tool %q has no handler", def.Name)
+		}
+	}
+}
+
+func TestToolRegistryDispatchesRegisteredTool(t *testing.T) {
+	registry := newToolRegistry()
+	registry.register("test.echo", toolDefinition{
+		Description: "Echo arguments.",
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+	}, func(_ context.Context, _ *Server, args map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"echo": args["value"]}, nil
+	})
+
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}, tools: registry}
+	if defs := s.listTools(); len(defs) != 1 || defs[0].Name != "test.echo" {
+		t.Fatalf("expected registered tool in tools/list, got %#v", defs)
+	}
+	result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"test.echo","arguments":{"value":"hi"}}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if data["echo"] != "hi" {
+		t.Fatalf("unexpected result: %#v", data)
+	}
+
+	if _, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"akuma.query","arguments":{}}`)); rpcErr == nil || rpcErr.Code != -32602 {
+		t.Fatalf("expected unknown tool for unregistered name, got %#v", rpcErr)
+	}
+}
+
+func TestRegisterAddsToolsToTheServer(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}}
+	err := s.Tools().Register("ops.echo", ToolDefinition{
+		Description: "Echo a value.",
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"value": map[string]interface{}{"type": "string"}}},
+	}, func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"echo": args["value"]}, nil
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := s.Tools().Register("ops.purge", ToolDefinition{Mutating: true}, func(context.Context, map[string]interface{}) (map[string]interface{}, error) {
+		return nil, nil
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, ok := builtinTools.lookup("ops.echo"); ok {
+		t.Fatal("Register leaked into the shared built-in registry")
+	}
+
+	result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"ops.echo","arguments":{"value":"hi"}}`))
+	if rpcErr != nil || result.(map[string]interface{})["structuredContent"].(map[string]interface{})["echo"] != "hi" {
+		t.Fatalf("unexpected result: %#v %#v", result, rpcErr)
+	}
+	s.SetReadOnly(true)
+	for _, def := range s.listTools() {
+		if def.Name == "ops.purge" {
+			t.Fatal("mutating tools should be hidden in read-only mode")
+		}
+	}
+
+	for name, want := range map[string]string{"enzan.burn": "already registered", "ops.echo": "already registered", "ops echo": "invalid tool name"} {
+		err := s.Tools().Register(name, ToolDefinition{}, func(context.Context, map[string]interface{}) (map[string]interface{}, error) { return nil, nil })
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Register(%q) = %v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestToolRegistryRejectsDuplicateNames(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "registered twice") {
+			t.Fatalf("expected duplicate registration panic, got %v", r)
+		}
+	}()
+	registry := newToolRegistry()
+	handler := clientTool((*kaizen.Client).EnzanBurn)
+	registry.register("enzan.burn", toolDefinition{}, handler)
+	registry.register("enzan.burn", toolDefinition{}, handler)
+}
+
+func TestListToolsDoesNotLeakProfileIntoRegistry(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{}, profiles: map[string]*kaizenAPIClient{"dev": {}}}
+	_ = s.listTools()
+	for _, def := range toolDefinitions() {
+		if _, ok := def.InputSchema["properties"].(map[string]interface{})["profile"]; ok {
+			t.Fatalf("listTools mutated registry schema for %s", def.Name)
+		}
+	}
+}
+
+func TestReadOnlyModeHidesAndRefusesMutatingTools(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}}
+	s.SetReadOnly(true)
+
+	listed := map[string]bool{}
+	for _, def := range s.listTools() {
+		listed[def.Name] = true
+	}
+	if listed["akuma.schema"] || listed["enzan.set_routing"] || listed["enzan.delete_alert"] {
+		t.Fatalf("mutating tools listed in read-only mode: %v", listed)
+	}
+	if !listed["akuma.query"] || !listed["enzan.burn"] {
+		t.Fatalf("read-only tools missing: %v", listed)
+	}
+
+	result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"akuma.schema","arguments":{"sourceId":"s"}}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	resultMap := result.(map[string]interface{})
+	text := resultMap["content"].([]map[string]string)[0]["text"]
+	if resultMap["isError"] != true || !strings.Contains(text, "read-only mode") {
+		t.Fatalf("expected read-only refusal, got %#v", result)
+	}
+}
+
+func TestCustomToolsAreMutatingUnlessGETOrReadOnly(t *testing.T) {
+	registry := newToolRegistry()
+	registerCustomTools(registry, []customToolConfig{
+		{Name: "a.get", Method: "GET", Path: "/a"},
+		{Name: "a.post", Method: "POST", Path: "/a"},
+		{Name: "a.search", Method: "POST", Path: "/a/search", ReadOnly: true},
+	})
+	for name, want := range map[string]bool{"a.get": false, "a.post": true, "a.search": false} {
+		if tool, _ := registry.lookup(name); tool.mutating != want {
+			t.Fatalf("%s mutating = %v, want %v", name, tool.mutating, want)
+		}
+	}
+}
+
+func TestToolAliasesReplaceListedNamesAndResolveCalls(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{"GET /v1/enzan/burn": `{"burn":1}`})
+	defer cleanup()
+	s.tools = newBuiltinToolRegistry()
+	if err := s.tools.applyToolAliases(map[string]string{"burn_rate": "enzan.burn"}, true); err != nil {
+		t.Fatalf("applyToolAliases: %v", err)
+	}
+
+	listed := map[string]bool{}
+	for _, def := range s.listTools() {
+		listed[def.Name] = true
+	}
+	if listed["enzan.burn"] || listed["akuma.query"] {
+		t.Fatalf("canonical dotted names should be replaced by aliases: %v", listed)
+	}
+	if !listed["enzan_burn"] || !listed["burn_rate"] || !listed["akuma_query"] {
+		t.Fatalf("expected aliases in tools/list: %v", listed)
+	}
+
+	for _, name := range []string{"burn_rate", "enzan_burn", "enzan.burn"} {
+		result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"`+name+`","arguments":{}}`))
+		if rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+			t.Fatalf("%s: unexpected failure %#v %#v", name, result, rpcErr)
+		}
+	}
+	if len(captured) != 3 || captured[0].Path != "/v1/enzan/burn" {
+		t.Fatalf("expected every alias to reach enzan.burn, got %#v", captured)
+	}
+}
+
+func TestToolAliasesRejectConflicts(t *testing.T) {
+	registry := newBuiltinToolRegistry()
+	if err := registry.alias("akuma.query", "enzan.burn"); err == nil {
+		t.Fatalf("expected alias shadowing a tool to fail")
+	}
+	if err := registry.alias("x", "nope"); err == nil {
+		t.Fatalf("expected alias to unknown tool to fail")
+	}
+}
+
+func TestDeprecatedToolStaysCallableWithWarning(t *testing.T) {
+	registry := newToolRegistry()
+	registry.register("test.old", toolDefinition{
+		Description: "Old echo.",
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+	}, func(_ context.Context, _ *Server, _ map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"ok": true}, nil
+	}, deprecated("test.new"))
+
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}, tools: registry}
+	defs := s.listTools()
+	encoded, _ := json.Marshal(defs[0])
+	if !strings.Contains(string(encoded), `"_meta":{"deprecated":true,"replacedBy":"test.new"}`) ||
+		!strings.HasPrefix(defs[0].Description, "Deprecated: test.old will be removed; use test.new instead. Old echo.") {
+		t.Fatalf("expected deprecation metadata in tools/list, got %s", encoded)
+	}
+
+	result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"test.old","arguments":{}}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	response := result.(map[string]interface{})
+	content := response["content"].([]map[string]string)
+	if len(content) != 2 || !strings.Contains(content[0]["text"], "use test.new instead") || response["structuredContent"] == nil {
+		t.Fatalf("expected a warning block ahead of the normal result, got %#v", response)
+	}
+}
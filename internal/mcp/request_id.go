@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"time"
+)
+
+// requestIDHeader carries the per-tool-call request ID to the Kaizen API,
+// so a failing agent action can be found in the backend's logs.
+const requestIDHeader = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
+// withRequestID sets the request ID for a tool call. In HTTP mode a
+// caller's own X-Request-ID is reused so the ID spans the whole chain.
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+func requestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// assignRequestID gives every tool call a request ID, sent to the backend
+// with each of the call's requests, and logs the call's outcome under it.
+func (s *Server) assignRequestID(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		id := requestIDFrom(ctx)
+		if id == "" {
+			id = newIdempotencyKey()
+			ctx = withRequestID(ctx, id)
+		}
+		started := time.Now()
+		result := next(ctx, call)
+		if s.logger != nil {
+			s.logger.Info("tool call",
+				"tool", call.Name,
+				"request_id", id,
+				"elapsed_ms", time.Since(started).Milliseconds(),
+				"is_error", result["isError"] == true,
+			)
+		}
+		return result
+	}
+}
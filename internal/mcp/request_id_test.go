@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRequestIDSentWithEveryBackendRequest(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get(requestIDHeader))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backend.Close()
+	var logs bytes.Buffer
+	s := &Server{
+		logger: slog.New(slog.NewJSONHandler(&logs, nil)),
+		client: &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client()},
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.batch", Arguments: map[string]interface{}{"dialect": "postgres", "prompts": []string{"a", "b"}}})
+	s.handleToolCall(context.Background(), raw)
+	if len(ids) != 2 || ids[0] == "" || ids[0] != ids[1] {
+		t.Fatalf("ids = %v, want one shared id for the call's requests", ids)
+	}
+	if !strings.Contains(logs.String(), `"request_id":"`+ids[0]+`"`) {
+		t.Fatalf("tool call not logged with its request id: %s", logs.String())
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{}})
+	s.handleToolCall(context.Background(), raw)
+	if ids[2] == "" || ids[2] == ids[0] {
+		t.Fatalf("each tool call needs its own id, got %v", ids)
+	}
+	s.handleToolCall(withRequestID(context.Background(), "gateway-42"), raw)
+	if ids[3] != "gateway-42" {
+		t.Fatalf("a caller's request id should be reused, got %q", ids[3])
+	}
+}
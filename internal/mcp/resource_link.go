@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+)
+
+// resourceLink is an MCP resource_link content block: a pointer to a file
+// or download the client can fetch itself, instead of its contents or a
+// location spelled out in text.
+type resourceLink struct {
+	URI         string
+	Name        string
+	MimeType    string
+	Description string
+}
+
+func (l resourceLink) block() map[string]string {
+	block := map[string]string{"type": "resource_link", "uri": l.URI, "name": l.Name}
+	if l.MimeType != "" {
+		block["mimeType"] = l.MimeType
+	}
+	if l.Description != "" {
+		block["description"] = l.Description
+	}
+	return block
+}
+
+type resourceLinksContextKey struct{}
+
+// resourceLinks collects the links a handler attaches to its result.
+type resourceLinks struct {
+	mu    sync.Mutex
+	links []resourceLink
+}
+
+// withResourceLinks lets handlers attach resource links for the rest of
+// one tool call.
+func withResourceLinks(ctx context.Context) (context.Context, *resourceLinks) {
+	links := &resourceLinks{}
+	return context.WithValue(ctx, resourceLinksContextKey{}, links), links
+}
+
+// attachResourceLink adds a resource_link block after the text content of
+// the current tool call's result.
+func attachResourceLink(ctx context.Context, link resourceLink) {
+	links, _ := ctx.Value(resourceLinksContextKey{}).(*resourceLinks)
+	if links == nil {
+		return
+	}
+	links.mu.Lock()
+	defer links.mu.Unlock()
+	links.links = append(links.links, link)
+}
+
+func (l *resourceLinks) blocks() []map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	blocks := make([]map[string]string, len(l.links))
+	for i, link := range l.links {
+		blocks[i] = link.block()
+	}
+	return blocks
+}
+
+// replaceTextContent swaps the text blocks of a result for texts and keeps
+// its resource links, for middleware that re-renders a result.
+func replaceTextContent(result map[string]interface{}, texts ...string) {
+	content, _ := result["content"].([]map[string]string)
+	replaced := make([]map[string]string, 0, len(texts)+len(content))
+	for _, text := range texts {
+		replaced = append(replaced, map[string]string{"type": "text", "text": text})
+	}
+	for _, block := range content {
+		if block["type"] != "text" {
+			replaced = append(replaced, block)
+		}
+	}
+	result["content"] = replaced
+}
@@ -0,0 +1,268 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// customToolConfig declares a tool in the config file that proxies to an
+// arbitrary Kaizen endpoint, so operators can expose new API surface
+// without a release.
+type customToolConfig struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema,omitempty"`
+	Method      string                 `json:"method"`
+	// Path may reference arguments as {name}; they are URL-escaped.
+	Path string `json:"path"`
+	// Payload maps the request body (or query string for GET). String
+	// values of the form "{name}" are replaced with the argument value,
+	// keeping its JSON type; other values are sent as-is. When omitted,
+	// every argument not used in Path is forwarded.
+	Payload map[string]interface{} `json:"payload,omitempty"`
+	// ReadOnly keeps a non-GET tool available in read-only mode. GET
+	// tools are always treated as read-only.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Deprecated keeps the tool callable but flags it, pointing at
+	// ReplacedBy when set.
+	Deprecated bool   `json:"deprecated,omitempty"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
+	// DryRun adds the standard dryRun argument. Set it only for endpoints
+	// that honor the X-Kaizen-Dry-Run header.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Examples are advertised in tools/list as _meta.examples.
+	Examples []toolExample `json:"examples,omitempty"`
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// restTool is a tool backed by a single templated HTTP call.
+type restTool struct {
+	method  string
+	path    string
+	payload map[string]interface{}
+	// queryArgs, when non-nil, names the arguments sent in the query
+	// string; every other argument not used in path goes in the body.
+	// bodyArg, when set, sends that one argument as the whole body.
+	queryArgs []string
+	bodyArg   string
+}
+
+func (t restTool) handler() toolHandler {
+	return func(ctx context.Context, s *Server, args map[string]interface{}) (map[string]interface{}, error) {
+		path, used, err := expandPathTemplate(t.path, args)
+		if err != nil {
+			return nil, err
+		}
+		if t.queryArgs != nil {
+			return t.callSplit(ctx, s, path, used, args)
+		}
+
+		var body map[string]interface{}
+		if t.payload != nil {
+			body, _ = mapPayload(t.payload, args).(map[string]interface{})
+		} else {
+			body = map[string]interface{}{}
+			for key, value := range args {
+				if !used[key] {
+					body[key] = value
+				}
+			}
+		}
+
+		if t.method == http.MethodGet || t.method == http.MethodDelete {
+			if query := encodeQuery(body); query != "" {
+				path += "?" + query
+			}
+			return s.call(ctx, t.method, path, nil)
+		}
+		return s.call(ctx, t.method, path, body)
+	}
+}
+
+// callSplit serves tools whose arguments are routed explicitly to the
+// query string and body, as generated from an OpenAPI operation.
+func (t restTool) callSplit(ctx context.Context, s *Server, path string, used map[string]bool, args map[string]interface{}) (map[string]interface{}, error) {
+	query := map[string]interface{}{}
+	for _, name := range t.queryArgs {
+		used[name] = true
+		if value, ok := args[name]; ok && value != nil {
+			query[name] = value
+		}
+	}
+	if encoded := encodeQuery(query); encoded != "" {
+		path += "?" + encoded
+	}
+
+	var body interface{}
+	if t.bodyArg != "" {
+		body = args[t.bodyArg]
+	} else {
+		fields := map[string]interface{}{}
+		for key, value := range args {
+			if !used[key] {
+				fields[key] = value
+			}
+		}
+		if len(fields) > 0 {
+			body = fields
+		}
+	}
+	if t.method == http.MethodGet || body == nil {
+		return s.call(ctx, t.method, path, nil)
+	}
+	return s.call(ctx, t.method, path, body)
+}
+
+func expandPathTemplate(template string, args map[string]interface{}) (string, map[string]bool, error) {
+	used := map[string]bool{}
+	var missing string
+	path := pathParamPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := args[name]
+		if !ok || value == nil || value == "" {
+			if missing == "" {
+				missing = name
+			}
+			return match
+		}
+		used[name] = true
+		return url.PathEscape(fmt.Sprint(value))
+	})
+	if missing != "" {
+		return "", nil, fmt.Errorf("%s is required", missing)
+	}
+	return path, used, nil
+}
+
+// mapPayload substitutes "{name}" placeholders anywhere in the template.
+// Placeholders for absent arguments drop the enclosing object key.
+func mapPayload(template interface{}, args map[string]interface{}) interface{} {
+	switch v := template.(type) {
+	case string:
+		if match := pathParamPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return args[match[1]]
+		}
+		return v
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if mapped := mapPayload(item, args); mapped != nil {
+				out[key] = mapped
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if mapped := mapPayload(item, args); mapped != nil {
+				out = append(out, mapped)
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func encodeQuery(values map[string]interface{}) string {
+	return queryValues(values).Encode()
+}
+
+// queryValues converts arguments to query parameters, repeating the
+// parameter for each item of an array. Null arguments are left out.
+func queryValues(values map[string]interface{}) url.Values {
+	query := url.Values{}
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			for _, item := range v {
+				if item != nil {
+					query.Add(key, queryValue(item))
+				}
+			}
+		default:
+			query.Set(key, queryValue(v))
+		}
+	}
+	return query
+}
+
+// queryValue formats one query parameter. Objects and nested arrays have
+// no query string form of their own and are sent as compact JSON, rather
+// than as Go's map[...] syntax.
+func queryValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return formatQueryNumber(v)
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return fmt.Sprint(value)
+}
+
+func formatQueryNumber(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprint(v)
+}
+
+func (c customToolConfig) validate(builtin map[string]bool) error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if builtin[c.Name] {
+		return fmt.Errorf("%s conflicts with a built-in tool", c.Name)
+	}
+	switch strings.ToUpper(c.Method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fmt.Errorf("%s.method must be GET, POST, PUT, PATCH, or DELETE", c.Name)
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("%s.path must start with /", c.Name)
+	}
+	return nil
+}
+
+func (c customToolConfig) definition() toolDefinition {
+	schema := c.InputSchema
+	if schema == nil {
+		schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return toolDefinition{Description: c.Description, InputSchema: schema}
+}
+
+func registerCustomTools(r *Registry, tools []customToolConfig) {
+	for _, tool := range tools {
+		method := strings.ToUpper(tool.Method)
+		var opts []toolOption
+		if method != http.MethodGet && !tool.ReadOnly {
+			opts = append(opts, mutating())
+		}
+		if tool.Deprecated {
+			opts = append(opts, deprecated(tool.ReplacedBy))
+		}
+		if tool.DryRun {
+			opts = append(opts, dryRunnable())
+		}
+		if len(tool.Examples) > 0 {
+			opts = append(opts, withExamples(tool.Examples...))
+		}
+		r.register(tool.Name, tool.definition(), restTool{
+			method:  method,
+			path:    tool.Path,
+			payload: tool.Payload,
+		}.handler(), opts...)
+	}
+}
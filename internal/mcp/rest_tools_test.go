@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCustomToolsProxyToConfiguredEndpoints(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/sozo/jobs/job 1":    `{"id":"job 1","status":"running"}`,
+		"POST /v1/akuma/annotations": `{"ok":true}`,
+	})
+	defer cleanup()
+
+	s.tools = newBuiltinToolRegistry()
+	registerCustomTools(s.tools, []customToolConfig{
+		{Name: "sozo.job", Description: "Fetch a job.", Method: "GET", Path: "/v1/sozo/jobs/{jobId}"},
+		{
+			Name:    "akuma.annotate",
+			Method:  "post",
+			Path:    "/v1/akuma/annotations",
+			Payload: map[string]interface{}{"target": map[string]interface{}{"table": "{table}"}, "note": "{note}", "source": "mcp"},
+		},
+	})
+
+	listed := false
+	for _, def := range s.listTools() {
+		listed = listed || def.Name == "sozo.job"
+	}
+	if !listed {
+		t.Fatalf("expected custom tool in tools/list")
+	}
+
+	result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"sozo.job","arguments":{"jobId":"job 1","verbose":true}}`))
+	if rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("unexpected failure: %#v %#v", result, rpcErr)
+	}
+	if got := captured[0]; got.Method != "GET" || got.Path != "/v1/sozo/jobs/job 1" || got.Query != "verbose=true" {
+		t.Fatalf("unexpected GET request: %#v", got)
+	}
+
+	if _, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"akuma.annotate","arguments":{"table":"orders","note":"pii"}}`)); rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(captured[1].Body), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if captured[1].Method != "POST" || body["note"] != "pii" || body["source"] != "mcp" || body["target"].(map[string]interface{})["table"] != "orders" {
+		t.Fatalf("unexpected mapped payload: %#v", captured[1])
+	}
+}
+
+func TestCustomGETToolsEncodeNestedArgumentsAsJSON(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, nil)
+	defer cleanup()
+	s.tools = newBuiltinToolRegistry()
+	registerCustomTools(s.tools, []customToolConfig{{Name: "ops.search", Method: "GET", Path: "/v1/search"}})
+
+	raw := `{"name":"ops.search","arguments":{"filter":{"team":"ml","tags":["gpu"]},"ids":[1,2.5,[3]],"cursor":null}}`
+	if result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(raw)); rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("unexpected failure: %#v %#v", result, rpcErr)
+	}
+	query, err := url.ParseQuery(captured[0].Query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := query.Get("filter"); got != `{"tags":["gpu"],"team":"ml"}` {
+		t.Fatalf("filter = %q, want compact JSON", got)
+	}
+	if got := strings.Join(query["ids"], ","); got != "1,2.5,[3]" {
+		t.Fatalf("ids = %q", got)
+	}
+	if _, ok := query["cursor"]; ok {
+		t.Fatalf("null arguments should be left out: %q", captured[0].Query)
+	}
+}
+
+func TestCustomToolRequiresPathArguments(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, nil)
+	defer cleanup()
+	s.tools = newToolRegistry()
+	registerCustomTools(s.tools, []customToolConfig{{Name: "sozo.job", Method: "GET", Path: "/v1/sozo/jobs/{jobId}"}})
+
+	result, _ := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"sozo.job","arguments":{}}`))
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	if !strings.Contains(text, "jobId is required") || len(captured) != 0 {
+		t.Fatalf("expected missing path argument error without a request, got %q", text)
+	}
+}
+
+func TestRegisterCustomToolsHonorsDeprecation(t *testing.T) {
+	registry := newToolRegistry()
+	registerCustomTools(registry, []customToolConfig{{Name: "ops.old", Method: "GET", Path: "/v1/x", Deprecated: true}})
+	tool, _ := registry.lookup("ops.old")
+	if tool.def.Meta == nil || !tool.def.Meta.Deprecated || tool.deprecationWarning("") != "Deprecated: ops.old will be removed." {
+		t.Fatalf("expected config deprecation to be applied, got %#v", tool.def)
+	}
+}
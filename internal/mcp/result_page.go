@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	resultPageTool = "kaizen.result.page"
+	// defaultResultMaxBytes bounds the inline text of one tool result
+	// before it is split into pages.
+	defaultResultMaxBytes = 64 << 10
+	resultCacheSize       = 32
+	resultCacheTTL        = 30 * time.Minute
+)
+
+// resultCache keeps the pages of truncated results so kaizen.result.page
+// can serve the rest. The zero value is ready to use. The oldest entry is
+// evicted once resultCacheSize results are held. Each entry is bound to
+// the caller that produced it, so in HTTP mode a leaked or guessed ID does
+// not hand one tenant's data to another.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResult
+	order   []string
+}
+
+type cachedResult struct {
+	owner   resultOwner
+	pages   []string
+	expires time.Time
+}
+
+// resultOwner identifies who may page through a cached result: a hash of
+// the credential the call used and the workspace it acted in.
+type resultOwner struct {
+	credentialHash string
+	workspace      string
+}
+
+func (s *Server) resultOwner(ctx context.Context) resultOwner {
+	client := s.apiClient(ctx)
+	if client == nil {
+		return resultOwner{}
+	}
+	return resultOwner{credentialHash: sha256Hex([]byte(client.credential(ctx))), workspace: client.workspaceFor(ctx)}
+}
+
+func (c *resultCache) put(owner resultOwner, pages []string) (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]cachedResult{}
+	}
+	for len(c.order) >= resultCacheSize {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[id] = cachedResult{owner: owner, pages: pages, expires: time.Now().Add(resultCacheTTL)}
+	c.order = append(c.order, id)
+	return id, nil
+}
+
+func (c *resultCache) get(owner resultOwner, id string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[id]
+	if !ok || entry.owner != owner || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.pages, true
+}
+
+// splitResultPages cuts text into pages of at most size bytes without
+// splitting a UTF-8 sequence.
+func splitResultPages(text string, size int) []string {
+	var pages []string
+	for len(text) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = size
+		}
+		pages = append(pages, text[:cut])
+		text = text[cut:]
+	}
+	return append(pages, text)
+}
+
+// truncateResult replaces a successful result whose text exceeds
+// s.resultMaxBytes with its first page and a pointer to
+// kaizen.result.page. structuredContent is dropped too, since it carries
+// the same payload. Resource links are small and kept as they are.
+func (s *Server) truncateResult(ctx context.Context, result map[string]interface{}) map[string]interface{} {
+	if s.resultMaxBytes <= 0 {
+		return result
+	}
+	content, _ := result["content"].([]map[string]string)
+	var texts []string
+	var links []map[string]string
+	for _, block := range content {
+		if block["type"] == "text" {
+			texts = append(texts, block["text"])
+		} else {
+			links = append(links, block)
+		}
+	}
+	full := strings.Join(texts, "\n\n")
+	if len(full) <= s.resultMaxBytes {
+		return result
+	}
+
+	pages := splitResultPages(full, s.resultMaxBytes)
+	id, err := s.results.put(s.resultOwner(ctx), pages)
+	if err != nil {
+		// Without an ID the remaining pages are unreachable; the full
+		// result is better than a dead end.
+		return result
+	}
+	return map[string]interface{}{
+		"content": append([]map[string]string{
+			{"type": "text", "text": pages[0]},
+			{"type": "text", "text": resultPageFooter(s.toolPrefix+resultPageTool, id, 1, len(pages), len(full))},
+		}, links...),
+	}
+}
+
+// resultPageFooter closes a page. tool is kaizen.result.page as the
+// client lists it, under KAIZEN_TOOLS_PREFIX.
+func resultPageFooter(tool, id string, page, pages, totalBytes int) string {
+	if page >= pages {
+		return fmt.Sprintf("End of result %s (page %d of %d).", id, page, pages)
+	}
+	return fmt.Sprintf("Result truncated: page %d of %d (%d bytes in total). Call %s with resultId %q and page %d for more.", page, pages, totalBytes, tool, id, page+1)
+}
+
+func (s *Server) callKaizenResultPage(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	id, _ := args["resultId"].(string)
+	pages, ok := s.results.get(s.resultOwner(ctx), id)
+	s.stats.recordLookup(cacheResultPages, ok)
+	if !ok {
+		return nil, fmt.Errorf("result %q is unknown or has expired; call the original tool again", id)
+	}
+	page := 2
+	if requested, ok := numericToolArg(args, "page"); ok {
+		page = requested
+	}
+	if page < 1 || page > len(pages) {
+		return nil, fmt.Errorf("page must be between 1 and %d", len(pages))
+	}
+	total := 0
+	for _, text := range pages {
+		total += len(text)
+	}
+	return map[string]interface{}{
+		"resultId":   id,
+		"page":       page,
+		"pages":      len(pages),
+		"totalBytes": total,
+		"text":       pages[page-1],
+		"tool":       s.toolPrefix + resultPageTool,
+	}, nil
+}
+
+// renderResultPage shows the page text verbatim rather than as an escaped
+// JSON string.
+func renderResultPage(_, data map[string]interface{}) ([]string, bool) {
+	text, _ := data["text"].(string)
+	id, _ := data["resultId"].(string)
+	page, _ := data["page"].(int)
+	pages, _ := data["pages"].(int)
+	total, _ := data["totalBytes"].(int)
+	tool, _ := data["tool"].(string)
+	return []string{text, resultPageFooter(tool, id, page, pages, total)}, true
+}
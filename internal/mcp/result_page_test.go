@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitResultPagesKeepsRunesWhole(t *testing.T) {
+	text := strings.Repeat("é", 10) // 2 bytes each
+	pages := splitResultPages(text, 5)
+	if strings.Join(pages, "") != text {
+		t.Fatalf("pages do not reassemble: %q", pages)
+	}
+	for _, page := range pages {
+		if len(page) > 5 || !utf8.ValidString(page) {
+			t.Fatalf("bad page %q", page)
+		}
+	}
+}
+
+func TestLargeResultIsPagedThroughResultPageTool(t *testing.T) {
+	var captured []capturedRequest
+	rows := make([]string, 200)
+	for i := range rows {
+		rows[i] = fmt.Sprintf(`{"id":%d,"name":"row-%03d"}`, i, i)
+	}
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/usage": `{"rows":[` + strings.Join(rows, ",") + `]}`,
+	})
+	defer cleanup()
+	s.resultMaxBytes = 1024
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.usage", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	resultMap := result.(map[string]interface{})
+	if _, ok := resultMap["structuredContent"]; ok {
+		t.Fatal("truncated results should drop structuredContent")
+	}
+	content := resultMap["content"].([]map[string]string)
+	if len(content) != 2 || len(content[0]["text"]) > 1024 {
+		t.Fatalf("unexpected truncated content: %d blocks", len(content))
+	}
+	var id string
+	for resultID := range s.results.entries {
+		id = resultID
+	}
+	if !strings.Contains(content[1]["text"], id) || !strings.Contains(content[1]["text"], resultPageTool) {
+		t.Fatalf("footer should point at %s: %q", resultPageTool, content[1]["text"])
+	}
+
+	full := content[0]["text"]
+	for page := 2; ; page++ {
+		raw, _ = json.Marshal(toolsCallParams{Name: resultPageTool, Arguments: map[string]interface{}{"resultId": id, "page": page}})
+		result, rpcErr = s.handleToolCall(context.Background(), raw)
+		if rpcErr != nil {
+			t.Fatalf("rpc error: %+v", rpcErr)
+		}
+		pageMap := result.(map[string]interface{})
+		if pageMap["isError"] == true {
+			t.Fatalf("page %d failed: %#v", page, pageMap)
+		}
+		pageContent := pageMap["content"].([]map[string]string)
+		full += pageContent[0]["text"]
+		if strings.HasPrefix(pageContent[1]["text"], "End of result") {
+			break
+		}
+		if page > 100 {
+			t.Fatal("paging did not terminate")
+		}
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(full), &decoded); err != nil {
+		t.Fatalf("reassembled pages are not the original JSON: %v", err)
+	}
+	if got := len(decoded["rows"].([]interface{})); got != 200 {
+		t.Fatalf("reassembled %d rows, want 200", got)
+	}
+}
+
+func TestResultPageRejectsUnknownIDAndOutOfRangePage(t *testing.T) {
+	s := &Server{}
+	if _, err := s.callKaizenResultPage(context.Background(), map[string]interface{}{"resultId": "missing"}); err == nil {
+		t.Fatal("expected error for unknown result")
+	}
+	id, err := s.results.put(s.resultOwner(context.Background()), []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.callKaizenResultPage(context.Background(), map[string]interface{}{"resultId": id, "page": float64(3)}); err == nil {
+		t.Fatal("expected error for out-of-range page")
+	}
+}
+
+func TestResultCacheEvictsOldest(t *testing.T) {
+	var cache resultCache
+	first, _ := cache.put(resultOwner{}, []string{"first"})
+	for i := 0; i < resultCacheSize; i++ {
+		_, _ = cache.put(resultOwner{}, []string{"x"})
+	}
+	if _, ok := cache.get(resultOwner{}, first); ok {
+		t.Fatal("oldest result should have been evicted")
+	}
+}
+
+func TestResultPageIsBoundToTheCallerAndWorkspace(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{apiKey: "shared-key", workspace: "acme"}}
+	owner := withCallerCredential(context.Background(), "caller-a-token")
+	id, err := s.results.put(s.resultOwner(owner), []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.callKaizenResultPage(owner, map[string]interface{}{"resultId": id}); err != nil {
+		t.Fatalf("owner should read its own result: %v", err)
+	}
+	for name, ctx := range map[string]context.Context{
+		"other caller":    withCallerCredential(context.Background(), "caller-b-token"),
+		"shared key":      context.Background(),
+		"other workspace": withWorkspace(owner, "globex"),
+	} {
+		if _, err := s.callKaizenResultPage(ctx, map[string]interface{}{"resultId": id}); err == nil {
+			t.Fatalf("%s should not read the result", name)
+		}
+	}
+}
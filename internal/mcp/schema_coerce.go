@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// coerceArguments converts obviously-convertible values to the type the
+// schema asks for before validation: numeric strings to numbers ("50"),
+// "true"/"false" to booleans, numbers and booleans to strings, and a lone
+// scalar to a one-element array. Anything ambiguous is left alone so
+// validation still reports it. args is updated in place.
+func coerceArguments(schema map[string]interface{}, args map[string]interface{}) {
+	if schema == nil || args == nil {
+		return
+	}
+	coerceObject(schema, args)
+}
+
+func coerceObject(schema map[string]interface{}, obj map[string]interface{}) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, value := range obj {
+		if propSchema, ok := properties[name].(map[string]interface{}); ok {
+			obj[name] = coerceValue(propSchema, value)
+		}
+	}
+}
+
+func coerceValue(schema map[string]interface{}, value interface{}) interface{} {
+	types := schemaTypes(schema["type"])
+	if len(types) != 1 || matchesSchemaType(types[0], value) {
+		return coerceChildren(schema, value)
+	}
+
+	switch types[0] {
+	case "integer", "number":
+		text, ok := value.(string)
+		if !ok {
+			return value
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return value
+		}
+		if types[0] == "integer" && n != math.Trunc(n) {
+			return value
+		}
+		return n
+	case "boolean":
+		if text, ok := value.(string); ok {
+			switch strings.ToLower(strings.TrimSpace(text)) {
+			case "true":
+				return true
+			case "false":
+				return false
+			}
+		}
+	case "string":
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(v)
+		}
+	case "array":
+		switch value.(type) {
+		case string, float64, bool:
+			return coerceChildren(schema, []interface{}{value})
+		}
+	}
+	return value
+}
+
+func coerceChildren(schema map[string]interface{}, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		coerceObject(schema, v)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				v[i] = coerceValue(items, item)
+			}
+		}
+	}
+	return value
+}
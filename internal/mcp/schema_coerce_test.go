@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCoerceArgumentsConvertsObviousMismatches(t *testing.T) {
+	schema := normalizeSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"records":  map[string]interface{}{"type": "integer"},
+			"ratio":    map[string]interface{}{"type": "number"},
+			"enabled":  map[string]interface{}{"type": "boolean"},
+			"sourceId": map[string]interface{}{"type": "string"},
+			"groupBy":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"nested": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"limit": map[string]interface{}{"type": "integer"}},
+			},
+		},
+	})
+	args := map[string]interface{}{
+		"records":  "100",
+		"ratio":    " 0.5 ",
+		"enabled":  "TRUE",
+		"sourceId": 42.0,
+		"groupBy":  "model",
+		"nested":   map[string]interface{}{"limit": "7"},
+	}
+	coerceArguments(schema, args)
+	want := map[string]interface{}{
+		"records":  100.0,
+		"ratio":    0.5,
+		"enabled":  true,
+		"sourceId": "42",
+		"groupBy":  []interface{}{"model"},
+		"nested":   map[string]interface{}{"limit": 7.0},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("coerceArguments = %#v, want %#v", args, want)
+	}
+}
+
+func TestCoerceArgumentsLeavesAmbiguousValues(t *testing.T) {
+	schema := normalizeSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"records": map[string]interface{}{"type": "integer"},
+			"enabled": map[string]interface{}{"type": "boolean"},
+		},
+	})
+	args := map[string]interface{}{"records": "12.5", "enabled": "yes"}
+	coerceArguments(schema, args)
+	if args["records"] != "12.5" || args["enabled"] != "yes" {
+		t.Fatalf("expected ambiguous values untouched, got %#v", args)
+	}
+}
+
+func TestHandleToolCallCoercesBeforeValidationWhenEnabled(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, nil)
+	defer cleanup()
+
+	raw := json.RawMessage(`{"name":"akuma.query","arguments":{"dialect":"postgres","prompt":"p","maxRows":"50"}}`)
+	result, _ := s.handleToolCall(context.Background(), raw)
+	if result.(map[string]interface{})["isError"] != true || len(captured) != 0 {
+		t.Fatalf("expected strict mode to reject string maxRows")
+	}
+
+	s.coerceArgs = true
+	result, _ = s.handleToolCall(context.Background(), raw)
+	if result.(map[string]interface{})["isError"] == true || len(captured) != 1 {
+		t.Fatalf("expected coerced call to reach the backend, got %#v", result)
+	}
+	var body map[string]interface{}
+	_ = json.Unmarshal([]byte(captured[0].Body), &body)
+	if body["maxRows"] != 50.0 {
+		t.Fatalf("expected numeric maxRows in payload, got %#v", body)
+	}
+}
@@ -0,0 +1,298 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// normalizeSchema round-trips a schema through JSON so validation sees one
+// shape regardless of whether it was written as Go literals ([]string,
+// int) or decoded from a config file or OpenAPI document.
+func normalizeSchema(schema map[string]interface{}) map[string]interface{} {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil
+	}
+	return normalized
+}
+
+// validateArguments checks tool arguments against the tool's inputSchema
+// so malformed calls fail locally with every problem listed, instead of
+// costing a backend round trip. It implements the subset of JSON Schema
+// the tool definitions use: type, enum, const, required, properties,
+// additionalProperties, items, numeric and length bounds, pattern, and the
+// allOf/anyOf/oneOf/not combinators.
+func validateArguments(schema map[string]interface{}, args map[string]interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	var value interface{} = map[string]interface{}{}
+	if args != nil {
+		value = args
+	}
+	var problems []string
+	validateSchemaValue(schema, value, "", &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid arguments: %s", strings.Join(problems, "; "))
+}
+
+func validateSchemaValue(schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		subject := path
+		if subject == "" {
+			subject = "arguments"
+		}
+		*problems = append(*problems, subject+" "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !matchesAnyType(types, value) {
+		names := make([]string, len(types))
+		for i, t := range types {
+			names[i] = schemaTypeArticle(t)
+		}
+		report("must be %s, got %s", strings.Join(names, " or "), valueTypeName(value))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsJSONValue(enum, value) {
+		options := make([]string, len(enum))
+		for i, option := range enum {
+			encoded, _ := json.Marshal(option)
+			options[i] = string(encoded)
+		}
+		report("must be one of %s", strings.Join(options, ", "))
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		encoded, _ := json.Marshal(constant)
+		report("must be %s", encoded)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateSchemaObject(schema, v, path, problems)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateSchemaValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+		if min, ok := schemaNumber(schema["minItems"]); ok && float64(len(v)) < min {
+			report("must have at least %v items", min)
+		}
+		if max, ok := schemaNumber(schema["maxItems"]); ok && float64(len(v)) > max {
+			report("must have at most %v items", max)
+		}
+	case float64:
+		if min, ok := schemaNumber(schema["minimum"]); ok && v < min {
+			report("must be >= %v", min)
+		}
+		if max, ok := schemaNumber(schema["maximum"]); ok && v > max {
+			report("must be <= %v", max)
+		}
+		if min, ok := schemaNumber(schema["exclusiveMinimum"]); ok && v <= min {
+			report("must be > %v", min)
+		}
+		if max, ok := schemaNumber(schema["exclusiveMaximum"]); ok && v >= max {
+			report("must be < %v", max)
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if min, ok := schemaNumber(schema["minLength"]); ok && length < min {
+			report("must be at least %v characters", min)
+		}
+		if max, ok := schemaNumber(schema["maxLength"]); ok && length > max {
+			report("must be at most %v characters", max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				report("must match pattern %s", pattern)
+			}
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			if subSchema, ok := sub.(map[string]interface{}); ok {
+				validateSchemaValue(subSchema, value, path, problems)
+			}
+		}
+	}
+	if any, ok := schema["anyOf"].([]interface{}); ok && countMatchingSchemas(any, value) == 0 {
+		report("must match at least one of the allowed shapes")
+	}
+	if one, ok := schema["oneOf"].([]interface{}); ok {
+		if matched := countMatchingSchemas(one, value); matched != 1 {
+			report("must match exactly one of the allowed shapes, matched %d", matched)
+		}
+	}
+	if not, ok := schema["not"].(map[string]interface{}); ok && schemaMatches(not, value) {
+		report("must not match the excluded shape")
+	}
+}
+
+func validateSchemaObject(schema map[string]interface{}, obj map[string]interface{}, path string, problems *[]string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			name, _ := item.(string)
+			if _, present := obj[name]; name != "" && !present {
+				*problems = append(*problems, joinSchemaPath(path, name)+" is required")
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if propSchema, ok := properties[name].(map[string]interface{}); ok {
+			validateSchemaValue(propSchema, obj[name], joinSchemaPath(path, name), problems)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*problems = append(*problems, joinSchemaPath(path, name)+" is not a recognized argument")
+			}
+		case map[string]interface{}:
+			validateSchemaValue(extra, obj[name], joinSchemaPath(path, name), problems)
+		}
+	}
+}
+
+func schemaMatches(schema map[string]interface{}, value interface{}) bool {
+	var problems []string
+	validateSchemaValue(schema, value, "", &problems)
+	return len(problems) == 0
+}
+
+func countMatchingSchemas(schemas []interface{}, value interface{}) int {
+	matched := 0
+	for _, sub := range schemas {
+		if subSchema, ok := sub.(map[string]interface{}); ok && schemaMatches(subSchema, value) {
+			matched++
+		}
+	}
+	return matched
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func schemaTypes(raw interface{}) []string {
+	switch v := raw.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			if text, ok := item.(string); ok {
+				types = append(types, text)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func matchesAnyType(types []string, value interface{}) bool {
+	for _, t := range types {
+		if matchesSchemaType(t, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSchemaType(t string, value interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func schemaTypeArticle(t string) string {
+	switch t {
+	case "integer":
+		return "an integer"
+	case "object":
+		return "an object"
+	case "array":
+		return "an array"
+	case "null":
+		return "null"
+	default:
+		return "a " + t
+	}
+}
+
+func valueTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func schemaNumber(raw interface{}) (float64, bool) {
+	n, ok := raw.(float64)
+	return n, ok
+}
+
+func containsJSONValue(options []interface{}, value interface{}) bool {
+	for _, option := range options {
+		if reflect.DeepEqual(option, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateArgumentsReportsEveryProblem(t *testing.T) {
+	tool, _ := builtinTools.lookup("akuma.query")
+	err := validateArguments(tool.schema, map[string]interface{}{
+		"dialect": "oracle",
+		"maxRows": "50",
+		"extra":   true,
+	})
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{
+		"prompt is required",
+		`dialect must be one of "postgres", "mysql", "snowflake", "bigquery"`,
+		"maxRows must be a number, got string",
+		"extra is not a recognized argument",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %q", want, err.Error())
+		}
+	}
+}
+
+func TestValidateArgumentsChecksNestedObjectsAndBounds(t *testing.T) {
+	tool, _ := builtinTools.lookup("enzan.pricing_offers_upsert")
+	err := validateArguments(tool.schema, map[string]interface{}{
+		"gpu": map[string]interface{}{"provider": "p", "gpuType": "g", "displayName": "d", "hourlyRateUSD": -1.0, "clusterSizeMin": 1.5},
+	})
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{"gpu.hourlyRateUSD must be >= 0", "gpu.clusterSizeMin must be an integer, got number"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %q", want, err.Error())
+		}
+	}
+
+	valid := map[string]interface{}{
+		"gpu": map[string]interface{}{"provider": "p", "gpuType": "g", "displayName": "d", "hourlyRateUSD": 2.5, "clusterSizeMin": 8.0},
+	}
+	if err := validateArguments(tool.schema, valid); err != nil {
+		t.Fatalf("expected valid arguments, got %v", err)
+	}
+}
+
+func TestHandleToolCallRejectsInvalidArgumentsWithoutBackendCall(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, nil)
+	defer cleanup()
+
+	result, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"enzan.summary","arguments":{"window":"fortnight"}}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected rpc error: %#v", rpcErr)
+	}
+	resp := result.(map[string]interface{})
+	text := resp["content"].([]map[string]string)[0]["text"]
+	if resp["isError"] != true || !strings.HasPrefix(text, "invalid arguments: window must be one of") {
+		t.Fatalf("expected schema error, got %#v", resp)
+	}
+	if len(captured) != 0 {
+		t.Fatalf("expected no backend request, got %#v", captured)
+	}
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// scopedProducts are the tool name prefixes that map to API scopes.
+var scopedProducts = map[string]bool{"akuma": true, "enzan": true, "sozo": true}
+
+// requiredScope is the scope a tool needs: "<product>:write" for mutating
+// akuma, enzan, and sozo tools, "<product>:read" for the rest of them,
+// and "" for tools outside those products.
+func requiredScope(name string, mutating bool) string {
+	product, _, ok := strings.Cut(name, ".")
+	if !ok || !scopedProducts[product] {
+		return ""
+	}
+	if mutating {
+		return product + ":write"
+	}
+	return product + ":read"
+}
+
+// scopeGranted reports whether granted covers required. "*" and
+// "<product>:*" are wildcards, and a write scope implies read.
+func scopeGranted(granted []string, required string) bool {
+	if required == "" {
+		return true
+	}
+	product, access, _ := strings.Cut(required, ":")
+	for _, scope := range granted {
+		switch scope {
+		case "*", required, product + ":*":
+			return true
+		case product + ":write":
+			if access == "read" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jwtScopes reads the scope (space-separated) or scp claim from a JWT
+// bearer token without verifying it; the backend still enforces access,
+// this only avoids offering calls that are bound to fail. It returns
+// false for opaque tokens and tokens without a scope claim.
+func jwtScopes(token string) ([]string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+	var claims struct {
+		Scope interface{} `json:"scope"`
+		Scp   interface{} `json:"scp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, false
+	}
+	for _, claim := range []interface{}{claims.Scope, claims.Scp} {
+		if scopes, ok := scopeList(claim); ok {
+			return scopes, true
+		}
+	}
+	return nil, false
+}
+
+func scopeList(raw interface{}) ([]string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return strings.Fields(v), true
+	case []interface{}:
+		scopes := make([]string, 0, len(v))
+		for _, item := range v {
+			if text, ok := item.(string); ok {
+				scopes = append(scopes, text)
+			}
+		}
+		return scopes, true
+	}
+	return nil, false
+}
+
+// loadGrantedScopes asks /v1/whoami which scopes each configured
+// credential carries. A credential whose scopes cannot be determined is
+// left unenforced.
+func (s *Server) loadGrantedScopes(ctx context.Context) {
+	s.grantedScopes = map[*kaizenAPIClient][]string{}
+	clients := []*kaizenAPIClient{s.client}
+	for _, client := range s.profiles {
+		clients = append(clients, client)
+	}
+	for _, client := range clients {
+		if _, done := s.grantedScopes[client]; done {
+			continue
+		}
+		identity, err := client.typed().WhoAmI(ctx)
+		if err != nil {
+			s.logger.Warn("failed to load credential scopes; tools will not be filtered by scope", "base_url", client.baseURL, "error", err.Error())
+			continue
+		}
+		if identity.Scopes != nil {
+			s.grantedScopes[client] = identity.Scopes
+		}
+	}
+}
+
+// credentialScopes returns the scopes of the credential the current call
+// uses: the HTTP caller's token under passthrough auth, otherwise the
+// selected client's key.
+func (s *Server) credentialScopes(ctx context.Context) ([]string, bool) {
+	if !s.enforceScopes {
+		return nil, false
+	}
+	if token, ok := ctx.Value(callerCredentialContextKey{}).(string); ok && token != "" {
+		return jwtScopes(token)
+	}
+	scopes, ok := s.grantedScopes[s.apiClient(ctx)]
+	return scopes, ok
+}
+
+// requireScope refuses calls whose credential lacks the tool's scope,
+// before they reach the backend.
+func (s *Server) requireScope(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		granted, known := s.credentialScopes(ctx)
+		if required := requiredScope(call.Name, call.Mutating); known && !scopeGranted(granted, required) {
+			return toolErrorResult(fmt.Sprintf("%s requires the %s scope, which the credential in use does not have (granted: %s)", call.Name, required, strings.Join(granted, ", ")))
+		}
+		return next(ctx, call)
+	}
+}
+
+// hideUnscopedTools drops tools the caller's credential cannot use from
+// tools/list.
+func (s *Server) hideUnscopedTools(ctx context.Context, defs []toolDefinition) []toolDefinition {
+	granted, known := s.credentialScopes(ctx)
+	if !known {
+		return defs
+	}
+	visible := make([]toolDefinition, 0, len(defs))
+	for _, def := range defs {
+		tool, _ := s.lookupTool(def.Name)
+		if scopeGranted(granted, requiredScope(tool.def.Name, tool.mutating)) {
+			visible = append(visible, def)
+		}
+	}
+	return visible
+}
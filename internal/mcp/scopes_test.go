@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestScopeGranted(t *testing.T) {
+	cases := []struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		{nil, "", true},
+		{[]string{"akuma:read"}, "akuma:read", true},
+		{[]string{"akuma:read"}, "akuma:write", false},
+		{[]string{"akuma:write"}, "akuma:read", true},
+		{[]string{"enzan:*"}, "enzan:write", true},
+		{[]string{"*"}, "sozo:write", true},
+		{[]string{"enzan:write"}, "sozo:read", false},
+	}
+	for _, tc := range cases {
+		if got := scopeGranted(tc.granted, tc.required); got != tc.want {
+			t.Errorf("scopeGranted(%v, %q) = %v, want %v", tc.granted, tc.required, got, tc.want)
+		}
+	}
+}
+
+func TestRequiredScope(t *testing.T) {
+	if got := requiredScope("enzan.set_budget", true); got != "enzan:write" {
+		t.Fatalf("got %q", got)
+	}
+	if got := requiredScope("akuma.query", false); got != "akuma:read" {
+		t.Fatalf("got %q", got)
+	}
+	if got := requiredScope("kaizen.status", false); got != "" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestJWTScopes(t *testing.T) {
+	encode := func(claims string) string {
+		return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".sig"
+	}
+	if scopes, ok := jwtScopes(encode(`{"scope":"akuma:read enzan:write"}`)); !ok || strings.Join(scopes, ",") != "akuma:read,enzan:write" {
+		t.Fatalf("scope claim = %v, %v", scopes, ok)
+	}
+	if scopes, ok := jwtScopes(encode(`{"scp":["sozo:read"]}`)); !ok || len(scopes) != 1 || scopes[0] != "sozo:read" {
+		t.Fatalf("scp claim = %v, %v", scopes, ok)
+	}
+	if _, ok := jwtScopes(encode(`{"sub":"u1"}`)); ok {
+		t.Fatal("token without a scope claim should be unknown")
+	}
+	if _, ok := jwtScopes("kz_live_opaque"); ok {
+		t.Fatal("opaque token should be unknown")
+	}
+}
+
+func TestScopeEnforcementHidesAndRefusesTools(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/whoami": `{"subject":"svc-ci","scopes":["akuma:read","enzan:read"]}`,
+	})
+	defer cleanup()
+	s.enforceScopes = true
+	s.loadGrantedScopes(context.Background())
+
+	listed := map[string]bool{}
+	for _, def := range s.hideUnscopedTools(context.Background(), s.listTools()) {
+		listed[def.Name] = true
+	}
+	for name, want := range map[string]bool{
+		"akuma.query":      true,
+		"enzan.summary":    true,
+		"kaizen.status":    true,
+		"akuma.schema":     false,
+		"enzan.set_budget": false,
+		"sozo.generate":    false,
+	} {
+		if listed[name] != want {
+			t.Errorf("%s listed = %v, want %v", name, listed[name], want)
+		}
+	}
+
+	captured = nil
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.set_budget", Arguments: map[string]interface{}{"amountUSD": 10, "period": "monthly"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	resultMap := result.(map[string]interface{})
+	content := resultMap["content"].([]map[string]string)
+	if resultMap["isError"] != true || !strings.Contains(content[0]["text"], "requires the enzan:write scope") {
+		t.Fatalf("expected scope error, got %#v", resultMap)
+	}
+	if len(captured) != 0 {
+		t.Fatalf("refused call reached the backend: %+v", captured)
+	}
+}
+
+func TestScopeEnforcementSkipsUnknownScopes(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/whoami": `{"subject":"svc-ci"}`,
+	})
+	defer cleanup()
+	s.enforceScopes = true
+	s.loadGrantedScopes(context.Background())
+
+	if got, all := len(s.hideUnscopedTools(context.Background(), s.listTools())), len(s.listTools()); got != all {
+		t.Fatalf("listed %d of %d tools with unknown scopes", got, all)
+	}
+}
@@ -11,8 +11,15 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
 )
 
 type Server struct {
@@ -20,175 +27,439 @@ type Server struct {
 	writer *bufio.Writer
 	logger *slog.Logger
 	client *kaizenAPIClient
+	config *serverConfig
+	// redactor scrubs credentials and denied fields from logs,
+	// transcripts, audit entries, and tool errors.
+	redactor *redactor
+	// tracer exports OpenTelemetry spans when an OTLP endpoint is set.
+	tracer *tracer
+	// logExporter ships logs over OTLP when OTEL_LOGS_EXPORTER=otlp.
+	logExporter *logExporter
+	// frames dumps every JSON-RPC frame when DebugFrames is enabled.
+	frames *frameDump
+
+	// writeMu serializes writes to writer so notifications sent while a
+	// tool call runs never interleave with a response.
+	writeMu sync.Mutex
+
+	// tools maps tool names to definitions and handlers. Nil falls back
+	// to the built-in set.
+	tools *Registry
+	// toolPrefix namespaces the tool names clients see (KAIZEN_TOOLS_PREFIX).
+	toolPrefix string
+
+	// toolFilter is kept so tools discovered from the backend honor the
+	// same enable/disable patterns as configured ones.
+	toolFilter toolFilter
+	// discovered holds the tools declared at /v1/tools when
+	// toolsDiscovery is set. Each refresh swaps in a new registry.
+	discovered             atomic.Pointer[Registry]
+	toolsDiscovery         bool
+	toolsDiscoveryInterval time.Duration
+
+	profiles       map[string]*kaizenAPIClient
+	defaultProfile string
+
+	// coerceArgs converts string-typed numbers/booleans and similar
+	// near-misses to the schema type before validation.
+	coerceArgs bool
+
+	// readOnly hides and refuses mutating tools (see SetReadOnly).
+	readOnly bool
+
+	// slowCallThreshold, when positive, is the duration past which tool
+	// calls and backend requests are logged as slow.
+	slowCallThreshold time.Duration
+
+	// enzanTimezone is the default timezone for zoned Enzan tools.
+	enzanTimezone string
+
+	// currency holds the configured default currency and conversion
+	// rates for cost-reporting Enzan tools; nil leaves figures in USD.
+	currency *currencyRates
+
+	// outputFormat is the default text rendering for tabular tools, json
+	// or markdown; a call's outputFormat argument overrides it.
+	outputFormat string
+
+	// sozoOutputDir is where sozo.generate writes outputFile exports.
+	sozoOutputDir string
+
+	// resultMaxBytes caps the inline text of a successful result; larger
+	// results are paged through kaizen.result.page. Zero disables it.
+	resultMaxBytes int
+	results        resultCache
+
+	// slots enforces the config file's toolConcurrency limits.
+	slots toolSlots
+
+	// costCeilings holds the config file's per-tool cost ceilings in USD
+	// for budget-guarded tools.
+	costCeilings map[string]float64
+
+	// toolMiddleware wraps every tool call (see UseToolMiddleware).
+	toolMiddleware []ToolMiddleware
+
+	// enforceScopes hides and refuses tools whose akuma/enzan/sozo scope
+	// the credential lacks. grantedScopes holds each client's scopes as
+	// reported by /v1/whoami; clients missing from it are not enforced.
+	enforceScopes bool
+	grantedScopes map[*kaizenAPIClient][]string
+
+	// passthroughAuth forwards each HTTP caller's bearer token to the
+	// Kaizen API instead of the shared KAIZEN_API_KEY.
+	passthroughAuth bool
+	// httpToken is the bearer token HTTP callers must present when they
+	// do not bring their own credentials (KAIZEN_MCP_HTTP_TOKEN).
+	// Without it, HTTP mode only listens on loopback.
+	httpToken string
+
+	// readiness caches the backend check behind /readyz.
+	readiness readiness
+
+	// stats counts tool calls and cache lookups for mcp.stats.
+	stats serverStats
 }
 
-func NewServer() *Server {
-	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+func NewServer() (*Server, error) {
+	config, err := loadServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := newRedactor(config.RedactFields)
+	if err != nil {
+		return nil, err
+	}
+	redactor.addSecret(os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN"))
+	output, err := logOutput()
+	if err != nil {
+		return nil, err
+	}
+	var handler slog.Handler = slog.NewJSONHandler(output, &slog.HandlerOptions{
+		Level:       slog.LevelInfo,
+		ReplaceAttr: redactor.replaceAttr,
+	})
+	stderrLogger := slog.New(handler)
+	logExporter, err := newLogExporterFromEnv(func(err error) {
+		// Only to stderr: a failed export must not queue more records.
+		stderrLogger.Warn("failed to export logs", "error", err.Error())
+	})
+	if err != nil {
+		return nil, err
+	}
+	if logExporter != nil {
+		handler = teeHandler{handler, newOTLPLogHandler(logExporter, redactor, slog.LevelInfo)}
+	}
+	logger := slog.New(handler)
+
+	client, err := newKaizenAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure Kaizen API client: %w", err)
+	}
+	redactor.addSecret(client.apiKey)
+
+	passthroughAuth, err := strconv.ParseBool(getEnv("KAIZEN_API_PASSTHROUGH_AUTH", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_API_PASSTHROUGH_AUTH: %w", err)
+	}
+	client.passthroughAuth = passthroughAuth
+	httpToken := strings.TrimSpace(os.Getenv("KAIZEN_MCP_HTTP_TOKEN"))
+	redactor.addSecret(httpToken)
+	coerceArgs, err := strconv.ParseBool(getEnv("KAIZEN_MCP_COERCE_ARGS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_MCP_COERCE_ARGS: %w", err)
+	}
+
+	tracer, err := newTracerFromEnv(func(err error) {
+		logger.Warn("failed to export trace spans", "error", err.Error())
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return &Server{
-		reader: bufio.NewReader(os.Stdin),
-		writer: bufio.NewWriter(os.Stdout),
-		logger: logger,
-		client: newKaizenAPIClient(),
+	tools := newBuiltinToolRegistry()
+	registerCustomTools(tools, config.CustomTools)
+	if err := loadOpenAPITools(client, tools, logger); err != nil {
+		return nil, err
+	}
+	filter, err := newToolFilter(config)
+	if err != nil {
+		return nil, err
+	}
+	tools.retain(filter.allows)
+	underscoreNames, err := strconv.ParseBool(getEnv("KAIZEN_TOOLS_UNDERSCORE_NAMES", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_TOOLS_UNDERSCORE_NAMES: %w", err)
+	}
+	if err := tools.applyToolAliases(config.ToolAliases, underscoreNames); err != nil {
+		return nil, fmt.Errorf("invalid tool aliases: %w", err)
+	}
+	toolPrefix := getEnv("KAIZEN_TOOLS_PREFIX", "")
+	if err := validateToolPrefix(toolPrefix); err != nil {
+		return nil, err
+	}
+	toolsDiscovery, err := strconv.ParseBool(getEnv("KAIZEN_TOOLS_DISCOVERY", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_TOOLS_DISCOVERY: %w", err)
+	}
+	enforceScopes, err := strconv.ParseBool(getEnv("KAIZEN_TOOLS_ENFORCE_SCOPES", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_TOOLS_ENFORCE_SCOPES: %w", err)
+	}
+	resultMaxBytes, err := strconv.Atoi(getEnv("KAIZEN_MCP_RESULT_MAX_BYTES", strconv.Itoa(defaultResultMaxBytes)))
+	if err != nil || resultMaxBytes < 0 {
+		return nil, fmt.Errorf("invalid KAIZEN_MCP_RESULT_MAX_BYTES: must be a non-negative byte count")
 	}
+	var slowCallThreshold time.Duration
+	if raw := getEnv("KAIZEN_MCP_SLOW_CALL_THRESHOLD", ""); raw != "" {
+		if slowCallThreshold, err = time.ParseDuration(raw); err != nil || slowCallThreshold <= 0 {
+			return nil, fmt.Errorf("invalid KAIZEN_MCP_SLOW_CALL_THRESHOLD %q: expected a positive duration such as 5s", raw)
+		}
+	}
+	enzanTimezone := getEnv("KAIZEN_ENZAN_TIMEZONE", "")
+	if enzanTimezone != "" {
+		if err := validateTimezone(enzanTimezone); err != nil {
+			return nil, fmt.Errorf("invalid KAIZEN_ENZAN_TIMEZONE: %w", err)
+		}
+	}
+	outputFormat := getEnv("KAIZEN_MCP_OUTPUT_FORMAT", outputFormatJSON)
+	if outputFormat != outputFormatJSON && outputFormat != outputFormatMarkdown {
+		return nil, fmt.Errorf("invalid KAIZEN_MCP_OUTPUT_FORMAT %q: expected json or markdown", outputFormat)
+	}
+	toolsDiscoveryInterval, err := time.ParseDuration(getEnv("KAIZEN_TOOLS_DISCOVERY_INTERVAL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAIZEN_TOOLS_DISCOVERY_INTERVAL: %w", err)
+	}
+
+	profiles := newProfileClients(client, config.Profiles)
+	for _, profile := range profiles {
+		redactor.addSecret(profile.apiKey)
+	}
+	if config.DefaultProfile != "" {
+		client = profiles[config.DefaultProfile]
+	}
+
+	s := &Server{
+		reader:                 bufio.NewReader(os.Stdin),
+		writer:                 bufio.NewWriter(os.Stdout),
+		logger:                 logger,
+		client:                 client,
+		config:                 config,
+		redactor:               redactor,
+		tracer:                 tracer,
+		logExporter:            logExporter,
+		tools:                  tools,
+		toolFilter:             filter,
+		toolPrefix:             toolPrefix,
+		toolsDiscovery:         toolsDiscovery,
+		toolsDiscoveryInterval: toolsDiscoveryInterval,
+		profiles:               profiles,
+		defaultProfile:         config.DefaultProfile,
+		passthroughAuth:        passthroughAuth,
+		httpToken:              httpToken,
+		coerceArgs:             coerceArgs,
+		outputFormat:           outputFormat,
+		slowCallThreshold:      slowCallThreshold,
+		enzanTimezone:          enzanTimezone,
+		currency:               newCurrencyRates(config.Currency),
+		stats:                  serverStats{started: time.Now()},
+		sozoOutputDir:          getEnv("KAIZEN_SOZO_OUTPUT_DIR", ""),
+		resultMaxBytes:         resultMaxBytes,
+		slots:                  newToolSlots(config.ToolConcurrency),
+		costCeilings:           config.CostCeilings,
+		enforceScopes:          enforceScopes,
+	}
+	if s.currency != nil {
+		s.currency.stats = &s.stats
+	}
+	if tracer != nil {
+		s.UseClientMiddleware(tracer.traceRequests)
+	}
+	if slowCallThreshold > 0 {
+		s.UseClientMiddleware(warnSlowRequests(logger, slowCallThreshold))
+	}
+	if enforceScopes {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		s.loadGrantedScopes(ctx)
+		cancel()
+	}
+	if toolsDiscovery {
+		// Like the remote OpenAPI spec, an unreachable backend only
+		// delays discovery until the next refresh.
+		if _, err := s.refreshDiscoveredTools(context.Background()); err != nil {
+			logger.Warn("failed to discover backend tools", "error", err.Error())
+		}
+	}
+	return s, nil
 }
 
 func (s *Server) Serve() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watchDiscoveredTools(ctx, s.notify)
+	ctx = withNotifier(ctx, s.notify)
+	// A stdio connection is a single session for the server's lifetime.
+	ctx = withSession(ctx, "stdio-"+randomHex(8))
 	for {
 		payload, err := readMessage(s.reader)
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				return nil
 			}
+			s.frames.readError("stdio", err)
 			return fmt.Errorf("failed to read message: %w", err)
 		}
+		s.frames.payload("stdio", frameInbound, payload)
 
-		var req jsonRPCRequest
-		if err := json.Unmarshal(payload, &req); err != nil {
-			s.logger.Warn("dropping invalid json-rpc payload", "error", err)
+		resp := s.handleMessage(ctx, payload)
+		if resp == nil {
 			continue
 		}
-
-		if req.Method == "notifications/initialized" || req.Method == "initialized" {
-			continue
+		s.frames.message("stdio", frameOutbound, *resp)
+		s.writeMu.Lock()
+		err = writeMessage(s.writer, *resp)
+		s.writeMu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
 		}
+	}
+}
 
-		var (
-			result interface{}
-			rpcErr *jsonRPCError
-		)
-
-		switch req.Method {
-		case "initialize":
-			result = map[string]interface{}{
-				"protocolVersion": protocol,
-				"capabilities": map[string]interface{}{
-					"tools": map[string]interface{}{},
-				},
-				"serverInfo": map[string]string{
-					"name":    serverName,
-					"version": serverVersion,
-				},
-			}
-		case "ping":
-			result = map[string]interface{}{}
-		case "tools/list":
-			result = map[string]interface{}{"tools": toolDefinitions()}
-		case "tools/call":
-			result, rpcErr = s.handleToolCall(req.Params)
-		default:
-			rpcErr = &jsonRPCError{Code: -32601, Message: "method not found", Data: req.Method}
-		}
+// notify writes a server-initiated notification to the stdio client.
+func (s *Server) notify(method string, params interface{}) {
+	notification := jsonRPCNotification{JSONRPC: "2.0", Method: method, Params: params}
+	s.frames.message("stdio", frameOutbound, notification)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := writeMessage(s.writer, notification); err != nil {
+		s.logger.Warn("failed to write notification", "method", method, "error", err.Error())
+	}
+}
 
-		if len(req.ID) == 0 {
-			continue
-		}
+// handleMessage dispatches one JSON-RPC message and returns the response
+// to send, or nil for notifications and undecodable payloads. It is shared
+// by the stdio and HTTP transports.
+func (s *Server) handleMessage(ctx context.Context, payload []byte) *jsonRPCResponse {
+	var req jsonRPCRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		s.logger.Warn("dropping invalid json-rpc payload", "error", err)
+		return nil
+	}
 
-		var id interface{}
-		if err := json.Unmarshal(req.ID, &id); err != nil {
-			id = string(req.ID)
-		}
+	if req.Method == "notifications/initialized" || req.Method == "initialized" {
+		return nil
+	}
 
-		resp := jsonRPCResponse{
-			JSONRPC: "2.0",
-			ID:      id,
-			Result:  result,
-			Error:   rpcErr,
+	ctx, sp := s.tracer.start(ctx, req.Method, spanKindServer)
+	defer sp.finish()
+	sp.set("rpc.system", "jsonrpc")
+	sp.set("rpc.method", req.Method)
+
+	var (
+		result interface{}
+		rpcErr *jsonRPCError
+	)
+
+	switch req.Method {
+	case "initialize":
+		initialized := map[string]interface{}{
+			"protocolVersion": protocol,
+			"capabilities": map[string]interface{}{
+				// Only stdio can deliver notifications/tools/list_changed.
+				"tools": map[string]interface{}{"listChanged": s.toolsDiscovery && canNotify(ctx)},
+			},
+			"serverInfo": map[string]string{
+				"name":    serverName,
+				"version": serverVersion,
+			},
 		}
-		if err := writeMessage(s.writer, resp); err != nil {
-			return fmt.Errorf("failed to write response: %w", err)
+		if s.needsConfiguration(ctx) {
+			initialized["instructions"] = setupInstructions
 		}
+		result = initialized
+	case "ping":
+		result = map[string]interface{}{}
+	case "tools/list":
+		result = map[string]interface{}{"tools": s.prefixToolNames(s.annotateUnconfigured(ctx, s.hideUnscopedTools(ctx, s.listTools())))}
+	case "tools/call":
+		result, rpcErr = s.handleToolCall(ctx, req.Params)
+	default:
+		rpcErr = &jsonRPCError{Code: -32601, Message: "method not found", Data: req.Method}
+	}
+	if rpcErr != nil {
+		sp.set("rpc.jsonrpc.error_code", rpcErr.Code)
+		sp.fail(rpcErr.Message)
+	}
+
+	if len(req.ID) == 0 {
+		return nil
+	}
+
+	var id interface{}
+	if err := json.Unmarshal(req.ID, &id); err != nil {
+		id = string(req.ID)
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+		Error:   rpcErr,
 	}
 }
 
-func (s *Server) handleToolCall(raw json.RawMessage) (interface{}, *jsonRPCError) {
+func (s *Server) handleToolCall(ctx context.Context, raw json.RawMessage) (result interface{}, rpcErr *jsonRPCError) {
+	// A panicking handler or middleware fails only its own call; left
+	// alone it would take down the stdio session of every other call.
+	// Panics inside the middleware chain are recovered there, so the call
+	// is still counted, and reported through panicked.
 	var params toolsCallParams
+	ctx, panicked := withToolPanic(ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.logPanic(ctx, params.Name, recovered, debug.Stack())
+			result, rpcErr = nil, toolPanicError(params.Name, "")
+		}
+	}()
 	if err := json.Unmarshal(raw, &params); err != nil {
 		return nil, &jsonRPCError{Code: -32602, Message: "invalid tool call params", Data: err.Error()}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	var (
-		data map[string]interface{}
-		err  error
-	)
-
-	switch params.Name {
-	case "akuma.query":
-		data, err = s.callAkumaQuery(ctx, params.Arguments)
-	case "akuma.query_interactive":
-		data, err = s.callAkumaQueryInteractive(ctx, params.Arguments)
-	case "akuma.explain":
-		data, err = s.callAkumaExplain(ctx, params.Arguments)
-	case "akuma.schema":
-		data, err = s.callAkumaSchema(ctx, params.Arguments)
-	case "enzan.summary":
-		data, err = s.callEnzanSummary(ctx, params.Arguments)
-	case "enzan.costs_by_model":
-		data, err = s.callEnzanCostsByModel(ctx, params.Arguments)
-	case "enzan.routing":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/routing", nil)
-	case "enzan.set_routing":
-		data, err = s.callEnzanSetRouting(ctx, params.Arguments)
-	case "enzan.routing_savings":
-		data, err = s.callEnzanRoutingSavings(ctx, params.Arguments)
-	case "enzan.pricing_models":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/pricing/models", nil)
-	case "enzan.set_model_pricing":
-		data, err = s.callEnzanSetModelPricing(ctx, params.Arguments)
-	case "enzan.pricing_gpus":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/pricing/gpus", nil)
-	case "enzan.set_gpu_pricing":
-		data, err = s.callEnzanSetGPUPricing(ctx, params.Arguments)
-	case "enzan.pricing_refresh_trigger":
-		// Preserve 429 {status:"dropped",triggeredBy:...} body so MCP
-		// callers can branch on the typed shape, matching the SDK contract.
-		data, err = s.callPreservingTypedBody(ctx, "POST", "/v1/enzan/pricing/refresh", nil, []int{http.StatusTooManyRequests})
-	case "enzan.pricing_refresh_log":
-		data, err = s.callEnzanPricingRefreshLog(ctx, params.Arguments)
-	case "enzan.pricing_providers":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/pricing/providers", nil)
-	case "enzan.pricing_offers_upsert":
-		data, err = s.callEnzanPricingOffersUpsert(ctx, params.Arguments)
-	case "enzan.optimize":
-		data, err = s.callEnzanOptimize(ctx, params.Arguments)
-	case "enzan.alerts":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/alerts", nil)
-	case "enzan.create_alert":
-		data, err = s.callEnzanCreateAlert(ctx, params.Arguments)
-	case "enzan.update_alert":
-		data, err = s.callEnzanUpdateAlert(ctx, params.Arguments)
-	case "enzan.delete_alert":
-		data, err = s.callEnzanDeleteAlert(ctx, params.Arguments)
-	case "enzan.alert_events":
-		data, err = s.callEnzanAlertEvents(ctx, params.Arguments)
-	case "enzan.alert_deliveries":
-		data, err = s.callEnzanAlertDeliveries(ctx, params.Arguments)
-	case "enzan.alert_endpoints":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/alerts/endpoints", nil)
-	case "enzan.create_alert_endpoint":
-		data, err = s.callEnzanCreateAlertEndpoint(ctx, params.Arguments)
-	case "enzan.update_alert_endpoint":
-		data, err = s.callEnzanUpdateAlertEndpoint(ctx, params.Arguments)
-	case "enzan.delete_alert_endpoint":
-		data, err = s.callEnzanDeleteAlertEndpoint(ctx, params.Arguments)
-	case "enzan.chat":
-		data, err = s.callEnzanChat(ctx, params.Arguments)
-	case "enzan.burn":
-		data, err = s.client.call(ctx, "GET", "/v1/enzan/burn", nil)
-	case "sozo.generate":
-		data, err = s.callSozoGenerate(ctx, params.Arguments)
-	case "sozo.schemas":
-		data, err = s.client.call(ctx, "GET", "/v1/sozo/schemas", nil)
-	default:
+	tool, ok := s.lookupTool(params.Name)
+	if !ok {
 		return nil, &jsonRPCError{Code: -32602, Message: "unknown tool", Data: params.Name}
 	}
+	// Resolve aliases so timeouts, logs, and errors use the canonical name.
+	params.Name = tool.def.Name
+
+	called := s.callTool(ctx, tool, params)
+	if panicked.recovered {
+		return nil, toolPanicError(params.Name, panicked.requestID)
+	}
+	if warning := tool.deprecationWarning(s.toolPrefix); warning != "" {
+		content, _ := called["content"].([]map[string]string)
+		called["content"] = append([]map[string]string{{"type": "text", "text": warning}}, content...)
+	}
+	return called, nil
+}
+
+// invokeTool is the innermost step of callTool: it runs the handler under
+// the tool's deadline and renders the result.
+func (s *Server) invokeTool(ctx context.Context, call ToolCall) map[string]interface{} {
+	ctx = withIdempotencyKey(ctx, newIdempotencyKey())
+	ctx = withProgress(ctx, call.progressToken)
+	ctx, links := withResourceLinks(ctx)
+	ctx, cancel := context.WithTimeout(ctx, s.toolTimeout(call.Name))
+	defer cancel()
+
+	data, err := call.tool.handler(ctx, s, call.Arguments)
 
 	if err != nil {
+		var panicErr *panicError
+		if errors.As(err, &panicErr) {
+			s.logPanic(ctx, call.Name, panicErr.recovered, panicErr.stack)
+			return toolPanicked(ctx, call.Name)
+		}
 		// typedBodyError carries a meaningful response body alongside a
 		// transport failure status or semantic failure state. Thread BOTH
 		// signals: isError=true so generic MCP clients see the failure,
@@ -201,103 +472,137 @@ func (s *Server) handleToolCall(raw json.RawMessage) (interface{}, *jsonRPCError
 				"content":           []map[string]string{{"type": "text", "text": fmt.Sprintf("%s:\n%s", typedErr.Error(), pretty)}},
 				"structuredContent": typedErr.Body,
 				"isError":           true,
-			}, nil
+			}
 		}
-		return map[string]interface{}{
-			"content": []map[string]string{{"type": "text", "text": err.Error()}},
-			"isError": true,
-		}, nil
+		return toolErrorResult(err.Error())
 	}
 
-	pretty, _ := json.MarshalIndent(data, "", "  ")
+	texts := call.tool.renderText(call.Arguments, data)
+	content := make([]map[string]string, len(texts))
+	for i, text := range texts {
+		content[i] = map[string]string{"type": "text", "text": text}
+	}
+	content = append(content, links.blocks()...)
 	return map[string]interface{}{
-		"content":           []map[string]string{{"type": "text", "text": string(pretty)}},
+		"content":           content,
 		"structuredContent": data,
-	}, nil
+	}
+}
+
+// renderText is the text content of a successful result: the tool's own
+// rendering when it has one, else pretty-printed JSON.
+func (t registeredTool) renderText(args, data map[string]interface{}) []string {
+	var texts []string
+	if t.render != nil {
+		texts, _ = t.render(args, data)
+	}
+	if len(texts) == 0 {
+		pretty, _ := json.MarshalIndent(data, "", "  ")
+		texts = []string{string(pretty)}
+	}
+	return texts
+}
+
+// toolTimeout resolves the backend deadline for one tool call: a per-tool
+// override from the config file wins, then the client-wide timeout.
+func (s *Server) toolTimeout(name string) time.Duration {
+	if s.config != nil {
+		if timeout, ok := s.config.ToolTimeouts[name]; ok {
+			return time.Duration(timeout)
+		}
+	}
+	if s.client != nil {
+		return s.client.defaultTimeout()
+	}
+	return defaultAPITimeout
 }
 
 func (s *Server) callAkumaQuery(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	payload, err := buildAkumaQueryPayload(args)
+	payload, err := s.akumaQueryPayload(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.kaizenClient(ctx).AkumaQuery(ctx, *payload)
 	if err != nil {
 		return nil, err
 	}
-	return s.client.call(ctx, http.MethodPost, "/v1/akuma/query", payload)
+	return capRows(resp.Fields(), payload.MaxRows), nil
 }
 
 func (s *Server) callAkumaQueryInteractive(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	payload, err := buildAkumaQueryPayload(args)
+	payload, err := s.akumaQueryPayload(args)
 	if err != nil {
 		return nil, err
 	}
 	// Interactive keeps its own call path because it preserves typed non-2xx
 	// bodies and converts HTTP 200 non-completed envelopes into MCP tool errors.
-	data, err := s.callPreservingTypedBody(ctx, http.MethodPost, "/v1/akuma/queries/interactive", payload, []int{
-		http.StatusBadRequest,
-		http.StatusUnauthorized,
-		http.StatusForbidden,
-		http.StatusMethodNotAllowed,
-		http.StatusNotFound,
-		http.StatusConflict,
-		http.StatusUnprocessableEntity,
-		http.StatusTooManyRequests,
-		http.StatusServiceUnavailable,
-		http.StatusGatewayTimeout,
-		http.StatusInternalServerError,
-	})
+	resp, err := s.kaizenClient(ctx).AkumaQueryInteractive(ctx, *payload)
 	if err != nil {
+		return nil, preserveTypedBody(err, []int{
+			http.StatusBadRequest,
+			http.StatusUnauthorized,
+			http.StatusForbidden,
+			http.StatusMethodNotAllowed,
+			http.StatusNotFound,
+			http.StatusConflict,
+			http.StatusUnprocessableEntity,
+			http.StatusTooManyRequests,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+			http.StatusInternalServerError,
+		})
+	}
+	if err := validateAkumaInteractiveResponse(resp); err != nil {
 		return nil, err
 	}
-	if err := validateAkumaInteractiveResponse(data); err != nil {
-		return nil, err
+	data := resp.Fields()
+	if resp.Status != "completed" {
+		return nil, &typedBodyError{Status: http.StatusOK, Body: data, Msg: "interactive query " + resp.Status}
 	}
-	if data["status"] != "completed" {
-		return nil, &typedBodyError{Status: http.StatusOK, Body: data, Msg: fmt.Sprintf("interactive query %s", data["status"])}
+	if result, ok := data["result"].(map[string]interface{}); ok {
+		data["result"] = capRows(result, payload.MaxRows)
 	}
 	return data, nil
 }
 
-func buildAkumaQueryPayload(args map[string]interface{}) (map[string]interface{}, error) {
-	dialect, _ := args["dialect"].(string)
-	prompt, _ := args["prompt"].(string)
-	if strings.TrimSpace(dialect) == "" {
-		return nil, fmt.Errorf("dialect is required")
-	}
-	if strings.TrimSpace(prompt) == "" {
-		return nil, fmt.Errorf("prompt is required")
-	}
+// capRows enforces maxRows even when the backend returns more, so a
+// result cannot flood the agent's context. Cut results are marked with
+// truncated and the backend's originalRowCount.
+func capRows(data map[string]interface{}, maxRows *int) map[string]interface{} {
+	rows, ok := data["rows"].([]interface{})
+	if maxRows == nil || *maxRows < 0 || !ok || len(rows) <= *maxRows {
+		return data
+	}
+	data["rows"] = rows[:*maxRows]
+	data["truncated"] = true
+	data["originalRowCount"] = len(rows)
+	return data
+}
 
-	payload := map[string]interface{}{
-		"dialect": dialect,
-		"prompt":  prompt,
-	}
-	if v, ok := args["mode"]; ok {
-		payload["mode"] = v
-	}
-	if v, ok := args["maxRows"]; ok {
-		payload["maxRows"] = v
+func buildAkumaQueryPayload(args map[string]interface{}) (*kaizen.AkumaQueryRequest, error) {
+	var req kaizen.AkumaQueryRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
 	}
-	if v, ok := args["sourceId"]; ok {
-		payload["sourceId"] = v
+	if strings.TrimSpace(req.Dialect) == "" {
+		return nil, fmt.Errorf("dialect is required")
 	}
-	if v, ok := args["guardrails"]; ok {
-		payload["guardrails"] = v
+	if strings.TrimSpace(req.Prompt) == "" {
+		return nil, fmt.Errorf("prompt is required")
 	}
-
-	return payload, nil
+	return &req, nil
 }
 
-func validateAkumaInteractiveResponse(data map[string]interface{}) error {
-	status, ok := data["status"].(string)
-	if !ok || strings.TrimSpace(status) == "" {
+func validateAkumaInteractiveResponse(resp *kaizen.AkumaInteractiveResponse) error {
+	status := resp.Status
+	if strings.TrimSpace(status) == "" {
 		return fmt.Errorf("interactive query response missing status")
 	}
 
-	result, hasResult := data["result"]
+	hasResult := len(resp.Result) > 0
 	var resultMap map[string]interface{}
 	if hasResult {
-		var ok bool
-		resultMap, ok = result.(map[string]interface{})
-		if !ok {
+		if err := json.Unmarshal(resp.Result, &resultMap); err != nil || resultMap == nil {
 			return fmt.Errorf("interactive query response result must be an object")
 		}
 	}
@@ -331,7 +636,7 @@ func (s *Server) callEnzanCreateAlertEndpoint(ctx context.Context, args map[stri
 	if signingSecret, ok := args["signingSecret"]; ok {
 		payload["signingSecret"] = signingSecret
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/alerts/endpoints", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanCreateAlertEndpoint(ctx, payload))
 }
 
 func (s *Server) callEnzanSetRouting(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -351,15 +656,12 @@ func (s *Server) callEnzanSetRouting(ctx context.Context, args map[string]interf
 	if value, ok := args["complex_model"]; ok {
 		payload["complex_model"] = value
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/routing", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanSetRouting(ctx, payload))
 }
 
 func (s *Server) callEnzanRoutingSavings(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	path := "/v1/enzan/routing/savings"
-	if window, ok := args["window"].(string); ok && strings.TrimSpace(window) != "" {
-		path += "?window=" + url.QueryEscape(window)
-	}
-	return s.client.call(ctx, "GET", path, nil)
+	window, _ := args["window"].(string)
+	return fieldsOf(s.kaizenClient(ctx).EnzanRoutingSavings(ctx, strings.TrimSpace(window)))
 }
 
 func (s *Server) callEnzanCreateAlert(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -390,7 +692,7 @@ func (s *Server) callEnzanCreateAlert(ctx context.Context, args map[string]inter
 	if enabled, ok := args["enabled"]; ok {
 		payload["enabled"] = enabled
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/alerts", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanCreateAlert(ctx, payload))
 }
 
 func (s *Server) callEnzanUpdateAlert(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -414,23 +716,28 @@ func (s *Server) callEnzanUpdateAlert(ctx context.Context, args map[string]inter
 	if enabled, ok := args["enabled"]; ok {
 		payload["enabled"] = enabled
 	}
-	return s.client.call(ctx, "PATCH", "/v1/enzan/alerts/"+url.PathEscape(id), payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanUpdateAlert(ctx, id, payload))
 }
 
 func (s *Server) callEnzanAlertEvents(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	path := "/v1/enzan/alerts/events"
+	query := url.Values{}
 	if limit, ok := numericToolArg(args, "limit"); ok && limit > 0 {
-		path = fmt.Sprintf("%s?limit=%d", path, limit)
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	for _, key := range []string{"status", "window"} {
+		if value, _ := args[key].(string); value != "" {
+			query.Set(key, value)
+		}
 	}
-	return s.client.call(ctx, "GET", path, nil)
+	return fieldsOf(s.kaizenClient(ctx).EnzanAlertEvents(ctx, query))
 }
 
 func (s *Server) callEnzanAlertDeliveries(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	path := "/v1/enzan/alerts/deliveries"
+	query := url.Values{}
 	if limit, ok := numericToolArg(args, "limit"); ok && limit > 0 {
-		path = fmt.Sprintf("%s?limit=%d", path, limit)
+		query.Set("limit", strconv.Itoa(limit))
 	}
-	return s.client.call(ctx, "GET", path, nil)
+	return fieldsOf(s.kaizenClient(ctx).EnzanAlertDeliveries(ctx, query))
 }
 
 func (s *Server) callEnzanDeleteAlert(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -438,7 +745,7 @@ func (s *Server) callEnzanDeleteAlert(ctx context.Context, args map[string]inter
 	if strings.TrimSpace(id) == "" {
 		return nil, fmt.Errorf("id is required")
 	}
-	return s.client.call(ctx, "DELETE", "/v1/enzan/alerts/"+url.PathEscape(id), nil)
+	return fieldsOf(s.kaizenClient(ctx).EnzanDeleteAlert(ctx, id))
 }
 
 func (s *Server) callEnzanUpdateAlertEndpoint(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -456,7 +763,7 @@ func (s *Server) callEnzanUpdateAlertEndpoint(ctx context.Context, args map[stri
 	if enabled, ok := args["enabled"]; ok {
 		payload["enabled"] = enabled
 	}
-	return s.client.call(ctx, "PATCH", "/v1/enzan/alerts/endpoints/"+url.PathEscape(id), payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanUpdateAlertEndpoint(ctx, id, payload))
 }
 
 func (s *Server) callEnzanDeleteAlertEndpoint(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -464,7 +771,7 @@ func (s *Server) callEnzanDeleteAlertEndpoint(ctx context.Context, args map[stri
 	if strings.TrimSpace(id) == "" {
 		return nil, fmt.Errorf("id is required")
 	}
-	return s.client.call(ctx, "DELETE", "/v1/enzan/alerts/endpoints/"+url.PathEscape(id), nil)
+	return fieldsOf(s.kaizenClient(ctx).EnzanDeleteAlertEndpoint(ctx, id))
 }
 
 func numericToolArg(args map[string]interface{}, key string) (int, bool) {
@@ -485,7 +792,11 @@ func (s *Server) callAkumaExplain(ctx context.Context, args map[string]interface
 	if strings.TrimSpace(sql) == "" {
 		return nil, fmt.Errorf("sql is required")
 	}
-	return s.client.call(ctx, "POST", "/v1/akuma/explain", map[string]interface{}{"sql": sql})
+	req := kaizen.AkumaExplainRequest{SQL: sql}
+	if format, _ := args["format"].(string); format != "" && format != "prose" {
+		req.IncludePlan = true
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaExplain(ctx, req))
 }
 
 func (s *Server) callAkumaSchema(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -511,40 +822,228 @@ func (s *Server) callAkumaSchema(ctx context.Context, args map[string]interface{
 	if version, ok := args["version"]; ok {
 		payload["version"] = version
 	}
-	return s.client.call(ctx, "POST", "/v1/akuma/schema", payload)
+	return fieldsOf(s.kaizenClient(ctx).AkumaRegisterSchema(ctx, payload))
 }
 
-func (s *Server) callEnzanSummary(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	payload := map[string]interface{}{
-		"window": "24h",
+func (s *Server) callAkumaSchemaGet(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	sourceID, _ := args["sourceId"].(string)
+	return fieldsOf(s.kaizenClient(ctx).AkumaSchema(ctx, sourceID))
+}
+
+var connectionNameRegexp = regexp.MustCompile(connectionNamePattern)
+
+func (s *Server) callAkumaSchemaIntrospect(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.AkumaIntrospectRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
 	}
-	if v, ok := args["window"]; ok {
-		payload["window"] = v
+	if !connectionNameRegexp.MatchString(req.Connection) {
+		return nil, fmt.Errorf("connection must be the name of a connection configured on the Kaizen server, not a connection string")
 	}
-	if v, ok := args["groupBy"]; ok {
-		payload["groupBy"] = v
+	return fieldsOf(s.kaizenClient(ctx).AkumaIntrospect(ctx, req))
+}
+
+func (s *Server) callAkumaValidate(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req, err := akumaSQLArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaValidate(ctx, req))
+}
+
+func (s *Server) callAkumaOptimize(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req, err := akumaSQLArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaOptimize(ctx, req))
+}
+
+func (s *Server) callAkumaLint(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req, err := akumaSQLArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaLint(ctx, req))
+}
+
+func (s *Server) callAkumaHistory(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	query := url.Values{}
+	for _, key := range []string{"status", "cursor"} {
+		if value, _ := args[key].(string); value != "" {
+			query.Set(key, value)
+		}
+	}
+	for _, key := range []string{"since", "until"} {
+		value, _ := args[key].(string)
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("%s must be an RFC 3339 timestamp such as 2024-05-01T00:00:00Z", key)
+		}
+		query.Set(key, value)
+	}
+	if limit, ok := numericToolArg(args, "limit"); ok {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaHistory(ctx, query))
+}
+
+func (s *Server) callAkumaDiff(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.AkumaDiffRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Before) == "" || strings.TrimSpace(req.After) == "" {
+		return nil, fmt.Errorf("before and after are required")
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaDiff(ctx, req))
+}
+
+func (s *Server) callAkumaPreview(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.AkumaPreviewRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Table) == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	// The row cap and column masking are enforced server-side; forward
+	// limit as given so the server's clamp stays the single authority.
+	return fieldsOf(s.kaizenClient(ctx).AkumaPreview(ctx, req))
+}
+
+func (s *Server) callAkumaConvert(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.AkumaConvertRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.SQL) == "" {
+		return nil, fmt.Errorf("sql is required")
+	}
+	if req.From == req.To {
+		return nil, fmt.Errorf("from and to must be different dialects")
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/summary", payload)
+	return fieldsOf(s.kaizenClient(ctx).AkumaConvert(ctx, req))
+}
+
+func (s *Server) callAkumaCost(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req, err := akumaSQLArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaCost(ctx, req))
+}
+
+func (s *Server) callAkumaFeedback(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.AkumaFeedbackRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.QueryID) == "" {
+		return nil, fmt.Errorf("queryId is required")
+	}
+	return fieldsOf(s.kaizenClient(ctx).AkumaFeedback(ctx, req))
+}
+
+// akumaSQLArgs decodes the arguments of the Akuma tools that analyze a
+// single SQL statement.
+func akumaSQLArgs(args map[string]interface{}) (kaizen.AkumaSQLRequest, error) {
+	var req kaizen.AkumaSQLRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return req, err
+	}
+	if strings.TrimSpace(req.SQL) == "" {
+		return req, fmt.Errorf("sql is required")
+	}
+	return req, nil
+}
+
+func (s *Server) callEnzanSummary(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req := kaizen.EnzanSummaryRequest{Window: "24h"}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).EnzanSummary(ctx, req))
 }
 
 func (s *Server) callEnzanCostsByModel(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	payload := map[string]interface{}{
-		"window": "30d",
+	req := kaizen.EnzanWindowRequest{Window: "30d"}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
 	}
-	if v, ok := args["window"]; ok {
-		payload["window"] = v
+	return fieldsOf(s.kaizenClient(ctx).EnzanCostsByModel(ctx, req))
+}
+
+func (s *Server) callEnzanForecast(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req := kaizen.EnzanForecastRequest{Horizon: "30d"}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/costs/by-model", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanForecast(ctx, req))
 }
 
-func (s *Server) callEnzanOptimize(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	payload := map[string]interface{}{
-		"window": "30d",
+func (s *Server) callEnzanSetBudget(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	id, _ := args["id"].(string)
+	var req kaizen.EnzanBudgetRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
 	}
-	if v, ok := args["window"]; ok {
-		payload["window"] = v
+	if strings.TrimSpace(id) != "" {
+		return fieldsOf(s.kaizenClient(ctx).EnzanUpdateBudget(ctx, id, req))
+	}
+	if req.AmountUSD == nil {
+		return nil, fmt.Errorf("amountUSD is required when creating a budget")
+	}
+	if req.Period == "" {
+		return nil, fmt.Errorf("period is required when creating a budget")
+	}
+	return fieldsOf(s.kaizenClient(ctx).EnzanCreateBudget(ctx, req))
+}
+
+func (s *Server) callEnzanBreakdown(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	if dimension, _ := args["dimension"].(string); dimension == "label" {
+		if key, _ := args["labelKey"].(string); strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("labelKey is required when dimension is label")
+		}
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/optimize", payload)
+	return clientQueryTool((*kaizen.Client).EnzanBreakdown)(ctx, s, args)
+}
+
+func (s *Server) callEnzanExport(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req := kaizen.EnzanExportRequest{MaxInlineBytes: enzanExportInlineLimit}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	resp, err := s.kaizenClient(ctx).EnzanExport(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.DownloadURL != "" {
+		link := resourceLink{URI: resp.DownloadURL, Name: "enzan-export.csv", MimeType: "text/csv"}
+		if resp.ExpiresAt != "" {
+			link.Description = "Expires at " + resp.ExpiresAt
+		}
+		attachResourceLink(ctx, link)
+	}
+	return resp.Fields(), nil
+}
+
+func (s *Server) callEnzanCompare(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req := kaizen.EnzanCompareRequest{Period: "week"}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).EnzanCompare(ctx, req))
+}
+
+func (s *Server) callEnzanOptimize(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req := kaizen.EnzanWindowRequest{Window: "30d"}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).EnzanOptimize(ctx, req))
 }
 
 func (s *Server) callEnzanChat(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -558,7 +1057,7 @@ func (s *Server) callEnzanChat(ctx context.Context, args map[string]interface{})
 	if v, ok := args["window"]; ok {
 		payload["window"] = v
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/chat", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanChat(ctx, payload))
 }
 
 func (s *Server) callEnzanSetModelPricing(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -587,7 +1086,7 @@ func (s *Server) callEnzanSetModelPricing(ctx context.Context, args map[string]i
 			payload[key] = v
 		}
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/pricing/models", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanSetModelPricing(ctx, payload))
 }
 
 func (s *Server) callEnzanSetGPUPricing(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -612,30 +1111,27 @@ func (s *Server) callEnzanSetGPUPricing(ctx context.Context, args map[string]int
 			payload[key] = v
 		}
 	}
-	return s.client.call(ctx, "POST", "/v1/enzan/pricing/gpus", payload)
+	return fieldsOf(s.kaizenClient(ctx).EnzanSetGPUPricing(ctx, payload))
 }
 
-// callPreservingTypedBody runs an api call. For the listed status codes
-// (429 dropped / 409 stale on the 8.2-public surface), it returns the
-// typed response body wrapped in a *typedBodyError so handleToolCall can
-// surface BOTH (a) `isError: true` — generic MCP clients that branch
-// only on tool failure correctly see a dropped/stale outcome — AND
-// (b) the typed body in `structuredContent` for callers that want to
-// branch on the body shape. Matches the SDK contract that exposes the
-// same bodies via err.data.
-func (s *Server) callPreservingTypedBody(ctx context.Context, method, path string, payload interface{}, preserveStatuses []int) (map[string]interface{}, error) {
-	data, err := s.client.call(ctx, method, path, payload)
-	if err != nil {
-		var apiErr *apiCallError
-		if errors.As(err, &apiErr) {
-			for _, code := range preserveStatuses {
-				if apiErr.Status == code {
-					return nil, &typedBodyError{Status: apiErr.Status, Body: apiErr.Body, Msg: apiErr.Msg}
-				}
+// preserveTypedBody passes err through, except that for the listed status
+// codes (429 dropped / 409 stale on the 8.2-public surface) it wraps the
+// typed response body in a *typedBodyError so handleToolCall can surface
+// BOTH (a) `isError: true` — generic MCP clients that branch only on tool
+// failure correctly see a dropped/stale outcome — AND (b) the typed body
+// in `structuredContent` for callers that want to branch on the body
+// shape. Matches the SDK contract that exposes the same bodies via
+// err.data.
+func preserveTypedBody(err error, preserveStatuses []int) error {
+	var apiErr *apiCallError
+	if errors.As(err, &apiErr) {
+		for _, code := range preserveStatuses {
+			if apiErr.Status == code {
+				return &typedBodyError{Status: apiErr.Status, Body: apiErr.Body, Msg: apiErr.Msg}
 			}
 		}
 	}
-	return data, err
+	return err
 }
 
 // typedBodyError signals that the underlying API call failed or produced a
@@ -650,16 +1146,23 @@ type typedBodyError struct {
 
 func (e *typedBodyError) Error() string { return e.Msg }
 
+func (s *Server) callEnzanPricingRefreshTrigger(ctx context.Context, _ map[string]interface{}) (map[string]interface{}, error) {
+	// Preserve 429 {status:"dropped",triggeredBy:...} body so MCP
+	// callers can branch on the typed shape, matching the SDK contract.
+	resp, err := s.kaizenClient(ctx).EnzanTriggerPricingRefresh(ctx)
+	return fieldsOf(resp, preserveTypedBody(err, []int{http.StatusTooManyRequests}))
+}
+
 func (s *Server) callEnzanPricingRefreshLog(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
-	path := "/v1/enzan/pricing/refresh/log"
+	query := url.Values{}
 	// Forward `limit` verbatim (including 0 and negative) so the server's
 	// "limit must be a positive integer" 400 path stays observable from
 	// MCP. Matches the SDK contract — server is the clamp/validation
 	// authority, clients must not silently drop user-provided values.
 	if limit, ok := numericToolArg(args, "limit"); ok {
-		path = fmt.Sprintf("%s?limit=%d", path, limit)
+		query.Set("limit", strconv.Itoa(limit))
 	}
-	return s.client.call(ctx, "GET", path, nil)
+	return fieldsOf(s.kaizenClient(ctx).EnzanPricingRefreshLog(ctx, query))
 }
 
 func (s *Server) callEnzanPricingOffersUpsert(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
@@ -691,7 +1194,8 @@ func (s *Server) callEnzanPricingOffersUpsert(ctx context.Context, args map[stri
 	}
 	// Preserve 409 {status:"stale"} body so MCP callers can branch on the
 	// typed stale shape, matching the SDK contract.
-	return s.callPreservingTypedBody(ctx, "POST", "/v1/enzan/pricing/offers", payload, []int{http.StatusConflict})
+	resp, err := s.kaizenClient(ctx).EnzanUpsertPricingOffer(ctx, payload)
+	return fieldsOf(resp, preserveTypedBody(err, []int{http.StatusConflict}))
 }
 
 type offerBranchState int
@@ -723,6 +1227,57 @@ func classifyOfferBranch(args map[string]interface{}, key string) (map[string]in
 }
 
 func (s *Server) callSozoGenerate(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	payload, err := buildSozoGeneratePayload(args)
+	if err != nil {
+		return nil, err
+	}
+	format, _ := args["format"].(string)
+	outputFile, _ := args["outputFile"].(string)
+	if stream, _ := args["stream"].(bool); stream {
+		if format != "" || outputFile != "" {
+			return nil, fmt.Errorf("stream cannot be combined with format or outputFile")
+		}
+		return s.streamSozoGenerate(ctx, payload)
+	}
+	if format != "" || outputFile != "" {
+		return s.exportSozoGenerate(ctx, payload, format, outputFile)
+	}
+	resp, err := s.kaizenClient(ctx).SozoGenerate(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	if _, seeded := payload["seed"]; seeded {
+		return s.verifySeededGeneration(resp.Fields(), resp.Body())
+	}
+	return resp.Fields(), nil
+}
+
+// callSozoJobsStart queues the same generation sozo.generate runs
+// synchronously and returns the job id to poll.
+func (s *Server) callSozoJobsStart(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	payload, err := buildSozoGeneratePayload(args)
+	if err != nil {
+		return nil, err
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoStartJob(ctx, payload))
+}
+
+// callSozoJobResult reads a page of a finished job's records.
+func (s *Server) callSozoJobResult(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	id, _ := args["jobId"].(string)
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("jobId is required")
+	}
+	page := map[string]interface{}{}
+	for _, key := range []string{"offset", "limit"} {
+		if value, ok := args[key]; ok && value != nil {
+			page[key] = value
+		}
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoJobResult(ctx, id, queryValues(page)))
+}
+
+func buildSozoGeneratePayload(args map[string]interface{}) (map[string]interface{}, error) {
 	if _, ok := args["records"]; !ok {
 		return nil, fmt.Errorf("records is required")
 	}
@@ -735,18 +1290,115 @@ func (s *Server) callSozoGenerate(ctx context.Context, args map[string]interface
 	payload := map[string]interface{}{
 		"records": args["records"],
 	}
-	for _, key := range []string{"schema", "schemaName", "correlations", "seed"} {
+	for _, key := range []string{"schema", "schemaName", "correlations", "seed", "runName"} {
 		if v, ok := args[key]; ok {
 			payload[key] = v
 		}
 	}
-	return s.client.call(ctx, "POST", "/v1/sozo/generate", payload)
+	return payload, nil
+}
+
+func (s *Server) callSozoValidate(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.SozoValidateRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Schema) == 0 {
+		return nil, fmt.Errorf("schema must not be empty")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoValidate(ctx, req))
+}
+
+func (s *Server) callSozoPreview(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	req := kaizen.SozoPreviewRequest{Records: sozoPreviewDefaultRecords}
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if req.Schema == nil && strings.TrimSpace(req.SchemaName) == "" {
+		return nil, fmt.Errorf("schema or schemaName is required")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoPreview(ctx, req))
+}
+
+func (s *Server) callSozoAnonymize(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.SozoAnonymizeRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Records) == 0 {
+		return nil, fmt.Errorf("records must not be empty")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoAnonymize(ctx, req))
+}
+
+func (s *Server) callSozoSchemaSave(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.SozoPresetRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Schema) == 0 {
+		return nil, fmt.Errorf("schema must not be empty")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoSaveSchema(ctx, req))
+}
+
+func (s *Server) callSozoAugment(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.SozoAugmentRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if len(req.SeedRecords) == 0 {
+		return nil, fmt.Errorf("seedRecords must not be empty")
+	}
+	if req.Records <= 0 {
+		return nil, fmt.Errorf("records must be positive")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoAugment(ctx, req))
+}
+
+func (s *Server) callSozoDrift(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.SozoDriftRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if req.Schema == nil && strings.TrimSpace(req.SchemaName) == "" {
+		return nil, fmt.Errorf("schema or schemaName is required")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoDrift(ctx, req))
+}
+
+func (s *Server) callSozoCorrelations(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	var req kaizen.SozoCorrelationsRequest
+	if err := decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.TrimSpace(req.JobID) == "" && req.Records == nil:
+		return nil, fmt.Errorf("jobId or records is required")
+	case req.JobID != "" && req.Records != nil:
+		return nil, fmt.Errorf("pass either jobId or records, not both")
+	case req.Records != nil && req.Correlations == nil:
+		return nil, fmt.Errorf("correlations is required with inline records")
+	}
+	return fieldsOf(s.kaizenClient(ctx).SozoCorrelations(ctx, req))
 }
 
 func (s *Server) LogStartup() {
-	s.logger.Info("starting mcp server", "name", serverName, "api_base_url", s.client.baseURL)
+	s.logger.Info("starting mcp server", "name", serverName, "api_base_url", s.client.baseURL, "profiles", s.profileNames(), "default_profile", s.defaultProfile, "read_only", s.readOnly)
+	if !s.passthroughAuth && s.needsConfiguration(context.Background()) {
+		s.logger.Warn("KAIZEN_API_KEY is not set; starting in degraded mode, where only offline tools and kaizen.status work")
+	}
 }
 
 func (s *Server) LogFatal(err error) {
 	s.logger.Error("mcp server stopped with error", "error", err)
 }
+
+// Shutdown exports the trace spans and log records still pending and
+// stops both exporters. Call it once, after the last log line, whichever
+// transport served: records logged later are not exported.
+func (s *Server) Shutdown() {
+	// Spans first: a failed span export is itself logged.
+	s.tracer.shutdown()
+	s.logExporter.shutdown()
+}
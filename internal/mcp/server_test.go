@@ -8,7 +8,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
 )
 
 func TestToolDefinitionsIncludesAkumaSchema(t *testing.T) {
@@ -100,7 +104,7 @@ func TestHandleToolCallUnknownTool(t *testing.T) {
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if result != nil {
 		t.Fatalf("expected nil result, got %#v", result)
 	}
@@ -124,7 +128,7 @@ func TestHandleToolCallMissingPromptReturnsToolError(t *testing.T) {
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -182,7 +186,7 @@ func TestHandleToolCallAkumaQueryInteractiveDispatchesToInteractiveEndpoint(t *t
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -233,7 +237,7 @@ func TestHandleToolCallAkumaQueryInteractiveRejectedEnvelope(t *testing.T) {
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -289,7 +293,7 @@ func TestHandleToolCallAkumaQueryInteractiveRejectsRejectedEnvelopeWithoutError(
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -331,7 +335,7 @@ func TestHandleToolCallAkumaQueryInteractiveRejectsCompletedEnvelopeWithError(t
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -373,7 +377,7 @@ func TestHandleToolCallAkumaQueryInteractiveMalformedEnvelopeReturnsToolError(t
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -415,7 +419,7 @@ func TestHandleToolCallAkumaQueryInteractiveRejectsNullResult(t *testing.T) {
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -457,7 +461,7 @@ func TestHandleToolCallAkumaQueryInteractiveAllowsFutureStatusWithoutResult(t *t
 		t.Fatalf("marshal params: %v", err)
 	}
 
-	result, rpcErr := s.handleToolCall(raw)
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("expected no rpc error, got %+v", rpcErr)
 	}
@@ -517,7 +521,7 @@ func TestHandleToolCallAkumaQueryInteractiveNonOKReturnsToolError(t *testing.T)
 				t.Fatalf("marshal params: %v", err)
 			}
 
-			result, rpcErr := s.handleToolCall(raw)
+			result, rpcErr := s.handleToolCall(context.Background(), raw)
 			if rpcErr != nil {
 				t.Fatalf("expected no rpc error, got %+v", rpcErr)
 			}
@@ -599,7 +603,7 @@ func TestHandleToolCallEnzanPricingRefreshTriggerHitsCorrectRoute(t *testing.T)
 	defer cleanup()
 
 	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.pricing_refresh_trigger", Arguments: map[string]interface{}{}})
-	if _, rpcErr := s.handleToolCall(raw); rpcErr != nil {
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
 	if len(captured) != 1 || captured[0].Method != http.MethodPost || captured[0].Path != "/v1/enzan/pricing/refresh" {
@@ -628,7 +632,7 @@ func TestHandleToolCallEnzanPricingRefreshLogForwardsLimitVerbatim(t *testing.T)
 			defer cleanup()
 
 			raw, _ := json.Marshal(toolsCallParams{Name: "enzan.pricing_refresh_log", Arguments: tc.args})
-			if _, rpcErr := s.handleToolCall(raw); rpcErr != nil {
+			if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
 				t.Fatalf("rpc error: %+v", rpcErr)
 			}
 			if len(captured) != 1 {
@@ -649,7 +653,7 @@ func TestHandleToolCallEnzanPricingProvidersHitsCorrectRoute(t *testing.T) {
 	defer cleanup()
 
 	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.pricing_providers", Arguments: map[string]interface{}{}})
-	if _, rpcErr := s.handleToolCall(raw); rpcErr != nil {
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
 	if len(captured) != 1 || captured[0].Method != http.MethodGet || captured[0].Path != "/v1/enzan/pricing/providers" {
@@ -671,7 +675,7 @@ func TestHandleToolCallEnzanPricingRefreshTriggerPreserves429DroppedBody(t *test
 
 	srv := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}}
 	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.pricing_refresh_trigger", Arguments: map[string]interface{}{}})
-	result, rpcErr := srv.handleToolCall(raw)
+	result, rpcErr := srv.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
@@ -707,7 +711,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertPreserves409StaleBody(t *testing.
 			"gpu": map[string]interface{}{"provider": "p", "gpuType": "g", "displayName": "d", "hourlyRateUSD": 1.0},
 		},
 	})
-	result, rpcErr := srv.handleToolCall(raw)
+	result, rpcErr := srv.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
@@ -750,7 +754,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertRejectsNullOrNonObjectBranches(t
 		t.Run(tc.name, func(t *testing.T) {
 			before := len(captured)
 			raw, _ := json.Marshal(toolsCallParams{Name: "enzan.pricing_offers_upsert", Arguments: tc.args})
-			result, rpcErr := srv.handleToolCall(raw)
+			result, rpcErr := srv.handleToolCall(context.Background(), raw)
 			if rpcErr != nil {
 				t.Fatalf("rpc error: %+v", rpcErr)
 			}
@@ -780,7 +784,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertLLMBranchHitsServer(t *testing.T)
 			"llm": map[string]interface{}{"provider": "p", "model": "m", "displayName": "d", "inputCostPer1KTokensUSD": 0.001, "outputCostPer1KTokensUSD": 0.002},
 		},
 	})
-	if _, rpcErr := srv.handleToolCall(raw); rpcErr != nil {
+	if _, rpcErr := srv.handleToolCall(context.Background(), raw); rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
 	if len(captured) != 1 || captured[0].Path != "/v1/enzan/pricing/offers" {
@@ -791,7 +795,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertLLMBranchHitsServer(t *testing.T)
 	}
 }
 
-func TestCallPreservingTypedBodyPassesThroughNonAPICallErrors(t *testing.T) {
+func TestPreserveTypedBodyPassesThroughNonAPICallErrors(t *testing.T) {
 	// Network/transport errors (no apiCallError, just a wrapped fmt.Errorf
 	// from the http client) must bubble up unchanged — only typed
 	// non-2xx bodies in preserveStatuses get rewritten to typedBodyError.
@@ -806,7 +810,7 @@ func TestCallPreservingTypedBodyPassesThroughNonAPICallErrors(t *testing.T) {
 	defer hs.Close()
 
 	srv := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}}
-	_, err := srv.callPreservingTypedBody(context.Background(), "POST", "/v1/enzan/pricing/refresh", nil, []int{http.StatusTooManyRequests})
+	_, err := srv.callEnzanPricingRefreshTrigger(context.Background(), nil)
 	if err == nil {
 		t.Fatalf("expected transport error, got nil")
 	}
@@ -829,7 +833,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertEnforcesExactlyOne(t *testing.T)
 			"llm": map[string]interface{}{"provider": "p", "model": "m", "displayName": "d", "inputCostPer1KTokensUSD": 0.0, "outputCostPer1KTokensUSD": 0.0},
 		},
 	})
-	result, rpcErr := sBoth.handleToolCall(raw)
+	result, rpcErr := sBoth.handleToolCall(context.Background(), raw)
 	if rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
@@ -850,7 +854,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertEnforcesExactlyOne(t *testing.T)
 		Name:      "enzan.pricing_offers_upsert",
 		Arguments: map[string]interface{}{},
 	})
-	resultNone, rpcErrNone := sBoth.handleToolCall(rawNone)
+	resultNone, rpcErrNone := sBoth.handleToolCall(context.Background(), rawNone)
 	if rpcErrNone != nil {
 		t.Fatalf("rpc error: %+v", rpcErrNone)
 	}
@@ -871,7 +875,7 @@ func TestHandleToolCallEnzanPricingOffersUpsertEnforcesExactlyOne(t *testing.T)
 			"gpu": map[string]interface{}{"provider": "p", "gpuType": "g", "displayName": "d", "hourlyRateUSD": 1.0},
 		},
 	})
-	if _, rpcErr := sGPU.handleToolCall(rawGPU); rpcErr != nil {
+	if _, rpcErr := sGPU.handleToolCall(context.Background(), rawGPU); rpcErr != nil {
 		t.Fatalf("rpc error: %+v", rpcErr)
 	}
 	if len(capturedGPU) != 1 || capturedGPU[0].Path != "/v1/enzan/pricing/offers" {
@@ -881,3 +885,1061 @@ func TestHandleToolCallEnzanPricingOffersUpsertEnforcesExactlyOne(t *testing.T)
 		t.Fatalf("expected request body to contain gpu but not llm, got %s", capturedGPU[0].Body)
 	}
 }
+
+func TestHandleToolCallAppliesPerToolTimeout(t *testing.T) {
+	release := make(chan struct{})
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+	defer close(release)
+
+	srv := &Server{
+		client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()},
+		config: &serverConfig{ToolTimeouts: map[string]configDuration{
+			"enzan.burn": configDuration(50 * time.Millisecond),
+		}},
+	}
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{}})
+	result, rpcErr := srv.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	resp, _ := result.(map[string]interface{})
+	if resp["isError"] != true {
+		t.Fatalf("expected per-tool timeout to fail the call, got %+v", resp)
+	}
+	content, _ := resp["content"].([]map[string]string)
+	if len(content) != 1 || !strings.Contains(content[0]["text"], "deadline exceeded") {
+		t.Fatalf("expected deadline error text, got %#v", resp["content"])
+	}
+}
+
+func TestServerToolTimeoutFallsBackToClientTimeout(t *testing.T) {
+	srv := &Server{
+		client: &kaizenAPIClient{timeout: 90 * time.Second},
+		config: &serverConfig{ToolTimeouts: map[string]configDuration{
+			"sozo.generate": configDuration(300 * time.Second),
+		}},
+	}
+	if got := srv.toolTimeout("sozo.generate"); got != 300*time.Second {
+		t.Fatalf("sozo.generate timeout = %v", got)
+	}
+	if got := srv.toolTimeout("enzan.burn"); got != 90*time.Second {
+		t.Fatalf("enzan.burn timeout = %v", got)
+	}
+	if got := (&Server{}).toolTimeout("enzan.burn"); got != defaultAPITimeout {
+		t.Fatalf("zero server timeout = %v", got)
+	}
+}
+
+func TestHandleToolCallSendsIdempotencyKeyOnPosts(t *testing.T) {
+	var keys []string
+	var getKey string
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			getKey = r.Header.Get("Idempotency-Key")
+		} else {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer hs.Close()
+
+	srv := &Server{client: &kaizenAPIClient{baseURL: hs.URL, apiKey: "k", httpClient: hs.Client()}}
+	for i := 0; i < 2; i++ {
+		raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 10.0, "schemaName": "users"}})
+		if _, rpcErr := srv.handleToolCall(context.Background(), raw); rpcErr != nil {
+			t.Fatalf("rpc error: %+v", rpcErr)
+		}
+	}
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{}})
+	if _, rpcErr := srv.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+
+	if len(keys) != 2 || len(keys[0]) != 32 || keys[0] == keys[1] {
+		t.Fatalf("expected a distinct 32-char key per tool call, got %q", keys)
+	}
+	if getKey != "" {
+		t.Fatalf("GET requests should not carry an idempotency key, got %q", getKey)
+	}
+}
+
+func TestHandleToolCallAkumaValidatePostsSQL(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/validate": `{"valid":false,"diagnostics":[{"severity":"error","code":"unknown_column","message":"column \"emial\" does not exist","line":1,"column":8}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.validate", Arguments: map[string]interface{}{"sql": "SELECT emial FROM users", "dialect": "postgres"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Method != http.MethodPost || captured[0].Path != "/v1/akuma/validate" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if captured[0].Body != `{"sql":"SELECT emial FROM users","dialect":"postgres"}` {
+		t.Fatalf("unexpected payload: %s", captured[0].Body)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if data["valid"] != false || len(data["diagnostics"].([]interface{})) != 1 {
+		t.Fatalf("expected diagnostics passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallAkumaOptimizeReturnsRewrite(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/optimize": `{"sql":"SELECT id FROM orders WHERE created_at >= '2024-01-01'","rewrites":[{"rule":"sargable_predicate"}],"estimatedImprovement":{"costReductionPct":62}}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.optimize", Arguments: map[string]interface{}{"sql": "SELECT id FROM orders WHERE YEAR(created_at) >= 2024", "sourceId": "wh"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/optimize" || !strings.Contains(captured[0].Body, `"sourceId":"wh"`) {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if _, ok := data["rewrites"].([]interface{}); !ok {
+		t.Fatalf("expected rewrites passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallAkumaLintReturnsFindings(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/lint": `{"findings":[{"rule":"select_star","severity":"warning","line":1}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.lint", Arguments: map[string]interface{}{"sql": "SELECT * FROM events"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/lint" || captured[0].Body != `{"sql":"SELECT * FROM events"}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	findings := result.(map[string]interface{})["structuredContent"].(map[string]interface{})["findings"].([]interface{})
+	if findings[0].(map[string]interface{})["severity"] != "warning" {
+		t.Fatalf("expected findings passthrough, got %#v", findings)
+	}
+}
+
+func TestHandleToolCallAkumaHistoryForwardsFilters(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/akuma/history": `{"items":[{"id":"q1","sql":"SELECT 1","status":"completed"}],"nextCursor":"c2"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.history", Arguments: map[string]interface{}{
+		"status": "completed", "since": "2024-05-01T00:00:00Z", "limit": 20.0,
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Method != http.MethodGet || captured[0].Path != "/v1/akuma/history" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if captured[0].Query != "limit=20&since=2024-05-01T00%3A00%3A00Z&status=completed" {
+		t.Fatalf("unexpected query: %s", captured[0].Query)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "akuma.history", Arguments: map[string]interface{}{"since": "yesterday"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	if !strings.Contains(text, "since must be an RFC 3339 timestamp") || len(captured) != 1 {
+		t.Fatalf("expected local timestamp validation, got %q", text)
+	}
+}
+
+func TestHandleToolCallAkumaDiffPostsBothStatements(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/diff": `{"filters":{"added":["status = 'paid'"]},"joins":{},"projections":{}}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.diff", Arguments: map[string]interface{}{
+		"before": "SELECT id FROM orders",
+		"after":  "SELECT id FROM orders WHERE status = 'paid'",
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/diff" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	var body kaizen.AkumaDiffRequest
+	if err := json.Unmarshal([]byte(captured[0].Body), &body); err != nil || body.Before != "SELECT id FROM orders" || !strings.Contains(body.After, "WHERE") {
+		t.Fatalf("unexpected payload: %s", captured[0].Body)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "akuma.diff", Arguments: map[string]interface{}{"before": " ", "after": "SELECT 1"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	if result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected blank before to be rejected locally")
+	}
+}
+
+func TestHandleToolCallAkumaPreviewForwardsSampleRequest(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/preview": `{"columns":["id","email"],"rows":[[1,"***"]],"masked":["email"],"rowCap":20}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.preview", Arguments: map[string]interface{}{
+		"table": "public.users", "columns": []interface{}{"id", "email"}, "limit": 5.0,
+	}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/preview" || captured[0].Body != `{"table":"public.users","columns":["id","email"],"limit":5}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if data["masked"].([]interface{})[0] != "email" {
+		t.Fatalf("expected masking metadata passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallAkumaConvertTranslatesDialects(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/convert": `{"sql":"SELECT DATE_TRUNC(created_at, MONTH) FROM orders","notes":[]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.convert", Arguments: map[string]interface{}{
+		"sql": "SELECT date_trunc('month', created_at) FROM orders", "from": "postgres", "to": "bigquery",
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/convert" || !strings.Contains(captured[0].Body, `"from":"postgres","to":"bigquery"`) {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "akuma.convert", Arguments: map[string]interface{}{"sql": "SELECT 1", "from": "mysql", "to": "mysql"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]
+	if !strings.Contains(text, "must be different") || len(captured) != 1 {
+		t.Fatalf("expected same-dialect conversion to be rejected locally, got %q", text)
+	}
+}
+
+func TestHandleToolCallAkumaCostReturnsEstimate(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/cost": `{"bytesScanned":1099511627776,"estimatedCostUSD":6.25,"method":"dry_run"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.cost", Arguments: map[string]interface{}{"sql": "SELECT * FROM events", "dialect": "bigquery"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/cost" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["estimatedCostUSD"] != 6.25 {
+		t.Fatalf("expected estimate passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallAkumaSchemaGetReadsActiveContext(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/akuma/schema": `{"sourceId":"wh","version":"v7","dialect":"snowflake","tables":[{"name":"orders"}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.schema.get", Arguments: map[string]interface{}{"sourceId": "wh"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Method != http.MethodGet || captured[0].Path != "/v1/akuma/schema" || captured[0].Query != "sourceId=wh" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["version"] != "v7" {
+		t.Fatalf("expected schema context passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallAkumaSchemaIntrospectUsesNamedConnection(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/schema/introspect": `{"sourceId":"wh","version":"v8","tables":[{"name":"orders"}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.schema.introspect", Arguments: map[string]interface{}{
+		"connection": "warehouse-prod", "sourceId": "wh", "schemas": []interface{}{"public"},
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/akuma/schema/introspect" || captured[0].Body != `{"connection":"warehouse-prod","sourceId":"wh","schemas":["public"]}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	for _, connection := range []string{"postgres://admin:secret@db:5432/prod", "host=db password=secret"} {
+		raw, _ = json.Marshal(toolsCallParams{Name: "akuma.schema.introspect", Arguments: map[string]interface{}{"connection": connection}})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		if result.(map[string]interface{})["isError"] != true {
+			t.Fatalf("expected %q to be rejected", connection)
+		}
+	}
+	if len(captured) != 1 {
+		t.Fatalf("credentials must never reach the backend, got %+v", captured)
+	}
+}
+
+func TestHandleToolCallEnzanForecastDefaultsHorizon(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/enzan/forecast": `{"horizon":"30d","projectedUSD":41200,"series":[{"date":"2024-06-01","expectedUSD":1370,"lowerUSD":1200,"upperUSD":1550}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.forecast", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/enzan/forecast" || captured[0].Body != `{"horizon":"30d"}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["projectedUSD"] != 41200.0 {
+		t.Fatalf("expected forecast passthrough, got %#v", data)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.forecast", Arguments: map[string]interface{}{"horizon": "1y"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected unsupported horizon to be rejected locally")
+	}
+}
+
+func TestHandleToolCallEnzanBudgetsListAndSet(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/budgets":  `{"budgets":[{"id":"b1","amountUSD":5000,"period":"monthly"}]}`,
+		"POST /v1/enzan/budgets": `{"id":"b2"}`,
+	})
+	defer cleanup()
+
+	calls := []toolsCallParams{
+		{Name: "enzan.budgets", Arguments: map[string]interface{}{}},
+		{Name: "enzan.set_budget", Arguments: map[string]interface{}{"amountUSD": 12000.0, "period": "quarterly", "scope": map[string]interface{}{"team": "ml"}}},
+		{Name: "enzan.set_budget", Arguments: map[string]interface{}{"id": "b1", "amountUSD": 6000.0}},
+	}
+	for _, call := range calls {
+		raw, _ := json.Marshal(call)
+		if result, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+			t.Fatalf("%s failed: %+v %+v", call.Name, result, rpcErr)
+		}
+	}
+	want := []capturedRequest{
+		{Method: http.MethodGet, Path: "/v1/enzan/budgets"},
+		{Method: http.MethodPost, Path: "/v1/enzan/budgets", Body: `{"amountUSD":12000,"period":"quarterly","scope":{"team":"ml"}}`},
+		{Method: http.MethodPatch, Path: "/v1/enzan/budgets/b1", Body: `{"amountUSD":6000}`},
+	}
+	if len(captured) != len(want) {
+		t.Fatalf("unexpected captured requests: %+v", captured)
+	}
+	for i := range want {
+		if captured[i] != want[i] {
+			t.Fatalf("request %d = %+v, want %+v", i, captured[i], want[i])
+		}
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.set_budget", Arguments: map[string]interface{}{"amountUSD": 1.0}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	if text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]; !strings.Contains(text, "period is required") {
+		t.Fatalf("expected create without period to be rejected, got %q", text)
+	}
+
+	s.SetReadOnly(true)
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.set_budget", Arguments: map[string]interface{}{"id": "b1", "amountUSD": 1.0}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 3 {
+		t.Fatalf("expected set_budget to be refused in read-only mode")
+	}
+}
+
+func TestHandleToolCallEnzanAlertEventsFiltersByStatusAndWindow(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/alerts/events": `{"events":[{"id":"e1","status":"active","alertId":"a1","resources":[{"cluster":"gpu-east"}]}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.alert_events", Arguments: map[string]interface{}{"status": "active", "window": "24h", "limit": 10.0}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.alert_events", Arguments: map[string]interface{}{}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 2 || captured[0].Query != "limit=10&status=active&window=24h" || captured[1].Query != "" {
+		t.Fatalf("unexpected captured requests: %+v", captured)
+	}
+}
+
+func TestHandleToolCallEnzanAnomaliesForwardsFilters(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/anomalies": `{"anomalies":[{"project":"vision","cluster":"gpu-east","expectedUSD":120,"actualUSD":480,"deviationPct":300}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.anomalies", Arguments: map[string]interface{}{"window": "7d", "minDeviationPct": 50.0, "limit": 5.0}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "limit=5&minDeviationPct=50&window=7d" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if anomalies, _ := data["anomalies"].([]interface{}); len(anomalies) != 1 {
+		t.Fatalf("expected anomalies passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallEnzanBreakdownByLabel(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/breakdown": `{"dimension":"label","groups":[{"key":"ml-research","costUSD":920.5}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.breakdown", Arguments: map[string]interface{}{"dimension": "label"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 0 {
+		t.Fatalf("expected missing labelKey to be rejected locally")
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.breakdown", Arguments: map[string]interface{}{"dimension": "label", "labelKey": "team", "window": "30d", "sortBy": "cost", "order": "desc", "top": 3.0}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "dimension=label&labelKey=team&order=desc&sortBy=cost&top=3&window=30d" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+}
+
+func TestHandleToolCallEnzanUtilizationPerNode(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/utilization": `{"nodes":[{"node":"gpu-east-3","smOccupancyPct":41.2,"memoryUsedPct":63,"idlePct":38.5}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.utilization", Arguments: map[string]interface{}{"window": "24h", "groupBy": "node", "cluster": "gpu-east"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "cluster=gpu-east&groupBy=node&window=24h" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["nodes"] == nil {
+		t.Fatalf("expected utilization passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallEnzanRecommendFiltersByKind(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/recommendations": `{"recommendations":[{"kind":"spot","resource":"gpu-east-3","estimatedMonthlySavingsUSD":1840}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.recommend", Arguments: map[string]interface{}{"kind": "spot", "minSavingsUSD": 500.0}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "kind=spot&minSavingsUSD=500" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.recommend", Arguments: map[string]interface{}{"kind": "delete"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected unknown kind to be rejected locally")
+	}
+}
+
+func TestHandleToolCallEnzanCompareDefaultsToWeek(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/enzan/compare": `{"period":"week","groups":[{"key":"vision","previousUSD":400,"currentUSD":520,"deltaUSD":120,"deltaPct":30}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.compare", Arguments: map[string]interface{}{"groupBy": []interface{}{"project"}}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"period":"week","groupBy":["project"]}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["groups"] == nil {
+		t.Fatalf("expected comparison passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallEnzanIdleForwardsThresholds(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/idle": `{"gpus":[{"node":"gpu-east-3","gpu":2,"idleHours":71,"idleCostUSD":213}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.idle", Arguments: map[string]interface{}{"thresholdPct": 5.0, "minHours": 48.0}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "minHours=48&thresholdPct=5" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "enzan.idle", Arguments: map[string]interface{}{"thresholdPct": 150.0}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected out-of-range threshold to be rejected locally")
+	}
+}
+
+func TestHandleToolCallEnzanAllocationByCostCenter(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/allocation": `{"costCenters":[{"name":"CC-1042","costUSD":8120.4}],"unallocatedUSD":312.9}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.allocation", Arguments: map[string]interface{}{"window": "30d", "mode": "chargeback"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "mode=chargeback&window=30d" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["unallocatedUSD"] != 312.9 {
+		t.Fatalf("expected allocation passthrough, got %#v", data)
+	}
+}
+
+func TestHandleToolCallSozoValidateReturnsDiagnostics(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/validate": `{"valid":false,"diagnostics":[{"severity":"error","path":"fields.age.range","message":"min 90 is greater than max 18"}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.validate", Arguments: map[string]interface{}{
+		"schema": map[string]interface{}{"fields": map[string]interface{}{"age": map[string]interface{}{"type": "int", "range": []interface{}{90.0, 18.0}}}},
+	}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"schema":{"fields":{"age":{"range":[90,18],"type":"int"}}}}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["valid"] != false {
+		t.Fatalf("expected diagnostics passthrough, got %#v", data)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.validate", Arguments: map[string]interface{}{"schema": map[string]interface{}{}}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected empty schema to be rejected locally")
+	}
+}
+
+func TestHandleToolCallSozoPreviewDefaultsToFiveRecords(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/preview": `{"records":[{"id":1,"email":"a@example.com"}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.preview", Arguments: map[string]interface{}{"schemaName": "users"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"schemaName":"users","records":5}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"schemaName": "users", "records": 50.0},
+		{"records": 3.0},
+	} {
+		raw, _ = json.Marshal(toolsCallParams{Name: "sozo.preview", Arguments: args})
+		if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+	if len(captured) != 1 {
+		t.Fatalf("expected invalid previews to be rejected locally, got %+v", captured)
+	}
+}
+
+func TestHandleToolCallSozoAnonymizeSendsRecordsAndRules(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/anonymize": `{"records":[{"email":"x91f@example.net","amount":118.2}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.anonymize", Arguments: map[string]interface{}{
+		"records": []interface{}{map[string]interface{}{"email": "jane@corp.com", "amount": 120.0}},
+		"rules":   map[string]interface{}{"email": "mask", "amount": "keep"},
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"records":[{"amount":120,"email":"jane@corp.com"}],"rules":{"amount":"keep","email":"mask"}}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.anonymize", Arguments: map[string]interface{}{
+		"records": []interface{}{map[string]interface{}{"email": "jane@corp.com"}},
+		"rules":   map[string]interface{}{"email": "encrypt"},
+	}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected unknown masking strategy to be rejected locally")
+	}
+}
+
+func TestHandleToolCallSozoSchemasForwardsFilters(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/sozo/schemas": `{"schemas":[{"name":"payments","tags":["finance"],"fieldCount":12}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.schemas", Arguments: map[string]interface{}{}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.schemas", Arguments: map[string]interface{}{"name": "pay", "tag": "finance", "maxFields": 20.0}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 2 || captured[0].Query != "" || captured[1].Query != "maxFields=20&name=pay&tag=finance" {
+		t.Fatalf("unexpected captured requests: %+v", captured)
+	}
+}
+
+func TestHandleToolCallSozoSchemaSaveAndDelete(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/schemas":                   `{"name":"checkout-events","version":1}`,
+		"DELETE /v1/sozo/schemas/checkout-events": `{"deleted":true}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.schema.save", Arguments: map[string]interface{}{
+		"name":   "checkout-events",
+		"schema": map[string]interface{}{"fields": map[string]interface{}{"sku": map[string]interface{}{"type": "string"}}},
+		"tags":   []interface{}{"retail"},
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.schema.delete", Arguments: map[string]interface{}{"name": "checkout-events"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 2 ||
+		captured[0].Body != `{"name":"checkout-events","schema":{"fields":{"sku":{"type":"string"}}},"tags":["retail"]}` ||
+		captured[1].Method != http.MethodDelete || captured[1].Path != "/v1/sozo/schemas/checkout-events" {
+		t.Fatalf("unexpected captured requests: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.schema.delete", Arguments: map[string]interface{}{"name": "../akuma"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 2 {
+		t.Fatalf("expected invalid preset name to be rejected locally")
+	}
+
+	s.SetReadOnly(true)
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.schema.save", Arguments: map[string]interface{}{"name": "x", "schema": map[string]interface{}{"a": 1.0}}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 2 {
+		t.Fatalf("expected sozo.schema.save to be refused in read-only mode")
+	}
+}
+
+func TestHandleToolCallSozoJobsLifecycle(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/jobs":             `{"jobId":"job-7","status":"queued"}`,
+		"GET /v1/sozo/jobs/job-7":        `{"jobId":"job-7","status":"running","progress":40000}`,
+		"GET /v1/sozo/jobs/job-7/result": `{"jobId":"job-7","total":100000,"records":[{"id":1001}]}`,
+	})
+	defer cleanup()
+
+	for _, call := range []toolsCallParams{
+		{Name: "sozo.jobs.start", Arguments: map[string]interface{}{"records": 100000.0, "schemaName": "users"}},
+		{Name: "sozo.jobs.status", Arguments: map[string]interface{}{"jobId": "job-7"}},
+		{Name: "sozo.jobs.result", Arguments: map[string]interface{}{"jobId": "job-7", "offset": 1000.0, "limit": 500.0}},
+	} {
+		raw, _ := json.Marshal(call)
+		result, rpcErr := s.handleToolCall(context.Background(), raw)
+		if rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+			t.Fatalf("%s failed: %+v %#v", call.Name, rpcErr, result)
+		}
+	}
+	want := []capturedRequest{
+		{Method: http.MethodPost, Path: "/v1/sozo/jobs", Body: `{"records":100000,"schemaName":"users"}`},
+		{Method: http.MethodGet, Path: "/v1/sozo/jobs/job-7"},
+		{Method: http.MethodGet, Path: "/v1/sozo/jobs/job-7/result", Query: "limit=500&offset=1000"},
+	}
+	if len(captured) != len(want) {
+		t.Fatalf("unexpected captured requests: %+v", captured)
+	}
+	for i := range want {
+		if captured[i] != want[i] {
+			t.Fatalf("request %d: got %+v, want %+v", i, captured[i], want[i])
+		}
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.jobs.start", Arguments: map[string]interface{}{"records": 10.0}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 3 {
+		t.Fatalf("expected a job without schema or schemaName to be rejected locally")
+	}
+}
+
+func TestHandleToolCallSozoAugmentExpandsSeeds(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/augment": `{"records":[{"plan":"pro","seats":12},{"plan":"team","seats":4}],"count":2}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.augment", Arguments: map[string]interface{}{
+		"seedRecords": []interface{}{map[string]interface{}{"plan": "pro", "seats": 10.0}},
+		"records":     2.0,
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"seedRecords":[{"plan":"pro","seats":10}],"records":2}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.augment", Arguments: map[string]interface{}{"seedRecords": []interface{}{}, "records": 2.0}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected empty seedRecords to be rejected locally")
+	}
+}
+
+func TestHandleToolCallSozoDriftSendsShiftSpec(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/drift": `{"records":[{"amount":212.5}],"report":{"amount":{"kind":"mean_shift","baseMean":100,"shiftedMean":115}}}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.drift", Arguments: map[string]interface{}{
+		"schemaName": "payments",
+		"records":    1000.0,
+		"shiftPct":   15.0,
+		"fields":     []interface{}{"amount"},
+		"kind":       "mean_shift",
+	}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"schemaName":"payments","records":1000,"shiftPct":15,"fields":["amount"],"kind":"mean_shift"}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"schemaName": "payments", "records": 10.0, "shiftPct": 0.0},
+		{"records": 10.0, "shiftPct": 5.0},
+	} {
+		raw, _ = json.Marshal(toolsCallParams{Name: "sozo.drift", Arguments: args})
+		if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+	if len(captured) != 1 {
+		t.Fatalf("expected invalid drift specs to be rejected locally, got %+v", captured)
+	}
+}
+
+func TestHandleToolCallAkumaGlossaryLooksUpTerm(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/akuma/glossary": `{"terms":[{"term":"ARR","columns":["subscriptions.annual_amount"]}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.glossary", Arguments: map[string]interface{}{"term": "ARR", "sourceId": "warehouse"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "sourceId=warehouse&term=ARR" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("unexpected tool error: %#v", result)
+	}
+}
+
+func TestHandleToolCallAkumaFeedbackPostsCorrection(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/feedback": `{"id":"fb_1","recorded":true}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.feedback", Arguments: map[string]interface{}{"queryId": "q_1", "rating": "down", "correction": "SELECT 1"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"queryId":"q_1","rating":"down","correction":"SELECT 1"}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "akuma.feedback", Arguments: map[string]interface{}{"queryId": "q_1", "rating": "meh"}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(captured) != 1 {
+		t.Fatalf("expected an invalid rating to be rejected locally")
+	}
+}
+
+func TestHandleToolCallEnzanEventsFiltersByKind(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/events": `{"events":[{"time":"2024-06-03T02:14:09Z","kind":"oom","node":"gpu-east-7"}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.events", Arguments: map[string]interface{}{"window": "7d", "kind": "oom"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Query != "kind=oom&window=7d" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+}
+
+func TestHandleToolCallSozoCorrelationsRequiresOneSource(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/correlations": `{"pairs":[{"fields":["a","b"],"requested":0.6,"realized":0.58}]}`,
+	})
+	defer cleanup()
+
+	records := []interface{}{map[string]interface{}{"a": 1.0, "b": 2.0}, map[string]interface{}{"a": 2.0, "b": 4.0}}
+	for _, args := range []map[string]interface{}{
+		{},
+		{"jobId": "job_1", "records": records},
+		{"records": records},
+	} {
+		raw, _ := json.Marshal(toolsCallParams{Name: "sozo.correlations", Arguments: args})
+		if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true {
+			t.Fatalf("expected %v to be rejected locally, got %#v", args, result)
+		}
+	}
+	if len(captured) != 0 {
+		t.Fatalf("invalid calls reached the backend: %+v", captured)
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.correlations", Arguments: map[string]interface{}{"jobId": "job_1"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"jobId":"job_1"}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+}
+
+func TestHandleToolCallSozoSeedsReplayByName(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/seeds/qa fixtures/replay": `{"seed":42,"records":[{"id":1}]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.seeds.replay", Arguments: map[string]interface{}{"name": "qa fixtures"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Method != http.MethodPost || captured[0].Path != "/v1/sozo/seeds/qa fixtures/replay" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "sozo.jobs.start", Arguments: map[string]interface{}{"records": 500.0, "schemaName": "saas_customers", "seed": 42.0, "runName": "qa fixtures"}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 2 || !strings.Contains(captured[1].Body, `"runName":"qa fixtures"`) {
+		t.Fatalf("runName not forwarded: %+v", captured)
+	}
+}
+
+func TestAkumaQueryEnforcesMaxRows(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/query":               `{"sql":"SELECT n FROM t","columns":["n"],"rows":[[1],[2],[3]]}`,
+		"POST /v1/akuma/queries/interactive": `{"status":"completed","result":{"sql":"SELECT n FROM t","rows":[[1],[2],[3]]}}`,
+	})
+	defer cleanup()
+
+	for _, name := range []string{"akuma.query", "akuma.query_interactive"} {
+		raw, _ := json.Marshal(toolsCallParams{Name: name, Arguments: map[string]interface{}{"dialect": "postgres", "prompt": "n", "mode": "sql-and-results", "maxRows": 2.0}})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+		if nested, ok := structured["result"].(map[string]interface{}); ok {
+			structured = nested
+		}
+		if rows := structured["rows"].([]interface{}); len(rows) != 2 || structured["truncated"] != true || structured["originalRowCount"] != 3 {
+			t.Fatalf("%s: rows not capped: %#v", name, structured)
+		}
+
+		raw, _ = json.Marshal(toolsCallParams{Name: name, Arguments: map[string]interface{}{"dialect": "postgres", "prompt": "n", "mode": "sql-and-results", "maxRows": 5.0}})
+		result, _ = s.handleToolCall(context.Background(), raw)
+		structured = result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+		if _, ok := structured["truncated"]; ok {
+			t.Fatalf("%s: result within maxRows marked truncated: %#v", name, structured)
+		}
+	}
+}
+
+func TestHandleToolCallAkumaBatchFansOutPerPrompt(t *testing.T) {
+	var mu sync.Mutex
+	var prompts []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body kaizen.AkumaQueryRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		prompts = append(prompts, body.Prompt)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if body.Prompt == "broken" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"cannot parse prompt"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"sql":"SELECT '` + body.Prompt + `'","rows":[[1],[2],[3]]}`))
+	}))
+	defer backend.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client()}}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.batch", Arguments: map[string]interface{}{
+		"dialect": "postgres",
+		"prompts": []string{"signups", "broken", "revenue"},
+		"maxRows": 2,
+	}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil || result.(map[string]interface{})["isError"] == true {
+		t.Fatalf("unexpected error: %+v %#v", rpcErr, result)
+	}
+	structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if structured["succeeded"] != 2 || structured["failed"] != 1 {
+		t.Fatalf("unexpected counts: %#v", structured)
+	}
+	results := structured["results"].([]interface{})
+	for i, want := range []string{"signups", "broken", "revenue"} {
+		entry := results[i].(map[string]interface{})
+		if entry["prompt"] != want {
+			t.Fatalf("results[%d] = %#v, want prompt %s in order", i, entry, want)
+		}
+		if _, failed := entry["error"]; failed != (want == "broken") {
+			t.Fatalf("results[%d] = %#v", i, entry)
+		}
+	}
+	if first := results[0].(map[string]interface{})["result"].(map[string]interface{}); first["truncated"] != true {
+		t.Fatalf("maxRows should apply per prompt, got %#v", first)
+	}
+	if len(prompts) != 3 {
+		t.Fatalf("backend saw %v, want one query per prompt", prompts)
+	}
+}
+
+func TestSozoContentHashKeepsBackendEncoding(t *testing.T) {
+	// json.Marshal would escape & as \u0026, and float64 would round the
+	// integer, so both would hash differently from the backend.
+	records := `[{"big":12345678901234567891,"name":"A&B"}]`
+	hash, err := sozoContentHash(json.RawMessage(`[{"name":"A&B","big":12345678901234567891}]`))
+	if err != nil {
+		t.Fatalf("sozoContentHash: %v", err)
+	}
+	if hash != "sha256:"+sha256Hex([]byte(records)) {
+		t.Fatalf("hash %s is not over %s", hash, records)
+	}
+
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/generate": `{"records":[{"name":"A&B","big":12345678901234567891}],"contentHash":"` + hash + `"}`,
+	})
+	defer cleanup()
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"schemaName": "people", "records": 1, "seed": 7}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	determinism := result.(map[string]interface{})["structuredContent"].(map[string]interface{})["determinism"].(map[string]interface{})
+	if determinism["verified"] != true {
+		t.Fatalf("determinism = %#v, want verified", determinism)
+	}
+}
+
+func TestHandleToolCallSozoGenerateVerifiesSeededHash(t *testing.T) {
+	records := `[{"id":1,"name":"Ada"},{"id":2,"name":"Grace"}]`
+	hash, _ := sozoContentHash(json.RawMessage(`[ {"name": "Ada", "id": 1}, {"id": 2, "name": "Grace"} ]`))
+	if hash != "sha256:"+sha256Hex([]byte(records)) {
+		t.Fatalf("hash %s is not over compact sorted-key JSON", hash)
+	}
+
+	for _, tc := range []struct {
+		name, backendHash string
+		verified          interface{}
+	}{
+		{"matching hash", hash, true},
+		{"mismatched hash", "sha256:0000", false},
+		{"no backend hash", "", nil},
+	} {
+		var captured []capturedRequest
+		s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+			"POST /v1/sozo/generate": `{"records":` + records + `,"contentHash":"` + tc.backendHash + `"}`,
+		})
+		raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"schemaName": "people", "records": 2, "seed": 7}})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		cleanup()
+		determinism := result.(map[string]interface{})["structuredContent"].(map[string]interface{})["determinism"].(map[string]interface{})
+		if determinism["contentHash"] != hash || determinism["verified"] != tc.verified {
+			t.Fatalf("%s: determinism = %#v", tc.name, determinism)
+		}
+		if _, warned := determinism["warning"]; warned != (tc.verified == false) {
+			t.Fatalf("%s: warning = %#v", tc.name, determinism["warning"])
+		}
+	}
+
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/generate": `{"records":` + records + `}`,
+	})
+	defer cleanup()
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"schemaName": "people", "records": 2}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	if _, ok := result.(map[string]interface{})["structuredContent"].(map[string]interface{})["determinism"]; ok {
+		t.Fatalf("unseeded runs should not be hashed")
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// warnSlowToolCalls logs a warning for every tool call that takes longer
+// than KAIZEN_MCP_SLOW_CALL_THRESHOLD, so degradation shows up in the
+// logs without tracing.
+func (s *Server) warnSlowToolCalls(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if s.slowCallThreshold <= 0 {
+			return next(ctx, call)
+		}
+		started := time.Now()
+		result := next(ctx, call)
+		if elapsed := time.Since(started); elapsed > s.slowCallThreshold {
+			s.logger.Warn("slow tool call",
+				"tool", call.Name,
+				"request_id", requestIDFrom(ctx),
+				"elapsed_ms", elapsed.Milliseconds(),
+				"threshold_ms", s.slowCallThreshold.Milliseconds(),
+				"is_error", result["isError"] == true,
+			)
+		}
+		return result
+	}
+}
+
+// warnSlowRequests is the client middleware counterpart of
+// warnSlowToolCalls for single Kaizen API requests, so a slow tool call
+// can be told apart from a slow backend.
+func warnSlowRequests(logger *slog.Logger, threshold time.Duration) ClientMiddleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			started := time.Now()
+			resp, err := next(req)
+			if elapsed := time.Since(started); elapsed > threshold {
+				attrs := []interface{}{
+					"method", req.Method,
+					"path", req.URL.Path,
+					"request_id", req.Header.Get(requestIDHeader),
+					"elapsed_ms", elapsed.Milliseconds(),
+					"threshold_ms", threshold.Milliseconds(),
+				}
+				if err != nil {
+					attrs = append(attrs, "error", err.Error())
+				} else {
+					attrs = append(attrs, "status", resp.StatusCode)
+				}
+				logger.Warn("slow kaizen api request", attrs...)
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowCallsAreLogged(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/enzan/burn" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backend.Close()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	s := &Server{
+		logger:            logger,
+		slowCallThreshold: 20 * time.Millisecond,
+		client:            &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client()},
+	}
+	s.UseClientMiddleware(warnSlowRequests(logger, s.slowCallThreshold))
+
+	for _, name := range []string{"enzan.burn", "enzan.budgets"} {
+		raw, _ := json.Marshal(toolsCallParams{Name: name, Arguments: map[string]interface{}{}})
+		s.handleToolCall(context.Background(), raw)
+	}
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q", line)
+		}
+		if entry["level"] != "WARN" {
+			continue
+		}
+		messages = append(messages, entry["msg"].(string))
+		if entry["elapsed_ms"].(float64) < 20 || entry["threshold_ms"] != 20.0 || entry["request_id"] == "" {
+			t.Fatalf("unexpected slow-call log: %v", entry)
+		}
+		if entry["msg"] == "slow tool call" && entry["tool"] != "enzan.burn" {
+			t.Fatalf("fast tool logged as slow: %v", entry)
+		}
+		if entry["msg"] == "slow kaizen api request" && (entry["path"] != "/v1/enzan/burn" || entry["status"] != 200.0) {
+			t.Fatalf("unexpected request log: %v", entry)
+		}
+	}
+	if got := strings.Join(messages, ","); got != "slow kaizen api request,slow tool call" {
+		t.Fatalf("logged %q, want the slow request and its tool call only", got)
+	}
+}
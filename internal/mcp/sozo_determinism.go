@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// sozoContentHash is the SHA-256 of the generated records encoded as
+// compact JSON with object keys sorted, which is how the backend computes
+// the contentHash of a seeded run. records is re-encoded from the
+// backend's bytes with numbers kept as written and without HTML escaping,
+// so large integers and characters such as & hash as the backend sent them.
+func sozoContentHash(records json.RawMessage) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(records))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("failed to hash generated records: %w", err)
+	}
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to hash generated records: %w", err)
+	}
+	return "sha256:" + sha256Hex(bytes.TrimSuffix(encoded.Bytes(), []byte("\n"))), nil
+}
+
+// verifySeededGeneration adds the server's content hash of a seeded run's
+// records and checks it against the backend's contentHash. The same seed
+// must always produce the same records, so a mismatch means the dataset
+// is not reproducible; it is flagged in the result and logged rather than
+// failing the call, since the records themselves are still usable. body
+// is the backend's response, which data was decoded from.
+func (s *Server) verifySeededGeneration(data map[string]interface{}, body []byte) (map[string]interface{}, error) {
+	var envelope struct {
+		Records json.RawMessage `json:"records"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Records == nil {
+		return data, nil
+	}
+	hash, err := sozoContentHash(envelope.Records)
+	if err != nil {
+		return nil, err
+	}
+	verification := map[string]interface{}{"contentHash": hash}
+	if backendHash, ok := data["contentHash"].(string); ok && backendHash != "" {
+		verified := backendHash == hash
+		verification["backendHash"] = backendHash
+		verification["verified"] = verified
+		if !verified {
+			verification["warning"] = "the records do not match the backend's hash for this seed; the run may not be reproducible"
+			if s.logger != nil {
+				s.logger.Warn("seeded sozo generation is nondeterministic", "content_hash", hash, "backend_hash", backendHash)
+			}
+		}
+	}
+	data["determinism"] = verification
+	return data, nil
+}
@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var sozoFileFormats = []string{"csv", "jsonl", "parquet"}
+
+// sozoFileMimeTypes labels exported files in their resource links.
+var sozoFileMimeTypes = map[string]string{
+	"csv":     "text/csv",
+	"jsonl":   "application/jsonl",
+	"parquet": "application/vnd.apache.parquet",
+}
+
+// exportSozoGenerate has the backend render the dataset in a file format.
+// CSV and JSON Lines can come back inline; with outputFile the file is
+// written under KAIZEN_SOZO_OUTPUT_DIR and returned as a resource link,
+// so thousands of rows never pass through the model context.
+func (s *Server) exportSozoGenerate(ctx context.Context, payload map[string]interface{}, format, outputFile string) (map[string]interface{}, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(outputFile), ".")
+		if !isSozoFileFormat(format) {
+			return nil, fmt.Errorf("format is required when outputFile has no .csv, .jsonl, or .parquet extension")
+		}
+	}
+
+	var target string
+	if outputFile == "" {
+		if format == "parquet" {
+			return nil, fmt.Errorf("parquet output requires outputFile")
+		}
+	} else {
+		if s.sozoOutputDir == "" {
+			return nil, fmt.Errorf("outputFile requires KAIZEN_SOZO_OUTPUT_DIR to be set")
+		}
+		if !filepath.IsLocal(outputFile) {
+			return nil, fmt.Errorf("outputFile must be a relative path inside the output directory")
+		}
+		var err error
+		if target, err = resolveOutputPath(s.sozoOutputDir, outputFile); err != nil {
+			return nil, err
+		}
+	}
+
+	payload["format"] = format
+	body, err := s.fetch(ctx, http.MethodPost, "/v1/sozo/generate", payload)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		return map[string]interface{}{"format": format, "content": string(body)}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// O_EXCL refuses to overwrite an existing file or follow a symlink
+	// planted at the target.
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	if _, err := file.Write(body); err != nil {
+		file.Close()
+		os.Remove(target)
+		return nil, fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write output file: %w", err)
+	}
+	attachResourceLink(ctx, resourceLink{
+		URI:         (&url.URL{Scheme: "file", Path: filepath.ToSlash(target)}).String(),
+		Name:        outputFile,
+		MimeType:    sozoFileMimeTypes[format],
+		Description: fmt.Sprintf("%d bytes of generated %s", len(body), format),
+	})
+	return map[string]interface{}{
+		"format":     format,
+		"outputFile": outputFile,
+		"bytes":      len(body),
+	}, nil
+}
+
+// resolveOutputPath joins a local outputFile onto dir after resolving
+// symlinks in both, and refuses a path that a symlink inside dir leads
+// out of it. Directories that do not exist yet are resolved from their
+// deepest existing ancestor.
+func resolveOutputPath(dir, outputFile string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+	existing, missing := filepath.Join(root, filepath.Dir(outputFile)), []string{filepath.Base(outputFile)}
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		missing = append([]string{filepath.Base(existing)}, missing...)
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve outputFile: %w", err)
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("outputFile must be a relative path inside the output directory")
+	}
+	return filepath.Join(append([]string{resolved}, missing...)...), nil
+}
+
+func isSozoFileFormat(format string) bool {
+	for _, known := range sozoFileFormats {
+		if format == known {
+			return true
+		}
+	}
+	return false
+}
+
+// renderSozoGenerate returns inline CSV/JSON Lines verbatim, a one-line
+// summary ahead of the resource link for files written to disk, and chunked JSON Lines for streamed
+// generations.
+func renderSozoGenerate(args, data map[string]interface{}) ([]string, bool) {
+	if outputFile, _ := data["outputFile"].(string); outputFile != "" {
+		return []string{fmt.Sprintf("Wrote %v bytes of %v to %s in the output directory.", data["bytes"], data["format"], outputFile)}, true
+	}
+	if _, exported := args["format"]; exported {
+		if content, ok := data["content"].(string); ok {
+			return []string{content}, true
+		}
+	}
+	return renderSozoRecords(args, data)
+}
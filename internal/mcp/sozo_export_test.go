@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSozoGenerateReturnsInlineCSV(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/generate": "id,name\n1,Ada\n2,Grace\n",
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 2.0, "schemaName": "users", "format": "csv"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Body != `{"format":"csv","records":2,"schemaName":"users"}` {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if text := result.(map[string]interface{})["content"].([]map[string]string)[0]["text"]; text != "id,name\n1,Ada\n2,Grace\n" {
+		t.Fatalf("expected raw CSV text, got %q", text)
+	}
+}
+
+func TestSozoGenerateWritesOutputFile(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/generate": "PAR1-binary-data",
+	})
+	defer cleanup()
+	s.sozoOutputDir = t.TempDir()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 5000.0, "schemaName": "users", "outputFile": "exports/users.parquet"}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || !strings.Contains(captured[0].Body, `"format":"parquet"`) {
+		t.Fatalf("expected the format to be inferred from the extension, got %+v", captured)
+	}
+	want := filepath.Join(s.sozoOutputDir, "exports", "users.parquet")
+	written, err := os.ReadFile(want)
+	if err != nil || string(written) != "PAR1-binary-data" {
+		t.Fatalf("expected file at %s, got %q (%v)", want, written, err)
+	}
+	response := result.(map[string]interface{})
+	data := response["structuredContent"].(map[string]interface{})
+	if _, leaked := data["path"]; leaked || data["outputFile"] != "exports/users.parquet" || data["bytes"] != 16 {
+		t.Fatalf("unexpected export result: %#v", data)
+	}
+	content := response["content"].([]map[string]string)
+	resolved, _ := filepath.EvalSymlinks(want)
+	link := content[len(content)-1]
+	if link["type"] != "resource_link" || link["uri"] != "file://"+filepath.ToSlash(resolved) || link["mimeType"] != "application/vnd.apache.parquet" {
+		t.Fatalf("expected a resource link to the file, got %#v", content)
+	}
+	if info, err := os.Stat(want); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("output file should be private: %v %v", info, err)
+	}
+
+	// An existing file is never overwritten.
+	captured = nil
+	result, _ = s.handleToolCall(context.Background(), raw)
+	if result.(map[string]interface{})["isError"] != true {
+		t.Fatalf("expected an existing output file to be refused, got %#v", result)
+	}
+}
+
+func TestSozoGenerateRefusesSymlinksOutOfTheOutputDir(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/sozo/generate": "id,name\n",
+	})
+	defer cleanup()
+	s.sozoOutputDir = t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(s.sozoOutputDir, "escape")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 1.0, "schemaName": "users", "outputFile": "escape/nested/users.csv"}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	response := result.(map[string]interface{})
+	if text := response["content"].([]map[string]string)[0]["text"]; response["isError"] != true || !strings.Contains(text, "inside the output directory") {
+		t.Fatalf("expected the symlink to be refused, got %#v", response)
+	}
+	if entries, _ := os.ReadDir(outside); len(captured) != 0 || len(entries) != 0 {
+		t.Fatalf("nothing should be requested or written outside: %+v %v", captured, entries)
+	}
+}
+
+func TestSozoGenerateRejectsUnsafeExports(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+
+	cases := []struct {
+		dir  string
+		args map[string]interface{}
+		want string
+	}{
+		{"", map[string]interface{}{"outputFile": "users.csv"}, "KAIZEN_SOZO_OUTPUT_DIR"},
+		{t.TempDir(), map[string]interface{}{"outputFile": "../users.csv"}, "inside the output directory"},
+		{t.TempDir(), map[string]interface{}{"outputFile": "users.txt"}, "format is required"},
+		{"", map[string]interface{}{"format": "parquet"}, "parquet output requires outputFile"},
+		{"", map[string]interface{}{"format": "csv", "stream": true}, "stream cannot be combined"},
+	}
+	for _, tc := range cases {
+		s.sozoOutputDir = tc.dir
+		args := map[string]interface{}{"records": 10.0, "schemaName": "users"}
+		for key, value := range tc.args {
+			args[key] = value
+		}
+		raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: args})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		response := result.(map[string]interface{})
+		if text := response["content"].([]map[string]string)[0]["text"]; response["isError"] != true || !strings.Contains(text, tc.want) {
+			t.Fatalf("%v: expected error containing %q, got %#v", tc.args, tc.want, response)
+		}
+	}
+	if len(captured) != 0 {
+		t.Fatalf("expected every case to be rejected locally, got %+v", captured)
+	}
+}
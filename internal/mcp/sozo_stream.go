@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sozoContentChunk is the number of records per text content block in a
+// streamed sozo.generate result.
+const sozoContentChunk = 500
+
+// streamSozoGenerate asks the backend to stream the generation as NDJSON.
+// Each line carries a batch of records under "records"; the final line has
+// "done": true and may add summary fields, which are kept in the result.
+// Progress is reported after every batch, and the tool timeout bounds the
+// gap between lines rather than the whole generation.
+func (s *Server) streamSozoGenerate(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	payload["stream"] = true
+	total, _ := payload["records"].(float64)
+
+	streamCtx, touch, stop := withIdleTimeout(ctx, s.toolTimeout("sozo.generate"))
+	defer stop()
+
+	records := []interface{}{}
+	result := map[string]interface{}{}
+	done := false
+	err := s.stream(streamCtx, http.MethodPost, "/v1/sozo/generate", payload, func(line []byte) error {
+		touch()
+		var chunk map[string]interface{}
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		switch v := chunk["error"].(type) {
+		case string:
+			return fmt.Errorf("generation failed after %d records: %s", len(records), v)
+		case map[string]interface{}:
+			msg, _ := v["message"].(string)
+			return fmt.Errorf("generation failed after %d records: %s", len(records), msg)
+		}
+		if batch, ok := chunk["records"].([]interface{}); ok {
+			records = append(records, batch...)
+			reportProgress(ctx, float64(len(records)), total)
+		}
+		if done, _ = chunk["done"].(bool); done {
+			for key, value := range chunk {
+				if key != "records" && key != "done" {
+					result[key] = value
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if cause := context.Cause(streamCtx); cause != nil && !errors.Is(cause, context.Canceled) {
+			return nil, cause
+		}
+		return nil, err
+	}
+	if !done {
+		return nil, fmt.Errorf("generation stream ended after %d records without completing", len(records))
+	}
+	result["records"] = records
+	result["count"] = len(records)
+	return result, nil
+}
+
+// withIdleTimeout detaches ctx from its deadline and instead cancels once
+// idle passes without a call to touch. Cancellation of ctx itself, such
+// as an HTTP client going away, still ends the stream.
+func withIdleTimeout(ctx context.Context, idle time.Duration) (streamCtx context.Context, touch func(), stop func()) {
+	streamCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	timer := time.AfterFunc(idle, func() {
+		cancel(fmt.Errorf("Kaizen API sent no data for %s", idle))
+	})
+	stopParent := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancel(context.Canceled)
+		}
+	})
+	touch = func() { timer.Reset(idle) }
+	stop = func() {
+		timer.Stop()
+		stopParent()
+		cancel(nil)
+	}
+	return streamCtx, touch, stop
+}
+
+// renderSozoRecords renders a streamed generation as JSON Lines, split
+// into blocks of sozoContentChunk records after a summary block, so large
+// datasets don't arrive as one multi-megabyte text item.
+func renderSozoRecords(args, data map[string]interface{}) ([]string, bool) {
+	if stream, _ := args["stream"].(bool); !stream {
+		return nil, false
+	}
+	records, _ := data["records"].([]interface{})
+	summary := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if key != "records" {
+			summary[key] = value
+		}
+	}
+	pretty, _ := json.MarshalIndent(summary, "", "  ")
+	texts := []string{string(pretty)}
+	for start := 0; start < len(records); start += sozoContentChunk {
+		end := min(start+sozoContentChunk, len(records))
+		var b strings.Builder
+		for _, record := range records[start:end] {
+			encoded, _ := json.Marshal(record)
+			b.Write(encoded)
+			b.WriteByte('\n')
+		}
+		texts = append(texts, b.String())
+	}
+	return texts, true
+}
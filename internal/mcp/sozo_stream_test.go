@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSozoStreamTestServer(t *testing.T, handler http.HandlerFunc) *Server {
+	t.Helper()
+	hs := httptest.NewServer(handler)
+	t.Cleanup(hs.Close)
+	return &Server{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		client: &kaizenAPIClient{
+			baseURL:    hs.URL,
+			apiKey:     "test-key",
+			httpClient: hs.Client(),
+		},
+	}
+}
+
+func TestServeStreamsSozoGenerateWithProgress(t *testing.T) {
+	var accept, body string
+	s := newSozoStreamTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+		w.Header().Set("Content-Type", ndjsonContentType)
+		for _, line := range []string{
+			`{"records":[{"id":1},{"id":2}]}`,
+			`{"records":[{"id":3}]}`,
+			`{"done":true,"seed":42}`,
+		} {
+			_, _ = io.WriteString(w, line+"\n")
+			w.(http.Flusher).Flush()
+		}
+	})
+	request := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"sozo.generate","arguments":{"records":3,"schemaName":"users","stream":true},"_meta":{"progressToken":"gen-1"}}}`
+	var out bytes.Buffer
+	s.reader = bufio.NewReader(strings.NewReader(request + "\n"))
+	s.writer = bufio.NewWriter(&out)
+	if err := s.Serve(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	if accept != ndjsonContentType || !strings.Contains(body, `"stream":true`) {
+		t.Fatalf("expected an NDJSON streaming request, got accept=%q body=%s", accept, body)
+	}
+
+	reader := bufio.NewReader(&out)
+	var messages []map[string]interface{}
+	for {
+		payload, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		var message map[string]interface{}
+		_ = json.Unmarshal(payload, &message)
+		messages = append(messages, message)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("expected two progress notifications and a response, got %#v", messages)
+	}
+	for i, want := range []float64{2, 3} {
+		params, _ := messages[i]["params"].(map[string]interface{})
+		if messages[i]["method"] != "notifications/progress" || params["progressToken"] != "gen-1" || params["progress"] != want || params["total"] != 3.0 {
+			t.Fatalf("unexpected progress notification %d: %#v", i, messages[i])
+		}
+	}
+
+	result := messages[2]["result"].(map[string]interface{})
+	data := result["structuredContent"].(map[string]interface{})
+	if data["count"] != 3.0 || data["seed"] != 42.0 || len(data["records"].([]interface{})) != 3 {
+		t.Fatalf("unexpected streamed result: %#v", data)
+	}
+	content := result["content"].([]interface{})
+	if len(content) != 2 || content[1].(map[string]interface{})["text"] != "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n" {
+		t.Fatalf("expected a summary block and one JSON Lines block, got %#v", content)
+	}
+}
+
+func TestSozoGenerateStreamUsesIdleTimeout(t *testing.T) {
+	s := newSozoStreamTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		for i := 0; i < 5; i++ {
+			_, _ = io.WriteString(w, `{"records":[{"id":1}]}`+"\n")
+			w.(http.Flusher).Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+		_, _ = io.WriteString(w, `{"done":true}`+"\n")
+	})
+	s.config = &serverConfig{ToolTimeouts: map[string]configDuration{"sozo.generate": configDuration(150 * time.Millisecond)}}
+
+	// Five batches 40ms apart outlast the 150ms timeout as a whole but
+	// never leave the stream idle for that long.
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 5.0, "schemaName": "users", "stream": true}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if response := result.(map[string]interface{}); response["isError"] == true || response["structuredContent"].(map[string]interface{})["count"] != 5 {
+		t.Fatalf("expected the slow but steady stream to complete, got %#v", response)
+	}
+}
+
+func TestSozoGenerateStreamFailsWhenBackendStalls(t *testing.T) {
+	s := newSozoStreamTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		_, _ = io.WriteString(w, `{"records":[{"id":1}]}`+"\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	s.config = &serverConfig{ToolTimeouts: map[string]configDuration{"sozo.generate": configDuration(100 * time.Millisecond)}}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "sozo.generate", Arguments: map[string]interface{}{"records": 5.0, "schemaName": "users", "stream": true}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	response := result.(map[string]interface{})
+	text := response["content"].([]map[string]string)[0]["text"]
+	if response["isError"] != true || !strings.Contains(text, "sent no data for 100ms") {
+		t.Fatalf("expected an idle timeout error, got %#v", response)
+	}
+}
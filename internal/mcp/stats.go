@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cache names reported by mcp.stats.
+const (
+	cacheResultPages   = "resultPages"
+	cacheReadiness     = "readiness"
+	cacheCurrencyRates = "currencyRates"
+)
+
+// latencyBuckets are the upper bounds of the tool latency histograms,
+// wide enough for both quick Enzan reads and long Sozo generations.
+var latencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+}
+
+// serverStats counts tool calls and cache lookups for mcp.stats. The zero
+// value is ready to use; methods on a nil *serverStats do nothing.
+type serverStats struct {
+	started time.Time
+
+	mu     sync.Mutex
+	tools  map[string]*toolStats
+	caches map[string]*cacheStats
+}
+
+type toolStats struct {
+	calls   int
+	errors  int
+	elapsed time.Duration
+	// buckets[i] counts calls no slower than latencyBuckets[i] and slower
+	// than the bucket before; the extra last entry counts the rest.
+	buckets []int
+}
+
+// cumulativeBuckets returns, per bucket bound, the calls no slower than it.
+func (t *toolStats) cumulativeBuckets() []int {
+	cumulative := make([]int, len(latencyBuckets))
+	total := 0
+	for i := range latencyBuckets {
+		total += t.buckets[i]
+		cumulative[i] = total
+	}
+	return cumulative
+}
+
+type cacheStats struct {
+	hits, misses int
+}
+
+func (st *serverStats) recordCall(tool string, elapsed time.Duration, failed bool) {
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.tools == nil {
+		st.tools = map[string]*toolStats{}
+	}
+	entry := st.tools[tool]
+	if entry == nil {
+		entry = &toolStats{buckets: make([]int, len(latencyBuckets)+1)}
+		st.tools[tool] = entry
+	}
+	entry.calls++
+	entry.elapsed += elapsed
+	bucket := len(latencyBuckets)
+	for i, bound := range latencyBuckets {
+		if elapsed <= bound {
+			bucket = i
+			break
+		}
+	}
+	entry.buckets[bucket]++
+	if failed {
+		entry.errors++
+	}
+}
+
+func (st *serverStats) recordLookup(cache string, hit bool) {
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.caches == nil {
+		st.caches = map[string]*cacheStats{}
+	}
+	entry := st.caches[cache]
+	if entry == nil {
+		entry = &cacheStats{}
+		st.caches[cache] = entry
+	}
+	if hit {
+		entry.hits++
+	} else {
+		entry.misses++
+	}
+}
+
+// snapshot renders the counters as the mcp.stats result.
+func (st *serverStats) snapshot() map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tools := make(map[string]interface{}, len(st.tools))
+	var calls, errors int
+	for name, entry := range st.tools {
+		calls += entry.calls
+		errors += entry.errors
+		histogram := map[string]int{}
+		for i, count := range entry.cumulativeBuckets() {
+			histogram[strconv.FormatInt(latencyBuckets[i].Milliseconds(), 10)] = count
+		}
+		tools[name] = map[string]interface{}{
+			"calls":        entry.calls,
+			"errors":       entry.errors,
+			"avgLatencyMs": roundMillis(entry.elapsed / time.Duration(entry.calls)),
+			// Cumulative counts of calls at or under each bound in ms.
+			"latencyHistogramMs": histogram,
+		}
+	}
+	caches := make(map[string]interface{}, len(st.caches))
+	for name, entry := range st.caches {
+		caches[name] = map[string]interface{}{
+			"hits":    entry.hits,
+			"misses":  entry.misses,
+			"hitRate": math.Round(float64(entry.hits)/float64(entry.hits+entry.misses)*1000) / 1000,
+		}
+	}
+	snapshot := map[string]interface{}{
+		"totalCalls":  calls,
+		"totalErrors": errors,
+		"tools":       tools,
+		"caches":      caches,
+	}
+	if !st.started.IsZero() {
+		snapshot["startedAt"] = st.started.UTC().Format(time.RFC3339)
+		snapshot["uptimeSeconds"] = int(time.Since(st.started).Seconds())
+	}
+	return snapshot
+}
+
+func roundMillis(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*10) / 10
+}
+
+// recordStats counts every tool call, including ones refused before they
+// reach the backend, for mcp.stats.
+func (s *Server) recordStats(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		started := time.Now()
+		result := next(ctx, call)
+		s.stats.recordCall(call.Name, time.Since(started), result["isError"] == true)
+		return result
+	}
+}
+
+func (s *Server) callMCPStats(_ context.Context, _ map[string]interface{}) (map[string]interface{}, error) {
+	return s.stats.snapshot(), nil
+}
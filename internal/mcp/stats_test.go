@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMCPStatsReportsCallsAndCacheLookups(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	defer cleanup()
+	s.stats.started = time.Now().Add(-time.Minute)
+
+	call := func(name string, args map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		raw, _ := json.Marshal(toolsCallParams{Name: name, Arguments: args})
+		result, _ := s.handleToolCall(context.Background(), raw)
+		return result.(map[string]interface{})
+	}
+	call("enzan.burn", map[string]interface{}{})
+	call("enzan.burn", map[string]interface{}{})
+	call("akuma.format", map[string]interface{}{"sql": "select 'oops"})
+	call(resultPageTool, map[string]interface{}{"resultId": "missing", "page": 2})
+
+	stats := call("mcp.stats", map[string]interface{}{})["structuredContent"].(map[string]interface{})
+	if stats["uptimeSeconds"].(int) < 60 || stats["totalCalls"] != 4 || stats["totalErrors"] != 2 {
+		t.Fatalf("unexpected totals: %#v", stats)
+	}
+	tools := stats["tools"].(map[string]interface{})
+	if burn := tools["enzan.burn"].(map[string]interface{}); burn["calls"] != 2 || burn["errors"] != 0 {
+		t.Fatalf("enzan.burn = %#v", burn)
+	}
+	if format := tools["akuma.format"].(map[string]interface{}); format["errors"] != 1 {
+		t.Fatalf("akuma.format = %#v", format)
+	}
+	pages := stats["caches"].(map[string]interface{})[cacheResultPages].(map[string]interface{})
+	if pages["hits"] != 0 || pages["misses"] != 1 || pages["hitRate"] != 0.0 {
+		t.Fatalf("resultPages = %#v", pages)
+	}
+}
+
+func TestToolLatencyHistogramsArePerTool(t *testing.T) {
+	var st serverStats
+	st.recordCall("akuma.query", 40*time.Millisecond, false)
+	st.recordCall("akuma.query", 300*time.Millisecond, true)
+	st.recordCall("sozo.generate", 3*time.Minute, false)
+
+	tools := st.snapshot()["tools"].(map[string]interface{})
+	query := tools["akuma.query"].(map[string]interface{})["latencyHistogramMs"].(map[string]int)
+	if query["50"] != 1 || query["250"] != 1 || query["500"] != 2 || query["120000"] != 2 {
+		t.Fatalf("akuma.query histogram = %#v", query)
+	}
+	generate := tools["sozo.generate"].(map[string]interface{})["latencyHistogramMs"].(map[string]int)
+	if generate["120000"] != 0 {
+		t.Fatalf("sozo.generate histogram = %#v", generate)
+	}
+}
+
+func TestMetricsEndpointServesPrometheusText(t *testing.T) {
+	srv := &Server{}
+	srv.stats.recordCall("akuma.query", 40*time.Millisecond, false)
+	srv.stats.recordCall("akuma.query", 300*time.Millisecond, true)
+	srv.stats.recordLookup(cacheResultPages, true)
+	hs := newHTTPTestServer(t, srv)
+
+	resp, err := http.Get(hs.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	for _, line := range []string{
+		`kaizen_mcp_tool_calls_total{tool="akuma.query"} 2`,
+		`kaizen_mcp_tool_errors_total{tool="akuma.query"} 1`,
+		`kaizen_mcp_tool_duration_seconds_bucket{tool="akuma.query",le="0.05"} 1`,
+		`kaizen_mcp_tool_duration_seconds_bucket{tool="akuma.query",le="0.25"} 1`,
+		`kaizen_mcp_tool_duration_seconds_bucket{tool="akuma.query",le="0.5"} 2`,
+		`kaizen_mcp_tool_duration_seconds_bucket{tool="akuma.query",le="+Inf"} 2`,
+		`kaizen_mcp_tool_duration_seconds_count{tool="akuma.query"} 2`,
+		`kaizen_mcp_cache_lookups_total{cache="resultPages",result="hit"} 1`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Fatalf("metrics missing %q:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"time"
+)
+
+// callKaizenStatus reports whether the backend is usable from this server:
+// reachability and latency of the health endpoint, per-component health
+// as the backend reports it, and the identity the credentials resolve to.
+// Backend failures are part of the report rather than a tool error, so an
+// agent can read the diagnosis instead of guessing.
+func (s *Server) callKaizenStatus(ctx context.Context, _ map[string]interface{}) (map[string]interface{}, error) {
+	client := s.apiClient(ctx)
+	report := map[string]interface{}{
+		"server": map[string]interface{}{
+			"name":            serverName,
+			"version":         serverVersion,
+			"protocolVersion": protocol,
+		},
+		"apiBaseUrl": client.baseURL,
+	}
+	if !client.hasCredentials(ctx) {
+		report["configured"] = false
+		report["setup"] = setupInstructions
+		return report, nil
+	}
+
+	started := time.Now()
+	health, err := client.typed().Health(ctx)
+	report["latencyMs"] = time.Since(started).Milliseconds()
+	if err != nil {
+		report["reachable"] = false
+		report["error"] = diagnoseBackendError(client.baseURL, err).Error()
+		return report, nil
+	}
+	report["reachable"] = true
+	if health.Components != nil {
+		report["components"] = health.Components
+	}
+	if health.Status != "" {
+		report["status"] = health.Status
+	}
+	advertised := health.APIVersion
+	report["apiVersion"] = advertised
+	report["supportedApiVersion"] = supportedAPIVersion
+	if warning := apiVersionWarning(advertised); warning != "" {
+		report["compatibilityWarning"] = warning
+	}
+
+	identity, err := client.typed().WhoAmI(ctx)
+	if err != nil {
+		report["identityError"] = diagnoseBackendError(client.baseURL, err).Error()
+		return report, nil
+	}
+	report["identity"] = identity.Fields()
+	return report, nil
+}
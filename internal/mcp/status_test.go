@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKaizenStatusReportsHealthAndIdentity(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/health": `{"status":"degraded","components":{"akuma":"ok","enzan":"ok","sozo":"down"}}`,
+		"GET /v1/whoami": `{"organization":"acme","keyName":"ci"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.status", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	data := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	components, _ := data["components"].(map[string]interface{})
+	identity, _ := data["identity"].(map[string]interface{})
+	server, _ := data["server"].(map[string]interface{})
+	if data["reachable"] != true || data["status"] != "degraded" || components["sozo"] != "down" || identity["organization"] != "acme" || server["version"] != serverVersion {
+		t.Fatalf("unexpected status report: %#v", data)
+	}
+}
+
+func TestKaizenStatusDiagnosesUnreachableBackend(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{})
+	cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.status", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	response := result.(map[string]interface{})
+	data := response["structuredContent"].(map[string]interface{})
+	if response["isError"] == true || data["reachable"] != false || !strings.Contains(data["error"].(string), "cannot reach") {
+		t.Fatalf("expected an unreachable report with a diagnosis, got %#v", response)
+	}
+	if _, ok := data["identity"]; ok {
+		t.Fatalf("expected identity lookup to be skipped, got %#v", data)
+	}
+}
+
+func TestKaizenWhoamiReturnsIdentity(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/whoami": `{"subject":"svc-ci","organization":"acme","scopes":["akuma:read","enzan:read"]}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.whoami", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/whoami" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["organization"] != "acme" {
+		t.Fatalf("expected identity passthrough, got %#v", data)
+	}
+}
+
+func TestKaizenUsageReturnsQuota(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/usage": `{"callsToday":812,"dailyLimit":1000,"remaining":188,"resetsAt":"2024-06-02T00:00:00Z"}`,
+	})
+	defer cleanup()
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.usage", Arguments: map[string]interface{}{}})
+	result, rpcErr := s.handleToolCall(context.Background(), raw)
+	if rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	if len(captured) != 1 || captured[0].Path != "/v1/usage" {
+		t.Fatalf("unexpected captured request: %+v", captured)
+	}
+	if data := result.(map[string]interface{})["structuredContent"].(map[string]interface{}); data["remaining"] != 188.0 {
+		t.Fatalf("expected usage passthrough, got %#v", data)
+	}
+}
@@ -0,0 +1,412 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// toolExample is one sample invocation advertised in _meta.examples so
+// agent frameworks that support few-shot tool examples can use it. Result
+// shows the shape of a successful structuredContent, not real data.
+type toolExample struct {
+	Description string                 `json:"description,omitempty"`
+	Arguments   map[string]interface{} `json:"arguments"`
+	Result      interface{}            `json:"result,omitempty"`
+}
+
+// withExamples attaches sample invocations to a tool.
+func withExamples(examples ...toolExample) toolOption {
+	return func(t *registeredTool) {
+		if len(examples) == 0 {
+			return
+		}
+		meta := toolMeta{}
+		if t.def.Meta != nil {
+			meta = *t.def.Meta
+		}
+		meta.Examples = append(meta.Examples, examples...)
+		t.def.Meta = &meta
+	}
+}
+
+// jsonExample builds a toolExample from JSON literals, which read far
+// better than nested Go maps. Invalid JSON is a programming error and
+// panics at startup.
+func jsonExample(description, arguments, result string) toolExample {
+	example := toolExample{Description: description}
+	if err := json.Unmarshal([]byte(arguments), &example.Arguments); err != nil {
+		panic(fmt.Sprintf("mcp: invalid example arguments %q: %v", description, err))
+	}
+	if result != "" {
+		if err := json.Unmarshal([]byte(result), &example.Result); err != nil {
+			panic(fmt.Sprintf("mcp: invalid example result %q: %v", description, err))
+		}
+	}
+	return example
+}
+
+// builtinToolExamples holds the examples for every built-in tool. They are
+// kept apart from the registrations in tools.go so the schemas stay easy
+// to scan; a test checks that every example validates against its schema.
+var builtinToolExamples = map[string][]toolExample{
+	"akuma.query": {
+		jsonExample("Generate SQL only",
+			`{"dialect":"postgres","prompt":"Top 10 customers by revenue last month","mode":"sql-only"}`,
+			`{"sql":"SELECT customer_id, SUM(amount) AS revenue FROM orders WHERE ... GROUP BY 1 ORDER BY 2 DESC LIMIT 10"}`),
+		jsonExample("Generate SQL and return rows",
+			`{"dialect":"snowflake","prompt":"Daily signups this week","mode":"sql-and-results","maxRows":50,"sourceId":"warehouse"}`,
+			`{"sql":"SELECT ...","columns":["day","signups"],"rows":[["2024-06-03",412]]}`),
+	},
+	"akuma.query_interactive": {
+		jsonExample("Interactive generation",
+			`{"dialect":"bigquery","prompt":"Revenue by region for Q1"}`,
+			`{"status":"completed","sql":"SELECT region, SUM(revenue) ..."}`),
+	},
+	"akuma.batch": {
+		jsonExample("Generate the queries behind a dashboard at once",
+			`{"dialect":"postgres","prompts":["Daily signups this week","Revenue by plan this month"],"mode":"sql-only"}`,
+			`{"results":[{"prompt":"Daily signups this week","result":{"sql":"SELECT ..."}},{"prompt":"Revenue by plan this month","error":"rate limited (status=429)"}],"succeeded":1,"failed":1}`),
+	},
+	"akuma.explain": {
+		jsonExample("Explain a query with a rendered plan",
+			`{"sql":"SELECT * FROM orders WHERE status = 'paid'","format":"mermaid"}`,
+			`{"explanation":"Scans orders and keeps paid rows.","plan":{"node":"Seq Scan","relation":"orders"}}`),
+	},
+	"akuma.schema": {
+		jsonExample("Register tables for a source, checking the effect first",
+			`{"dialect":"postgres","sourceId":"app-db","tables":[{"name":"orders","columns":[{"name":"id","type":"bigint"},{"name":"amount","type":"numeric"}]}],"dryRun":true}`,
+			`{"sourceId":"app-db","version":"v3","tables":1}`),
+	},
+	"akuma.schema.get": {
+		jsonExample("Read the registered schema for a source",
+			`{"sourceId":"warehouse"}`,
+			`{"sourceId":"warehouse","version":"v7","dialect":"snowflake","tables":[{"name":"orders"}]}`),
+	},
+	"akuma.schema.introspect": {
+		jsonExample("Introspect selected tables from a configured connection",
+			`{"connection":"warehouse-prod","schemas":["analytics"],"tables":["orders","customers"]}`,
+			`{"sourceId":"warehouse-prod","version":"v8","tables":[{"name":"orders"},{"name":"customers"}]}`),
+	},
+	"akuma.validate": {
+		jsonExample("Catch a misspelled column",
+			`{"sql":"SELECT emial FROM users","dialect":"postgres"}`,
+			`{"valid":false,"diagnostics":[{"severity":"error","code":"unknown_column","message":"column \"emial\" does not exist","line":1,"column":8}]}`),
+	},
+	"akuma.optimize": {
+		jsonExample("Rewrite a non-sargable predicate",
+			`{"sql":"SELECT id FROM orders WHERE DATE(created_at) >= '2024-01-01'","dialect":"postgres"}`,
+			`{"sql":"SELECT id FROM orders WHERE created_at >= '2024-01-01'","rewrites":[{"rule":"sargable_predicate"}],"estimatedImprovement":{"costReductionPct":62}}`),
+	},
+	"akuma.lint": {
+		jsonExample("Lint for style and safety issues",
+			`{"sql":"SELECT * FROM orders","dialect":"mysql"}`,
+			`{"findings":[{"rule":"select_star","severity":"warning","line":1}]}`),
+	},
+	"akuma.history": {
+		jsonExample("Recent rejected queries",
+			`{"status":"rejected","since":"2024-06-01T00:00:00Z","limit":20}`,
+			`{"items":[{"id":"q1","sql":"DELETE FROM orders","status":"rejected"}],"nextCursor":"c2"}`),
+	},
+	"akuma.diff": {
+		jsonExample("Compare two versions of a query",
+			`{"before":"SELECT id FROM orders","after":"SELECT id FROM orders WHERE status = 'paid'","dialect":"postgres"}`,
+			`{"filters":{"added":["status = 'paid'"]},"joins":{},"projections":{}}`),
+	},
+	"akuma.preview": {
+		jsonExample("Peek at a few masked rows",
+			`{"table":"public.users","columns":["id","email"],"limit":5}`,
+			`{"columns":["id","email"],"rows":[[1,"***"]],"masked":["email"],"rowCap":20}`),
+	},
+	"akuma.convert": {
+		jsonExample("Translate Postgres SQL to BigQuery",
+			`{"sql":"SELECT date_trunc('month', created_at) FROM orders","from":"postgres","to":"bigquery"}`,
+			`{"sql":"SELECT DATE_TRUNC(created_at, MONTH) FROM orders","notes":[]}`),
+	},
+	"akuma.cost": {
+		jsonExample("Estimate a BigQuery scan",
+			`{"sql":"SELECT * FROM events","dialect":"bigquery"}`,
+			`{"bytesScanned":1099511627776,"estimatedCostUSD":6.25,"method":"dry_run"}`),
+	},
+	"akuma.generate_and_check": {
+		jsonExample("Generate, validate, and cost a query in one call",
+			`{"dialect":"bigquery","prompt":"Weekly active users for the last 12 weeks","sourceId":"warehouse"}`,
+			`{"sql":"SELECT ...","query":{"sql":"SELECT ..."},"validation":{"valid":true,"diagnostics":[]},"cost":{"bytesScanned":52428800,"estimatedCostUSD":0.0003}}`),
+	},
+	"akuma.format": {
+		jsonExample("Tidy a one-line query",
+			`{"sql":"select id, name from users where active = true and age > 18 order by name","dialect":"postgres"}`,
+			`{"dialect":"postgres","sql":"SELECT id,\n  name\nFROM users\nWHERE active = TRUE\n  AND age > 18\nORDER BY name"}`),
+	},
+	"akuma.glossary": {
+		jsonExample("Resolve a business term before writing SQL",
+			`{"term":"active customer","sourceId":"warehouse"}`,
+			`{"terms":[{"term":"active customer","definition":"Customer with a paid order in the last 90 days","tables":["customers","orders"],"columns":["orders.customer_id","orders.created_at"],"filter":"orders.status = 'paid' AND orders.created_at >= CURRENT_DATE - 90"}]}`),
+	},
+	"akuma.feedback": {
+		jsonExample("Record a user's fix to generated SQL",
+			`{"queryId":"q_81f2","rating":"down","correction":"SELECT id FROM orders WHERE status = 'paid'","comment":"Should only count paid orders"}`,
+			`{"id":"fb_1c9","queryId":"q_81f2","recorded":true}`),
+	},
+	"enzan.summary": {
+		jsonExample("Spend for the last day by project",
+			`{"window":"24h","groupBy":["project"]}`,
+			`{"window":"24h","totalCostUSD":1240.5,"groups":[{"key":"vision","costUSD":480.25}]}`),
+	},
+	"enzan.costs_by_model": {
+		jsonExample("Model costs for the last 30 days",
+			`{"window":"30d"}`,
+			`{"window":"30d","models":[{"provider":"openai","model":"gpt-4o","costUSD":812.4}]}`),
+	},
+	"enzan.routing": {
+		jsonExample("Show the routing configuration", `{}`,
+			`{"enabled":true,"simple_model":"gpt-4o-mini","moderate_model":"gpt-4o","complex_model":"o1"}`),
+	},
+	"enzan.set_routing": {
+		jsonExample("Enable routing, checking the effect first",
+			`{"enabled":true,"simple_model":"gpt-4o-mini","complex_model":"gpt-4o","dryRun":true}`,
+			`{"enabled":true,"simple_model":"gpt-4o-mini","complex_model":"gpt-4o"}`),
+	},
+	"enzan.routing_savings": {
+		jsonExample("Routing savings this week", `{"window":"7d"}`,
+			`{"window":"7d","savingsUSD":312.8,"routedRequests":18400}`),
+	},
+	"enzan.pricing_models": {
+		jsonExample("List model pricing", `{}`,
+			`{"models":[{"provider":"openai","model":"gpt-4o","input_cost_per_1k_tokens_usd":0.005,"output_cost_per_1k_tokens_usd":0.015}]}`),
+	},
+	"enzan.set_model_pricing": {
+		jsonExample("Override a model's token prices",
+			`{"provider":"openai","model":"gpt-4o","input_cost_per_1k_tokens_usd":0.005,"output_cost_per_1k_tokens_usd":0.015}`,
+			`{"provider":"openai","model":"gpt-4o","active":true}`),
+	},
+	"enzan.pricing_gpus": {
+		jsonExample("List GPU pricing", `{}`,
+			`{"gpus":[{"provider":"aws","gpu_type":"H100","hourly_rate_usd":12.29}]}`),
+	},
+	"enzan.set_gpu_pricing": {
+		jsonExample("Override a GPU hourly rate",
+			`{"provider":"aws","gpu_type":"H100","hourly_rate_usd":11.5}`,
+			`{"provider":"aws","gpu_type":"H100","hourly_rate_usd":11.5,"active":true}`),
+	},
+	"enzan.pricing_refresh_trigger": {
+		jsonExample("Queue a pricing refresh", `{}`, `{"status":"queued","triggeredBy":"u1"}`),
+	},
+	"enzan.pricing_refresh_log": {
+		jsonExample("Last five pricing refreshes", `{"limit":5}`,
+			`{"entries":[{"startedAt":"2024-06-01T02:00:00Z","status":"succeeded","updated":42}]}`),
+	},
+	"enzan.pricing_providers": {
+		jsonExample("List pricing providers", `{}`, `{"providers":[{"name":"aws","lastRefreshedAt":"2024-06-01T02:00:00Z"}]}`),
+	},
+	"enzan.pricing_offers_upsert": {
+		jsonExample("Record a reserved GPU offer",
+			`{"gpu":{"provider":"lambda","gpuType":"H100","displayName":"Lambda H100 reserved","hourlyRateUSD":2.49,"deploymentClass":"reserved"}}`,
+			`{"status":"upserted","gpu":{"id":"offer-123"}}`),
+	},
+	"enzan.optimize": {
+		jsonExample("Optimization opportunities this week", `{"window":"7d"}`,
+			`{"window":"7d","recommendations":[{"title":"Route simple prompts to a smaller model","estimatedSavingsUSD":420}]}`),
+	},
+	"enzan.alerts": {
+		jsonExample("List alert rules", `{}`,
+			`{"alerts":[{"id":"a1","name":"Daily spend over $500","type":"cost_threshold","threshold":500,"enabled":true}]}`),
+	},
+	"enzan.create_alert": {
+		jsonExample("Alert when daily spend passes $500",
+			`{"name":"Daily spend over $500","type":"cost_threshold","threshold":500,"window":"24h"}`,
+			`{"id":"a1","name":"Daily spend over $500","enabled":true}`),
+	},
+	"enzan.update_alert": {
+		jsonExample("Raise an alert threshold", `{"id":"a1","threshold":750}`,
+			`{"id":"a1","threshold":750,"enabled":true}`),
+	},
+	"enzan.delete_alert": {
+		jsonExample("Delete an alert rule", `{"id":"a1"}`, `{"deleted":true}`),
+	},
+	"enzan.alert_events": {
+		jsonExample("Active alerts in the last day", `{"status":"active","window":"24h","limit":20}`,
+			`{"events":[{"id":"e1","status":"active","alertId":"a1","resources":[{"cluster":"gpu-east"}]}]}`),
+	},
+	"enzan.alert_deliveries": {
+		jsonExample("Recent webhook deliveries", `{"limit":10}`,
+			`{"deliveries":[{"id":"d1","endpointId":"ep1","status":"delivered","responseCode":200}]}`),
+	},
+	"enzan.alert_endpoints": {
+		jsonExample("List webhook endpoints", `{}`,
+			`{"endpoints":[{"id":"ep1","targetUrl":"https://hooks.example.com/kaizen","enabled":true}]}`),
+	},
+	"enzan.create_alert_endpoint": {
+		jsonExample("Add a webhook endpoint", `{"targetUrl":"https://hooks.example.com/kaizen"}`,
+			`{"id":"ep1","targetUrl":"https://hooks.example.com/kaizen","enabled":true}`),
+	},
+	"enzan.update_alert_endpoint": {
+		jsonExample("Pause a webhook endpoint", `{"id":"ep1","enabled":false}`,
+			`{"id":"ep1","enabled":false}`),
+	},
+	"enzan.delete_alert_endpoint": {
+		jsonExample("Delete a webhook endpoint", `{"id":"ep1"}`, `{"deleted":true}`),
+	},
+	"enzan.chat": {
+		jsonExample("Ask a cost question",
+			`{"message":"Why did GPU spend jump yesterday?","window":"7d"}`,
+			`{"conversationId":"c1","answer":"Spend rose 40% because gpu-east ran 3 extra H100 nodes..."}`),
+	},
+	"enzan.burn": {
+		jsonExample("Current burn rate", `{}`, `{"hourlyUSD":52.1,"dailyUSD":1250.4,"monthlyProjectedUSD":37512}`),
+	},
+	"enzan.forecast": {
+		jsonExample("Spend forecast for the next month", `{"horizon":"30d"}`,
+			`{"horizon":"30d","projectedUSD":41200,"series":[{"date":"2024-06-01","expectedUSD":1370,"lowerUSD":1200,"upperUSD":1550}]}`),
+	},
+	"enzan.budgets": {
+		jsonExample("List budgets", `{}`, `{"budgets":[{"id":"b1","amountUSD":5000,"period":"monthly","spentUSD":3120}]}`),
+	},
+	"enzan.set_budget": {
+		jsonExample("Create a team budget, checking the effect first",
+			`{"name":"ML research","amountUSD":5000,"period":"monthly","scope":{"team":"ml-research"},"dryRun":true}`,
+			`{"id":"b2","amountUSD":5000,"period":"monthly"}`),
+	},
+	"enzan.anomalies": {
+		jsonExample("Large deviations this week", `{"window":"7d","minDeviationPct":50,"limit":10}`,
+			`{"anomalies":[{"project":"vision","cluster":"gpu-east","expectedUSD":120,"actualUSD":480,"deviationPct":300}]}`),
+	},
+	"enzan.breakdown": {
+		jsonExample("Top teams by cost", `{"dimension":"team","window":"30d","top":5}`,
+			`{"dimension":"team","groups":[{"key":"ml-research","costUSD":920.5}]}`),
+		jsonExample("Cost by a label value", `{"dimension":"label","labelKey":"env","window":"7d"}`,
+			`{"dimension":"label","groups":[{"key":"prod","costUSD":3120}]}`),
+	},
+	"enzan.events": {
+		jsonExample("Preemptions in the last day",
+			`{"window":"24h","kind":"preemption","cluster":"gpu-east"}`,
+			`{"window":"24h","events":[{"time":"2024-06-03T02:14:09Z","kind":"preemption","cluster":"gpu-east","node":"gpu-east-7","workload":"train-llm-42"}]}`),
+	},
+	"enzan.utilization": {
+		jsonExample("Per-node utilization for a cluster", `{"cluster":"gpu-east","groupBy":"node","window":"24h"}`,
+			`{"nodes":[{"node":"gpu-east-3","smOccupancyPct":41.2,"memoryUsedPct":63,"idlePct":38.5}]}`),
+	},
+	"enzan.recommend": {
+		jsonExample("Spot recommendations worth at least $500", `{"kind":"spot","minSavingsUSD":500}`,
+			`{"recommendations":[{"kind":"spot","resource":"gpu-east-3","estimatedMonthlySavingsUSD":1840}]}`),
+	},
+	"enzan.export": {
+		jsonExample("Export project costs as CSV", `{"window":"30d","groupBy":["project"]}`,
+			`{"format":"csv","rowCount":2,"csv":"project,costUSD\nvision,480.25\nnlp,120\n"}`),
+	},
+	"enzan.compare": {
+		jsonExample("This week against last week by project", `{"period":"week","groupBy":["project"]}`,
+			`{"period":"week","groups":[{"key":"vision","previousUSD":400,"currentUSD":520,"deltaUSD":120,"deltaPct":30}]}`),
+	},
+	"enzan.idle": {
+		jsonExample("GPUs idle for a day or more", `{"minHours":24,"thresholdPct":5}`,
+			`{"gpus":[{"node":"gpu-east-3","gpu":2,"idleHours":71,"idleCostUSD":213}]}`),
+	},
+	"enzan.allocation": {
+		jsonExample("Monthly chargeback by cost center", `{"mode":"chargeback","window":"30d"}`,
+			`{"costCenters":[{"name":"CC-1042","costUSD":8120.4}],"unallocatedUSD":312.9}`),
+	},
+	"sozo.generate": {
+		jsonExample("Generate records from a saved schema", `{"schemaName":"payments","records":100,"seed":42}`,
+			`{"records":[{"id":1,"amount":118.2,"currency":"USD"}],"count":100,"contentHash":"sha256:9f2b…","determinism":{"contentHash":"sha256:9f2b…","backendHash":"sha256:9f2b…","verified":true}}`),
+		jsonExample("Write a CSV export to the output directory", `{"schemaName":"payments","records":10000,"format":"csv","outputFile":"payments.csv"}`,
+			`{"format":"csv","outputFile":"payments.csv","bytes":482113}`),
+	},
+	"sozo.schemas": {
+		jsonExample("Saved schemas tagged finance", `{"tag":"finance"}`,
+			`{"schemas":[{"name":"payments","tags":["finance"],"fieldCount":12}]}`),
+	},
+	"sozo.validate": {
+		jsonExample("Catch an inverted range",
+			`{"schema":{"fields":{"age":{"type":"integer","range":{"min":90,"max":18}}}}}`,
+			`{"valid":false,"diagnostics":[{"severity":"error","path":"fields.age.range","message":"min 90 is greater than max 18"}]}`),
+	},
+	"sozo.preview": {
+		jsonExample("Preview three records", `{"schemaName":"payments","records":3}`,
+			`{"records":[{"id":1,"email":"a@example.com"}]}`),
+	},
+	"sozo.anonymize": {
+		jsonExample("Mask emails and keep amounts",
+			`{"records":[{"email":"jane@acme.com","amount":118.2}],"rules":{"email":"synthesize","amount":"keep"}}`,
+			`{"records":[{"email":"x91f@example.net","amount":118.2}]}`),
+	},
+	"sozo.schema.save": {
+		jsonExample("Save a schema under a name",
+			`{"name":"checkout-events","schema":{"fields":{"orderId":{"type":"uuid"},"total":{"type":"number"}}},"tags":["commerce"]}`,
+			`{"name":"checkout-events","version":1}`),
+	},
+	"sozo.schema.delete": {
+		jsonExample("Check what deleting a schema would do", `{"name":"checkout-events","dryRun":true}`,
+			`{"name":"checkout-events","deleted":false}`),
+	},
+	"sozo.jobs.start": {
+		jsonExample("Start a large background generation", `{"schemaName":"payments","records":100000}`,
+			`{"jobId":"job-7","status":"queued"}`),
+	},
+	"sozo.jobs.status": {
+		jsonExample("Poll a generation job", `{"jobId":"job-7"}`,
+			`{"jobId":"job-7","status":"running","progress":40000}`),
+	},
+	"sozo.jobs.result": {
+		jsonExample("Read the first page of a finished job", `{"jobId":"job-7","limit":100}`,
+			`{"jobId":"job-7","total":100000,"records":[{"id":1001}]}`),
+	},
+	"sozo.augment": {
+		jsonExample("Grow a small sample",
+			`{"seedRecords":[{"plan":"pro","seats":12},{"plan":"team","seats":4}],"records":200}`,
+			`{"records":[{"plan":"pro","seats":12},{"plan":"team","seats":4}],"count":200}`),
+	},
+	"sozo.drift": {
+		jsonExample("Shift amounts up 15%", `{"schemaName":"payments","records":500,"shiftPct":15,"fields":["amount"],"kind":"mean_shift"}`,
+			`{"records":[{"amount":212.5}],"report":{"amount":{"kind":"mean_shift","baseMean":100,"shiftedMean":115}}}`),
+	},
+	"sozo.correlations": {
+		jsonExample("Check a generation job's correlation fidelity",
+			`{"jobId":"job_7f3a","fields":["age","income"]}`,
+			`{"fields":["age","income"],"matrix":[[1,0.58],[0.58,1]],"pairs":[{"fields":["age","income"],"requested":0.6,"realized":0.58,"delta":-0.02}]}`),
+		jsonExample("Check inline records against the requested correlations",
+			`{"records":[{"age":31,"income":52000},{"age":45,"income":81000},{"age":23,"income":30500}],"correlations":{"age":{"income":0.6}}}`,
+			`{"fields":["age","income"],"matrix":[[1,0.99],[0.99,1]],"pairs":[{"fields":["age","income"],"requested":0.6,"realized":0.99,"delta":0.39}]}`),
+	},
+	"sozo.seeds": {
+		jsonExample("Find the seeded runs for a preset",
+			`{"schemaName":"saas_customers","limit":10}`,
+			`{"items":[{"name":"qa-fixtures-2024-06","seed":42,"schemaName":"saas_customers","records":500,"createdAt":"2024-06-03T09:12:00Z"}],"nextCursor":"c2"}`),
+	},
+	"sozo.seeds.replay": {
+		jsonExample("Regenerate QA fixtures from a named run",
+			`{"name":"qa-fixtures-2024-06"}`,
+			`{"name":"qa-fixtures-2024-06","seed":42,"records":[{"customer_id":"c_0001","plan":"pro"}]}`),
+	},
+	"mcp.stats": {
+		jsonExample("Inspect server health", `{}`,
+			`{"startedAt":"2024-06-03T09:00:00Z","uptimeSeconds":5400,"totalCalls":42,"totalErrors":3,"tools":{"akuma.query":{"calls":30,"errors":2,"avgLatencyMs":812.4,"latencyHistogramMs":{"50":0,"100":1,"250":6,"500":14,"1000":23,"2500":28,"5000":30,"10000":30,"30000":30,"60000":30,"120000":30}}},"caches":{"resultPages":{"hits":4,"misses":1,"hitRate":0.8}}}`),
+	},
+	"kaizen.status": {
+		jsonExample("Check the backend before reporting an outage", `{}`,
+			`{"status":"ok","reachable":true,"latencyMs":84,"components":{"akuma":"ok","enzan":"ok","sozo":"ok"}}`),
+	},
+	"kaizen.whoami": {
+		jsonExample("Confirm the tenant in use", `{}`,
+			`{"subject":"svc-ci","organization":"acme","scopes":["akuma:read","enzan:read"]}`),
+	},
+	"kaizen.usage": {
+		jsonExample("Remaining quota", `{}`,
+			`{"callsToday":812,"dailyLimit":1000,"remaining":188,"resetsAt":"2024-06-02T00:00:00Z"}`),
+	},
+	resultPageTool: {
+		jsonExample("Fetch the second page of a truncated result", `{"resultId":"3f2a9c0d1e4b5a6c7d8e9f0a1b2c3d4e","page":2}`,
+			`{"resultId":"3f2a9c0d1e4b5a6c7d8e9f0a1b2c3d4e","page":2,"pages":4,"totalBytes":240113,"text":"..."}`),
+	},
+}
+
+// attachExamples adds examples to already registered tools.
+func (r *Registry) attachExamples(examples map[string][]toolExample) {
+	for name, list := range examples {
+		tool, ok := r.tools[name]
+		if !ok {
+			continue
+		}
+		withExamples(list...)(&tool)
+		r.tools[name] = tool
+	}
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEveryBuiltinToolHasValidExamples(t *testing.T) {
+	for _, def := range toolDefinitions() {
+		if def.Meta == nil || len(def.Meta.Examples) == 0 {
+			t.Errorf("%s has no examples", def.Name)
+			continue
+		}
+		tool, _ := builtinTools.lookup(def.Name)
+		for _, example := range def.Meta.Examples {
+			if err := validateArguments(tool.schema, example.Arguments); err != nil {
+				t.Errorf("%s example %q: %v", def.Name, example.Description, err)
+			}
+		}
+	}
+	for name := range builtinToolExamples {
+		if _, ok := builtinTools.lookup(name); !ok {
+			t.Errorf("examples declared for unknown tool %s", name)
+		}
+	}
+}
+
+func TestExamplesAreListedUnderMeta(t *testing.T) {
+	s := &Server{}
+	for _, def := range s.listTools() {
+		if def.Name != "akuma.validate" {
+			continue
+		}
+		encoded, _ := json.Marshal(def)
+		if !strings.Contains(string(encoded), `"_meta":{"examples":[{"description":"Catch a misspelled column","arguments":{`) {
+			t.Fatalf("unexpected tools/list entry: %s", encoded)
+		}
+		return
+	}
+	t.Fatal("akuma.validate not listed")
+}
+
+func TestExamplesKeepDeprecationMeta(t *testing.T) {
+	r := newToolRegistry()
+	r.register("x.old", toolDefinition{InputSchema: map[string]interface{}{"type": "object"}}, restTool{method: http.MethodGet, path: "/v1/x"}.handler(),
+		withExamples(jsonExample("call it", `{}`, `{"ok":true}`)), deprecated("x.new"))
+	tool, _ := r.lookup("x.old")
+	if tool.def.Meta == nil || !tool.def.Meta.Deprecated || len(tool.def.Meta.Examples) != 1 {
+		t.Fatalf("meta = %+v", tool.def.Meta)
+	}
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// toolFilter limits which tools a deployment exposes. Patterns are
+// path.Match globs over tool names ("enzan.*", "akuma.query"). A tool is
+// exposed when it matches enable (or enable is empty) and does not match
+// disable.
+type toolFilter struct {
+	enable  []string
+	disable []string
+}
+
+// newToolFilter prefers KAIZEN_TOOLS_ENABLE/KAIZEN_TOOLS_DISABLE over the
+// config file's enableTools/disableTools, list by list.
+func newToolFilter(cfg *serverConfig) (toolFilter, error) {
+	filter := toolFilter{enable: cfg.EnableTools, disable: cfg.DisableTools}
+	if raw := getEnv("KAIZEN_TOOLS_ENABLE", ""); raw != "" {
+		filter.enable = splitToolPatterns(raw)
+	}
+	if raw := getEnv("KAIZEN_TOOLS_DISABLE", ""); raw != "" {
+		filter.disable = splitToolPatterns(raw)
+	}
+	for _, pattern := range append(append([]string{}, filter.enable...), filter.disable...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return toolFilter{}, fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return filter, nil
+}
+
+func splitToolPatterns(raw string) []string {
+	var patterns []string
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			patterns = append(patterns, field)
+		}
+	}
+	return patterns
+}
+
+func (f toolFilter) allows(name string) bool {
+	if len(f.enable) > 0 && !matchesToolPattern(f.enable, name) {
+		return false
+	}
+	return !matchesToolPattern(f.disable, name)
+}
+
+func matchesToolPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
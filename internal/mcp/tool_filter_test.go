@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToolFilterRestrictsListAndCall(t *testing.T) {
+	t.Setenv("KAIZEN_TOOLS_ENABLE", "enzan.*")
+	t.Setenv("KAIZEN_TOOLS_DISABLE", "enzan.set_*, enzan.chat")
+	filter, err := newToolFilter(&serverConfig{EnableTools: []string{"akuma.*"}})
+	if err != nil {
+		t.Fatalf("newToolFilter: %v", err)
+	}
+
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}, tools: newBuiltinToolRegistry()}
+	s.tools.retain(filter.allows)
+
+	for _, def := range s.listTools() {
+		if !strings.HasPrefix(def.Name, "enzan.") || strings.HasPrefix(def.Name, "enzan.set_") || def.Name == "enzan.chat" {
+			t.Fatalf("filtered tool %q still listed", def.Name)
+		}
+	}
+	if _, ok := s.tools.lookup("enzan.burn"); !ok {
+		t.Fatalf("expected enzan.burn to remain enabled")
+	}
+	for _, name := range []string{"akuma.query", "enzan.set_routing"} {
+		_, rpcErr := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"`+name+`","arguments":{}}`))
+		if rpcErr == nil || rpcErr.Code != -32602 {
+			t.Fatalf("expected %s to be refused, got %#v", name, rpcErr)
+		}
+	}
+}
+
+func TestToolFilterUsesConfigWhenEnvUnset(t *testing.T) {
+	t.Setenv("KAIZEN_TOOLS_ENABLE", "")
+	t.Setenv("KAIZEN_TOOLS_DISABLE", "")
+	filter, err := newToolFilter(&serverConfig{DisableTools: []string{"sozo.*"}})
+	if err != nil {
+		t.Fatalf("newToolFilter: %v", err)
+	}
+	if filter.allows("sozo.generate") || !filter.allows("akuma.query") {
+		t.Fatalf("unexpected filter decisions")
+	}
+}
+
+func TestToolFilterRejectsMalformedPatterns(t *testing.T) {
+	t.Setenv("KAIZEN_TOOLS_ENABLE", "enzan.[")
+	if _, err := newToolFilter(&serverConfig{}); err == nil || !strings.Contains(err.Error(), "invalid tool pattern") {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// ToolCall is one tools/call as seen by tool middleware, after alias
+// resolution. Middleware may rewrite Arguments before calling next.
+type ToolCall struct {
+	// Name is the canonical tool name.
+	Name      string
+	Arguments map[string]interface{}
+	// Mutating reports whether the tool changes backend state.
+	Mutating bool
+
+	tool          registeredTool
+	progressToken interface{}
+}
+
+// ToolFunc runs one tool call and returns its MCP result: content,
+// optional structuredContent, and isError for tool-level failures.
+type ToolFunc func(ctx context.Context, call ToolCall) map[string]interface{}
+
+// ToolMiddleware wraps tool execution so cross-cutting concerns (logging,
+// auth, quota, redaction) live in one place instead of in every handler.
+// A middleware may short-circuit by returning a result without calling
+// next, and may inspect or replace the result next returns.
+type ToolMiddleware func(next ToolFunc) ToolFunc
+
+// UseToolMiddleware appends middleware around every tool call. The first
+// middleware registered is the outermost one, and all of them run outside
+// the built-in read-only, profile, workspace, scope, validation,
+// credential, budget, and concurrency checks. Register middleware before
+// calling Serve; the chain is not safe to modify concurrently.
+func (s *Server) UseToolMiddleware(middleware ...ToolMiddleware) {
+	s.toolMiddleware = append(s.toolMiddleware, middleware...)
+}
+
+// callTool runs one resolved tool call through the middleware chain and
+// builds its MCP result. Every failure past lookup is a tool error
+// (isError) rather than a JSON-RPC error, so the model sees it.
+func (s *Server) callTool(ctx context.Context, tool registeredTool, params toolsCallParams) map[string]interface{} {
+	call := ToolCall{Name: params.Name, Arguments: params.Arguments, Mutating: tool.mutating, tool: tool}
+	if params.Meta != nil {
+		call.progressToken = params.Meta.ProgressToken
+	}
+	chain := append(append([]ToolMiddleware{s.assignRequestID, s.traceToolCall, s.warnSlowToolCalls, s.recordStats, s.recoverToolPanics}, s.toolMiddleware...),
+		s.redactToolErrors,
+		s.refuseInReadOnly,
+		s.routeProfile,
+		s.routeWorkspace,
+		s.requireScope,
+		s.checkArguments,
+		s.applyTimezone,
+		s.requireConfiguration,
+		s.guardBudget,
+		s.limitConcurrency,
+		s.pageLargeResults,
+		s.applyDryRun,
+		s.applyOutputFormat,
+		s.applyCurrency,
+	)
+	next := ToolFunc(s.invokeTool)
+	for i := len(chain) - 1; i >= 0; i-- {
+		next = chain[i](next)
+	}
+	return next(ctx, call)
+}
+
+func toolErrorResult(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": true,
+	}
+}
+
+func (s *Server) refuseInReadOnly(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if s.readOnly && call.Mutating {
+			return toolErrorResult(fmt.Sprintf("%s changes backend state and is disabled because the server is in read-only mode", call.Name))
+		}
+		return next(ctx, call)
+	}
+}
+
+// routeProfile pins the backend client named by the `profile` argument
+// and removes the argument.
+func (s *Server) routeProfile(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		client, args, err := s.selectProfile(call.Arguments)
+		if err != nil {
+			return toolErrorResult(err.Error())
+		}
+		call.Arguments = args
+		return next(withAPIClient(ctx, client), call)
+	}
+}
+
+func (s *Server) checkArguments(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if s.coerceArgs {
+			coerceArguments(call.tool.schema, call.Arguments)
+		}
+		if err := validateArguments(call.tool.schema, call.Arguments); err != nil {
+			return toolErrorResult(err.Error())
+		}
+		return next(ctx, call)
+	}
+}
+
+func (s *Server) limitConcurrency(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		release, err := s.slots.acquire(call.Name)
+		if err != nil {
+			return toolErrorResult(err.Error())
+		}
+		defer release()
+		return next(ctx, call)
+	}
+}
+
+func (s *Server) pageLargeResults(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		result := next(ctx, call)
+		if call.Name == resultPageTool || result["isError"] == true {
+			return result
+		}
+		return s.truncateResult(ctx, result)
+	}
+}
+
+func (s *Server) applyDryRun(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		if !call.tool.dryRun {
+			return next(ctx, call)
+		}
+		dryRun, args := takeDryRun(call.Arguments)
+		call.Arguments = args
+		if !dryRun {
+			return next(ctx, call)
+		}
+		ctx, dryRunCall := withDryRun(ctx)
+		result := next(ctx, call)
+		if result["isError"] == true {
+			return result
+		}
+		// Fail closed: only a backend acknowledgement makes this a dry run.
+		if !dryRunCall.acknowledged.Load() {
+			return toolErrorResult(fmt.Sprintf("%s was called as a dry run, but no backend response confirmed it; the result cannot be presented as a dry run", call.Name))
+		}
+		content, _ := result["content"].([]map[string]string)
+		result["content"] = append([]map[string]string{{"type": "text", "text": dryRunNotice}}, content...)
+		return result
+	}
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToolMiddlewareRunsInRegistrationOrder(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/usage": `{"calls":3}`,
+	})
+	defer cleanup()
+
+	var order []string
+	trace := func(label string) ToolMiddleware {
+		return func(next ToolFunc) ToolFunc {
+			return func(ctx context.Context, call ToolCall) map[string]interface{} {
+				order = append(order, label+">"+call.Name)
+				result := next(ctx, call)
+				order = append(order, label+"<")
+				return result
+			}
+		}
+	}
+	s.UseToolMiddleware(trace("outer"), trace("inner"))
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen.usage", Arguments: map[string]interface{}{}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+		t.Fatalf("rpc error: %+v", rpcErr)
+	}
+	want := "outer>kaizen.usage,inner>kaizen.usage,inner<,outer<"
+	if got := strings.Join(order, ","); got != want {
+		t.Fatalf("order = %s, want %s", got, want)
+	}
+	if len(captured) != 1 {
+		t.Fatalf("expected one backend call, got %+v", captured)
+	}
+}
+
+func TestToolMiddlewareCanShortCircuitAndRewrite(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"POST /v1/akuma/validate": `{"valid":true,"token":"secret"}`,
+	})
+	defer cleanup()
+
+	s.UseToolMiddleware(
+		// Deny mutating tools.
+		func(next ToolFunc) ToolFunc {
+			return func(ctx context.Context, call ToolCall) map[string]interface{} {
+				if call.Mutating {
+					return toolErrorResult("denied by policy")
+				}
+				return next(ctx, call)
+			}
+		},
+		// Default an argument and redact the structured result.
+		func(next ToolFunc) ToolFunc {
+			return func(ctx context.Context, call ToolCall) map[string]interface{} {
+				call.Arguments["dialect"] = "postgres"
+				result := next(ctx, call)
+				if data, ok := result["structuredContent"].(map[string]interface{}); ok {
+					result["structuredContent"] = s.redactor.value(data)
+				}
+				return result
+			}
+		},
+	)
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.schema", Arguments: map[string]interface{}{"dialect": "postgres", "tables": []interface{}{}}})
+	result, _ := s.handleToolCall(context.Background(), raw)
+	if content := result.(map[string]interface{})["content"].([]map[string]string); content[0]["text"] != "denied by policy" {
+		t.Fatalf("mutating call should be denied, got %#v", result)
+	}
+	if len(captured) != 0 {
+		t.Fatalf("denied call reached the backend: %+v", captured)
+	}
+
+	raw, _ = json.Marshal(toolsCallParams{Name: "akuma.validate", Arguments: map[string]interface{}{"sql": "select 1"}})
+	result, _ = s.handleToolCall(context.Background(), raw)
+	if len(captured) != 1 || !strings.Contains(captured[0].Body, `"dialect":"postgres"`) {
+		t.Fatalf("rewritten argument not forwarded: %+v", captured)
+	}
+	structured := result.(map[string]interface{})["structuredContent"].(map[string]interface{})
+	if structured["token"] != redactedValue {
+		t.Fatalf("token = %#v, want redacted", structured["token"])
+	}
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// toolNamePattern bounds tool names and their prefix to characters every
+// MCP client accepts.
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func validateToolPrefix(prefix string) error {
+	if prefix != "" && !toolNamePattern.MatchString(prefix) {
+		return fmt.Errorf("invalid KAIZEN_TOOLS_PREFIX %q: use letters, digits, '.', '_', and '-' only", prefix)
+	}
+	return nil
+}
+
+// prefixToolNames puts the KAIZEN_TOOLS_PREFIX namespace in front of every
+// listed name, so several instances of this server can be attached to one
+// client without their tools colliding.
+func (s *Server) prefixToolNames(defs []toolDefinition) []toolDefinition {
+	if s.toolPrefix == "" {
+		return defs
+	}
+	prefixed := make([]toolDefinition, len(defs))
+	for i, def := range defs {
+		prefixed[i] = def
+		prefixed[i].Name = s.toolPrefix + def.Name
+	}
+	return prefixed
+}
+
+// unprefixedToolName strips the namespace prefix from a called name. Bare
+// names are accepted too, like aliases.
+func (s *Server) unprefixedToolName(name string) string {
+	if s.toolPrefix == "" {
+		return name
+	}
+	if trimmed, ok := strings.CutPrefix(name, s.toolPrefix); ok {
+		return trimmed
+	}
+	return name
+}
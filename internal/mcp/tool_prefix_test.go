@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToolPrefixNamespacesListedAndCalledTools(t *testing.T) {
+	var captured []capturedRequest
+	s, cleanup := newPricingTestServer(t, &captured, map[string]string{
+		"GET /v1/enzan/burn": `{"burnRateUSDPerHour":12}`,
+	})
+	defer cleanup()
+	s.toolPrefix = "kaizen-prod."
+
+	resp := s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	for _, def := range resp.Result.(map[string]interface{})["tools"].([]toolDefinition) {
+		if !strings.HasPrefix(def.Name, "kaizen-prod.") {
+			t.Fatalf("%s listed without the prefix", def.Name)
+		}
+	}
+	if tool, _ := builtinTools.lookup("enzan.burn"); tool.def.Name != "enzan.burn" {
+		t.Fatalf("prefix leaked into the shared registry: %s", tool.def.Name)
+	}
+
+	for _, name := range []string{"kaizen-prod.enzan.burn", "enzan.burn"} {
+		raw, _ := json.Marshal(toolsCallParams{Name: name, Arguments: map[string]interface{}{}})
+		if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr != nil {
+			t.Fatalf("%s: rpc error: %+v", name, rpcErr)
+		}
+	}
+	if len(captured) != 2 {
+		t.Fatalf("expected two backend calls, got %+v", captured)
+	}
+	raw, _ := json.Marshal(toolsCallParams{Name: "kaizen-staging.enzan.burn", Arguments: map[string]interface{}{}})
+	if _, rpcErr := s.handleToolCall(context.Background(), raw); rpcErr == nil {
+		t.Fatalf("another instance's prefix should be unknown")
+	}
+}
+
+func TestValidateToolPrefix(t *testing.T) {
+	for prefix, ok := range map[string]bool{"": true, "kaizen-prod.": true, "prod_": true, "prod/": false, "a b": false} {
+		if err := validateToolPrefix(prefix); (err == nil) != ok {
+			t.Fatalf("validateToolPrefix(%q) = %v", prefix, err)
+		}
+	}
+}
+
+func TestToolPrefixAppliesToNoticesAndFooters(t *testing.T) {
+	registry := newToolRegistry()
+	registry.register("test.old", toolDefinition{
+		Description: "Old echo.",
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+	}, func(_ context.Context, _ *Server, _ map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"text": strings.Repeat("x", 64)}, nil
+	}, deprecated("test.new"))
+	s := &Server{client: &kaizenAPIClient{apiKey: "k"}, tools: registry, toolPrefix: "kaizen-prod.", resultMaxBytes: 32}
+
+	want := "Deprecated: kaizen-prod.test.old will be removed; use kaizen-prod.test.new instead."
+	if description := s.listTools()[0].Description; !strings.HasPrefix(description, want) {
+		t.Fatalf("description = %q", description)
+	}
+	result, _ := s.handleToolCall(context.Background(), json.RawMessage(`{"name":"kaizen-prod.test.old","arguments":{}}`))
+	content := result.(map[string]interface{})["content"].([]map[string]string)
+	if content[0]["text"] != want || !strings.Contains(content[len(content)-1]["text"], "Call kaizen-prod."+resultPageTool+" ") {
+		t.Fatalf("notice and footer should use the prefix: %#v", content)
+	}
+}
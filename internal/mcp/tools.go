@@ -1,455 +1,1064 @@
 package mcp
 
-func toolDefinitions() []toolDefinition {
-	return []toolDefinition{
-		{
-			Name:        "akuma.query",
-			Description: "Translate natural language into SQL (optionally returning rows or explanation).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"dialect":    map[string]interface{}{"type": "string", "enum": []string{"postgres", "mysql", "snowflake", "bigquery"}},
-					"prompt":     map[string]interface{}{"type": "string"},
-					"mode":       map[string]interface{}{"type": "string", "enum": []string{"sql-only", "sql-and-results", "explain"}},
-					"maxRows":    map[string]interface{}{"type": "number"},
-					"sourceId":   map[string]interface{}{"type": "string"},
-					"guardrails": map[string]interface{}{"type": "object"},
-				},
-				"required":             []string{"dialect", "prompt"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "akuma.query_interactive",
-			Description: "Translate natural language into SQL using the interactive Akuma protocol. Returns a status envelope; non-completed statuses such as rejected surface as MCP tool errors with the full envelope in structuredContent.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"dialect":    map[string]interface{}{"type": "string", "enum": []string{"postgres", "mysql", "snowflake", "bigquery"}},
-					"prompt":     map[string]interface{}{"type": "string"},
-					"mode":       map[string]interface{}{"type": "string", "enum": []string{"sql-only", "sql-and-results", "explain"}},
-					"maxRows":    map[string]interface{}{"type": "number"},
-					"sourceId":   map[string]interface{}{"type": "string"},
-					"guardrails": map[string]interface{}{"type": "object"},
-				},
-				"required":             []string{"dialect", "prompt"},
-				"additionalProperties": false,
+import "github.com/kaizen-ai-systems/mcp-server/internal/kaizen"
+
+var akumaDialects = []string{"postgres", "mysql", "snowflake", "bigquery"}
+
+// connectionNamePattern admits names of server-side connections only, so a
+// DSN or URL with embedded credentials can't be passed through.
+const connectionNamePattern = `^[A-Za-z0-9][A-Za-z0-9_.-]*$`
+
+// presetNamePattern keeps custom Sozo preset names safe to use as a path
+// segment and distinct from free text.
+const presetNamePattern = `^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`
+
+// sozo.preview is meant to stay cheap; larger samples go through
+// sozo.generate.
+const (
+	sozoPreviewDefaultRecords = 5
+	sozoPreviewMaxRecords     = 10
+)
+
+// sozoSampleMaxRecords bounds the real records sozo.anonymize and
+// sozo.augment accept: they work from samples, and real data should not
+// be shipped around in bulk.
+const sozoSampleMaxRecords = 500
+
+// sozoCorrelationMaxRecords bounds the inline records sozo.correlations
+// accepts; generated datasets larger than that are checked by jobId.
+const sozoCorrelationMaxRecords = 10000
+
+// registerBuiltinTools declares every Kaizen tool together with its
+// handler so tools/list and tools/call cannot drift apart. Tools that
+// change backend state are marked mutating() so read-only mode hides them.
+func registerBuiltinTools(r *Registry) {
+	r.register("akuma.query", toolDefinition{
+		Description: "Translate natural language into SQL (optionally returning rows or explanation).",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dialect":    map[string]interface{}{"type": "string", "enum": []string{"postgres", "mysql", "snowflake", "bigquery"}},
+				"prompt":     map[string]interface{}{"type": "string"},
+				"mode":       map[string]interface{}{"type": "string", "enum": []string{"sql-only", "sql-and-results", "explain"}},
+				"maxRows":    map[string]interface{}{"type": "number", "description": "Most rows to return; extra rows are dropped and the result gets truncated: true and originalRowCount"},
+				"sourceId":   map[string]interface{}{"type": "string"},
+				"guardrails": map[string]interface{}{"type": "object"},
 			},
+			"required":             []string{"dialect", "prompt"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "akuma.explain",
-			Description: "Explain a SQL query in plain English.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"sql": map[string]interface{}{"type": "string"},
-				},
-				"required":             []string{"sql"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "akuma.schema",
-			Description: "Set Akuma schema context used for query generation.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"sourceId": map[string]interface{}{"type": "string"},
-					"name":     map[string]interface{}{"type": "string"},
-					"dialect":  map[string]interface{}{"type": "string", "enum": []string{"postgres", "mysql", "snowflake", "bigquery"}},
-					"version":  map[string]interface{}{"type": "string"},
-					"tables":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
-				},
-				"required":             []string{"dialect", "tables"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.summary",
-			Description: "Summarize GPU spend and usage for a time window.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"window":  map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
-					"groupBy": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
-				},
-				"additionalProperties": false,
+	}, serverMethod((*Server).callAkumaQuery), budgetGuarded(returnsRows), tabular())
+	r.register("akuma.query_interactive", toolDefinition{
+		Description: "Translate natural language into SQL using the interactive Akuma protocol. Returns a status envelope; non-completed statuses such as rejected surface as MCP tool errors with the full envelope in structuredContent.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dialect":    map[string]interface{}{"type": "string", "enum": []string{"postgres", "mysql", "snowflake", "bigquery"}},
+				"prompt":     map[string]interface{}{"type": "string"},
+				"mode":       map[string]interface{}{"type": "string", "enum": []string{"sql-only", "sql-and-results", "explain"}},
+				"maxRows":    map[string]interface{}{"type": "number", "description": "Most rows to return; extra rows are dropped and the result gets truncated: true and originalRowCount"},
+				"sourceId":   map[string]interface{}{"type": "string"},
+				"guardrails": map[string]interface{}{"type": "object"},
 			},
+			"required":             []string{"dialect", "prompt"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.costs_by_model",
-			Description: "Break down Akuma API spend by model for a time window.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
-				},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.routing",
-			Description: "Get the current Enzan smart-routing config.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.set_routing",
-			Description: "Upsert the current Enzan smart-routing config.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"enabled":        map[string]interface{}{"type": "boolean"},
-					"simple_model":   map[string]interface{}{"type": "string"},
-					"moderate_model": map[string]interface{}{"type": "string"},
-					"complex_model":  map[string]interface{}{"type": "string"},
+	}, serverMethod((*Server).callAkumaQueryInteractive), budgetGuarded(returnsRows), sharesCeilingWith("akuma.query"), tabular())
+	r.register("akuma.batch", toolDefinition{
+		Description: "Run several akuma.query prompts concurrently with shared settings, e.g. the queries behind a dashboard. Returns one entry per prompt, in order, with either its result or its error; one failed prompt does not fail the batch.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dialect": map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"prompts": map[string]interface{}{
+					"type":     "array",
+					"items":    map[string]interface{}{"type": "string"},
+					"minItems": 1,
+					"maxItems": akumaBatchMaxPrompts,
 				},
-				"required":             []string{"enabled"},
-				"additionalProperties": false,
+				"mode":       map[string]interface{}{"type": "string", "enum": []string{"sql-only", "sql-and-results", "explain"}},
+				"maxRows":    map[string]interface{}{"type": "number", "description": "Most rows to return per prompt; extra rows are dropped and the result gets truncated: true and originalRowCount"},
+				"sourceId":   map[string]interface{}{"type": "string"},
+				"guardrails": map[string]interface{}{"type": "object"},
+			},
+			"required":             []string{"dialect", "prompts"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaBatch), budgetGuarded(returnsRows), sharesCeilingWith("akuma.query"), pricedAs(akumaBatchEstimates))
+	r.register("akuma.explain", toolDefinition{
+		Description: "Explain a SQL query in plain English. Set format to ascii or mermaid to also get the query plan rendered as an ASCII tree or a Mermaid flowchart.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":    map[string]interface{}{"type": "string"},
+				"format": map[string]interface{}{"type": "string", "enum": []string{"prose", "ascii", "mermaid"}},
+			},
+			"required":             []string{"sql"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaExplain), withTextRenderer(renderAkumaPlan))
+	r.register("akuma.schema", toolDefinition{
+		Description: "Set Akuma schema context used for query generation.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sourceId": map[string]interface{}{"type": "string"},
+				"name":     map[string]interface{}{"type": "string"},
+				"dialect":  map[string]interface{}{"type": "string", "enum": []string{"postgres", "mysql", "snowflake", "bigquery"}},
+				"version":  map[string]interface{}{"type": "string"},
+				"tables":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+			},
+			"required":             []string{"dialect", "tables"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaSchema), mutating(), dryRunnable())
+	r.register("akuma.schema.get", toolDefinition{
+		Description: "Read the schema context Akuma currently uses for query generation: the active schema version, dialect, and table definitions. Omit sourceId for the default source.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sourceId": map[string]interface{}{"type": "string"},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaSchemaGet))
+	r.register("akuma.schema.introspect", toolDefinition{
+		Description: "Introspect tables from a live database through a connection configured on the Kaizen server, then register them as Akuma schema context in one step. connection is the name of that server-side connection; raw credentials and connection strings are never accepted.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"connection": map[string]interface{}{"type": "string", "pattern": connectionNamePattern, "description": "Name of a server-side connection, e.g. warehouse-prod"},
+				"sourceId":   map[string]interface{}{"type": "string"},
+				"schemas":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"tables":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			"required":             []string{"connection"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaSchemaIntrospect), mutating())
+	r.register("akuma.validate", toolDefinition{
+		Description: "Check SQL against the registered schema before execution. Returns structured diagnostics (unknown tables or columns, type mismatches, dialect violations) with locations so the query can be fixed first.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":      map[string]interface{}{"type": "string"},
+				"dialect":  map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"sourceId": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"sql"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaValidate))
+	r.register("akuma.optimize", toolDefinition{
+		Description: "Rewrite SQL for performance. Returns the optimized query, the list of rewrites applied, and the estimated improvement. The original query is not executed.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":      map[string]interface{}{"type": "string"},
+				"dialect":  map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"sourceId": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"sql"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaOptimize))
+	r.register("akuma.lint", toolDefinition{
+		Description: "Lint SQL for style and safety issues such as SELECT *, a missing WHERE on large tables, or non-sargable predicates. Returns findings with severities and locations.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":      map[string]interface{}{"type": "string"},
+				"dialect":  map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"sourceId": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"sql"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaLint))
+	r.register("akuma.history", toolDefinition{
+		Description: "List recent Akuma queries generated with the current API key, newest first, so prior SQL can be reused instead of regenerated. Filter by status and an RFC 3339 time range, and pass the returned nextCursor back as cursor to page.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"status": map[string]interface{}{"type": "string", "enum": []string{"completed", "rejected", "failed"}},
+				"since":  map[string]interface{}{"type": "string", "description": "RFC 3339 timestamp, inclusive"},
+				"until":  map[string]interface{}{"type": "string", "description": "RFC 3339 timestamp, exclusive"},
+				"limit":  map[string]interface{}{"type": "integer", "minimum": 1},
+				"cursor": map[string]interface{}{"type": "string"},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaHistory))
+	r.register("akuma.diff", toolDefinition{
+		Description: "Compare two SQL statements semantically. Returns the changes to joins, filters, projections, grouping, and ordering, rather than a text diff.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"before":  map[string]interface{}{"type": "string"},
+				"after":   map[string]interface{}{"type": "string"},
+				"dialect": map[string]interface{}{"type": "string", "enum": akumaDialects},
+			},
+			"required":             []string{"before", "after"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaDiff))
+	r.register("akuma.preview", toolDefinition{
+		Description: "Sample a few rows from a table to ground SQL generation in real values. The server caps the row count and applies column masking policies, so masked columns come back redacted.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"table":    map[string]interface{}{"type": "string", "description": "Table name, optionally schema-qualified"},
+				"sourceId": map[string]interface{}{"type": "string"},
+				"columns":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"limit":    map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"required":             []string{"table"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaPreview), tabular())
+	r.register("akuma.convert", toolDefinition{
+		Description: "Translate a SQL statement from one supported dialect to another, e.g. when porting queries during a warehouse migration. Returns the converted SQL and notes on constructs that could not be translated exactly.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":  map[string]interface{}{"type": "string"},
+				"from": map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"to":   map[string]interface{}{"type": "string", "enum": akumaDialects},
+			},
+			"required":             []string{"sql", "from", "to"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaConvert))
+	r.register("akuma.cost", toolDefinition{
+		Description: "Estimate the warehouse cost of a SQL statement without running it, using a BigQuery dry run or a Snowflake estimate. Returns the bytes scanned and the estimated cost so expensive queries can be flagged first.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":      map[string]interface{}{"type": "string"},
+				"dialect":  map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"sourceId": map[string]interface{}{"type": "string"},
 			},
+			"required":             []string{"sql"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.routing_savings",
-			Description: "Get realized Enzan smart-routing savings for a time window.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+	}, serverMethod((*Server).callAkumaCost))
+	r.register("akuma.generate_and_check", toolDefinition{
+		Description: "Translate natural language into SQL, then validate it against the registered schema and estimate its warehouse cost, in one call. Returns the SQL with the validation diagnostics and the cost estimate; a check that fails is reported under its own key instead of failing the call.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dialect":    map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"prompt":     map[string]interface{}{"type": "string"},
+				"sourceId":   map[string]interface{}{"type": "string"},
+				"guardrails": map[string]interface{}{"type": "object"},
+			},
+			"required":             []string{"dialect", "prompt"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaGenerateAndCheck))
+	r.register("akuma.format", toolDefinition{
+		Description: "Pretty-print SQL for the chosen dialect: one clause per line, one select item and one AND/OR condition per line, indented subqueries, and consistent keyword case. Runs locally without calling the Kaizen API, so it works even when the backend is unreachable. It does not check that the SQL is valid; use akuma.validate for that.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"sql":         map[string]interface{}{"type": "string"},
+				"dialect":     map[string]interface{}{"type": "string", "enum": akumaDialects},
+				"keywordCase": map[string]interface{}{"type": "string", "enum": []string{"upper", "lower", "preserve"}},
+				"indent":      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 8},
+			},
+			"required":             []string{"sql"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaFormat), withTextRenderer(renderAkumaFormat), offline())
+	r.register("akuma.glossary", toolDefinition{
+		Description: "Look up business terms in the Akuma glossary, e.g. \"ARR\" or \"active customer\". Each entry has the term's definition and the tables, columns, and filter expression it maps to. Resolve business vocabulary here before generating SQL so the query uses the agreed definition. Omit term to list the whole glossary.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"term":     map[string]interface{}{"type": "string", "description": "Term or synonym to look up; matching is case-insensitive"},
+				"sourceId": map[string]interface{}{"type": "string"},
+				"limit":    map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).AkumaGlossary))
+	r.register("akuma.feedback", toolDefinition{
+		Description: "Submit feedback on a generated query so Akuma can learn from it. queryId is the id returned with the query (see akuma.history). Rate it up or down, and when the user fixed the SQL, pass their version as correction; a comment can say what was wrong.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"queryId":    map[string]interface{}{"type": "string"},
+				"rating":     map[string]interface{}{"type": "string", "enum": []string{"up", "down"}},
+				"correction": map[string]interface{}{"type": "string", "description": "Corrected SQL, as the user edited it"},
+				"comment":    map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"queryId", "rating"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callAkumaFeedback), mutating())
+	r.register("enzan.summary", toolDefinition{
+		Description: "Summarize GPU spend and usage for a time window.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window":  map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+				"groupBy": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanSummary), zoned(), costReporting())
+	r.register("enzan.costs_by_model", toolDefinition{
+		Description: "Break down Akuma API spend by model for a time window.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanCostsByModel), costReporting())
+	r.register("enzan.routing", toolDefinition{
+		Description: "Get the current Enzan smart-routing config.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanRouting))
+	r.register("enzan.set_routing", toolDefinition{
+		Description: "Upsert the current Enzan smart-routing config.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"enabled":        map[string]interface{}{"type": "boolean"},
+				"simple_model":   map[string]interface{}{"type": "string"},
+				"moderate_model": map[string]interface{}{"type": "string"},
+				"complex_model":  map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"enabled"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanSetRouting), mutating(), dryRunnable())
+	r.register("enzan.routing_savings", toolDefinition{
+		Description: "Get realized Enzan smart-routing savings for a time window.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanRoutingSavings), costReporting())
+	r.register("enzan.pricing_models", toolDefinition{
+		Description: "List configured LLM pricing entries.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanModelPricing))
+	r.register("enzan.set_model_pricing", toolDefinition{
+		Description: "Upsert one LLM pricing entry (admin API key required).",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"provider":                      map[string]interface{}{"type": "string"},
+				"model":                         map[string]interface{}{"type": "string"},
+				"display_name":                  map[string]interface{}{"type": "string"},
+				"input_cost_per_1k_tokens_usd":  map[string]interface{}{"type": "number"},
+				"output_cost_per_1k_tokens_usd": map[string]interface{}{"type": "number"},
+				"currency":                      map[string]interface{}{"type": "string"},
+				"active":                        map[string]interface{}{"type": "boolean"},
+			},
+			"required":             []string{"provider", "model", "input_cost_per_1k_tokens_usd", "output_cost_per_1k_tokens_usd"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanSetModelPricing), mutating())
+	r.register("enzan.pricing_gpus", toolDefinition{
+		Description: "List configured GPU pricing entries.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanGPUPricing))
+	r.register("enzan.set_gpu_pricing", toolDefinition{
+		Description: "Upsert one GPU pricing entry (admin API key required).",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"provider":        map[string]interface{}{"type": "string"},
+				"gpu_type":        map[string]interface{}{"type": "string"},
+				"display_name":    map[string]interface{}{"type": "string"},
+				"hourly_rate_usd": map[string]interface{}{"type": "number"},
+				"currency":        map[string]interface{}{"type": "string"},
+				"active":          map[string]interface{}{"type": "boolean"},
+			},
+			"required":             []string{"provider", "gpu_type", "hourly_rate_usd"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanSetGPUPricing), mutating())
+	r.register("enzan.pricing_refresh_trigger", toolDefinition{
+		Description: "Trigger an on-demand live-pricing refresh sweep (admin enzan_pricing_admin required). Fire-and-forget; poll enzan.pricing_refresh_log for status.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanPricingRefreshTrigger), mutating())
+	r.register("enzan.pricing_refresh_log", toolDefinition{
+		Description: "List recent live-pricing refresh-log entries (admin enzan_pricing_admin required). Default 50; server clamps to 1..200 and rejects non-positive values with 400. Limit is forwarded verbatim so the server remains the clamp/validation authority.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{"type": "integer"},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanPricingRefreshLog))
+	r.register("enzan.pricing_providers", toolDefinition{
+		Description: "List registered live-pricing sources with adapter availability hints (admin enzan_pricing_admin required).",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanPricingProviders))
+	r.register("enzan.pricing_offers_upsert", toolDefinition{
+		Description: "Upsert one manual (admin-authored) live-pricing offer; exactly one of gpu or llm must be set (admin enzan_pricing_admin required).",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"gpu": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"provider":          map[string]interface{}{"type": "string"},
+						"gpuType":           map[string]interface{}{"type": "string"},
+						"displayName":       map[string]interface{}{"type": "string"},
+						"region":            map[string]interface{}{"type": "string"},
+						"deploymentClass":   map[string]interface{}{"type": "string", "enum": []string{"on_demand", "reserved", "spot", "committed_monthly"}},
+						"commitmentTerm":    map[string]interface{}{"type": "string"},
+						"clusterSizeMin":    map[string]interface{}{"type": "integer"},
+						"clusterSizeMax":    map[string]interface{}{"type": "integer"},
+						"interconnectClass": map[string]interface{}{"type": "string", "enum": []string{"standard", "high_speed", "infiniband", "nvlink", "unknown"}},
+						"trainingReady":     map[string]interface{}{"type": "boolean"},
+						"hourlyRateUSD":     map[string]interface{}{"type": "number", "minimum": 0},
+						"currency":          map[string]interface{}{"type": "string"},
+						"currencyFxNote":    map[string]interface{}{"type": "string"},
+						"sourceUrl":         map[string]interface{}{"type": "string"},
+					},
+					"required":             []string{"provider", "gpuType", "displayName", "hourlyRateUSD"},
+					"additionalProperties": false,
 				},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.pricing_models",
-			Description: "List configured LLM pricing entries.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.set_model_pricing",
-			Description: "Upsert one LLM pricing entry (admin API key required).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"provider":                      map[string]interface{}{"type": "string"},
-					"model":                         map[string]interface{}{"type": "string"},
-					"display_name":                  map[string]interface{}{"type": "string"},
-					"input_cost_per_1k_tokens_usd":  map[string]interface{}{"type": "number"},
-					"output_cost_per_1k_tokens_usd": map[string]interface{}{"type": "number"},
-					"currency":                      map[string]interface{}{"type": "string"},
-					"active":                        map[string]interface{}{"type": "boolean"},
+				"llm": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"provider":                 map[string]interface{}{"type": "string"},
+						"model":                    map[string]interface{}{"type": "string"},
+						"displayName":              map[string]interface{}{"type": "string"},
+						"region":                   map[string]interface{}{"type": "string"},
+						"commitmentTerm":           map[string]interface{}{"type": "string"},
+						"inputCostPer1KTokensUSD":  map[string]interface{}{"type": "number", "minimum": 0},
+						"outputCostPer1KTokensUSD": map[string]interface{}{"type": "number", "minimum": 0},
+						"currency":                 map[string]interface{}{"type": "string"},
+						"currencyFxNote":           map[string]interface{}{"type": "string"},
+						"sourceUrl":                map[string]interface{}{"type": "string"},
+					},
+					"required":             []string{"provider", "model", "displayName", "inputCostPer1KTokensUSD", "outputCostPer1KTokensUSD"},
+					"additionalProperties": false,
 				},
-				"required":             []string{"provider", "model", "input_cost_per_1k_tokens_usd", "output_cost_per_1k_tokens_usd"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.pricing_gpus",
-			Description: "List configured GPU pricing entries.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.set_gpu_pricing",
-			Description: "Upsert one GPU pricing entry (admin API key required).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"provider":        map[string]interface{}{"type": "string"},
-					"gpu_type":        map[string]interface{}{"type": "string"},
-					"display_name":    map[string]interface{}{"type": "string"},
-					"hourly_rate_usd": map[string]interface{}{"type": "number"},
-					"currency":        map[string]interface{}{"type": "string"},
-					"active":          map[string]interface{}{"type": "boolean"},
+			},
+			// JSON-Schema-level "exactly one of gpu or llm" enforcement so
+			// generated clients reject invalid payloads at the contract
+			// layer rather than the runtime 400 path.
+			"oneOf": []map[string]interface{}{
+				{"required": []string{"gpu"}, "not": map[string]interface{}{"required": []string{"llm"}}},
+				{"required": []string{"llm"}, "not": map[string]interface{}{"required": []string{"gpu"}}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanPricingOffersUpsert), mutating())
+	r.register("enzan.optimize", toolDefinition{
+		Description: "Generate cost optimization recommendations for a time window.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanOptimize), costReporting())
+	r.register("enzan.alerts", toolDefinition{
+		Description: "List configured Enzan alert rules.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanAlerts))
+	r.register("enzan.create_alert", toolDefinition{
+		Description: "Create one Enzan alert rule.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":        map[string]interface{}{"type": "string"},
+				"name":      map[string]interface{}{"type": "string"},
+				"type":      map[string]interface{}{"type": "string", "enum": []string{"cost_threshold", "cost_anomaly", "budget_exceeded", "optimization_available", "pricing_change", "daily_summary"}},
+				"threshold": map[string]interface{}{"type": "number"},
+				"window":    map[string]interface{}{"type": "string"},
+				"labels": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
 				},
-				"required":             []string{"provider", "gpu_type", "hourly_rate_usd"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.pricing_refresh_trigger",
-			Description: "Trigger an on-demand live-pricing refresh sweep (admin enzan_pricing_admin required). Fire-and-forget; poll enzan.pricing_refresh_log for status.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.pricing_refresh_log",
-			Description: "List recent live-pricing refresh-log entries (admin enzan_pricing_admin required). Default 50; server clamps to 1..200 and rejects non-positive values with 400. Limit is forwarded verbatim so the server remains the clamp/validation authority.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"limit": map[string]interface{}{"type": "integer"},
+				"enabled": map[string]interface{}{"type": "boolean"},
+			},
+			"required":             []string{"name", "type"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanCreateAlert), mutating())
+	r.register("enzan.update_alert", toolDefinition{
+		Description: "Update one Enzan alert rule by id.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":        map[string]interface{}{"type": "string"},
+				"name":      map[string]interface{}{"type": "string"},
+				"threshold": map[string]interface{}{"type": "number"},
+				"window":    map[string]interface{}{"type": "string"},
+				"labels": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
 				},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.pricing_providers",
-			Description: "List registered live-pricing sources with adapter availability hints (admin enzan_pricing_admin required).",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.pricing_offers_upsert",
-			Description: "Upsert one manual (admin-authored) live-pricing offer; exactly one of gpu or llm must be set (admin enzan_pricing_admin required).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"gpu": map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"provider":          map[string]interface{}{"type": "string"},
-							"gpuType":           map[string]interface{}{"type": "string"},
-							"displayName":       map[string]interface{}{"type": "string"},
-							"region":            map[string]interface{}{"type": "string"},
-							"deploymentClass":   map[string]interface{}{"type": "string", "enum": []string{"on_demand", "reserved", "spot", "committed_monthly"}},
-							"commitmentTerm":    map[string]interface{}{"type": "string"},
-							"clusterSizeMin":    map[string]interface{}{"type": "integer"},
-							"clusterSizeMax":    map[string]interface{}{"type": "integer"},
-							"interconnectClass": map[string]interface{}{"type": "string", "enum": []string{"standard", "high_speed", "infiniband", "nvlink", "unknown"}},
-							"trainingReady":     map[string]interface{}{"type": "boolean"},
-							"hourlyRateUSD":     map[string]interface{}{"type": "number", "minimum": 0},
-							"currency":          map[string]interface{}{"type": "string"},
-							"currencyFxNote":    map[string]interface{}{"type": "string"},
-							"sourceUrl":         map[string]interface{}{"type": "string"},
-						},
-						"required":             []string{"provider", "gpuType", "displayName", "hourlyRateUSD"},
-						"additionalProperties": false,
-					},
-					"llm": map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"provider":                 map[string]interface{}{"type": "string"},
-							"model":                    map[string]interface{}{"type": "string"},
-							"displayName":              map[string]interface{}{"type": "string"},
-							"region":                   map[string]interface{}{"type": "string"},
-							"commitmentTerm":           map[string]interface{}{"type": "string"},
-							"inputCostPer1KTokensUSD":  map[string]interface{}{"type": "number", "minimum": 0},
-							"outputCostPer1KTokensUSD": map[string]interface{}{"type": "number", "minimum": 0},
-							"currency":                 map[string]interface{}{"type": "string"},
-							"currencyFxNote":           map[string]interface{}{"type": "string"},
-							"sourceUrl":                map[string]interface{}{"type": "string"},
+				"enabled": map[string]interface{}{"type": "boolean"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanUpdateAlert), mutating())
+	r.register("enzan.delete_alert", toolDefinition{
+		Description: "Delete one Enzan alert rule by id.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanDeleteAlert), mutating())
+	r.register("enzan.alert_events", toolDefinition{
+		Description: "List recent Enzan alert events: threshold breaches and runaway jobs that fired, each with its status, the rule that triggered it, and the affected resources. Use it to answer why someone was paged about GPU spend. Filter by status (active or resolved) and window.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit":  map[string]interface{}{"type": "number"},
+				"status": map[string]interface{}{"type": "string", "enum": []string{"active", "resolved"}},
+				"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanAlertEvents))
+	r.register("enzan.alert_deliveries", toolDefinition{
+		Description: "List recent Enzan alert deliveries.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{"type": "number"},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanAlertDeliveries))
+	r.register("enzan.alert_endpoints", toolDefinition{
+		Description: "List configured Enzan alert delivery webhook endpoints.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanAlertEndpoints))
+	r.register("enzan.create_alert_endpoint", toolDefinition{
+		Description: "Create one Enzan alert delivery webhook endpoint.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"targetUrl":     map[string]interface{}{"type": "string"},
+				"signingSecret": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"targetUrl"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanCreateAlertEndpoint), mutating())
+	r.register("enzan.update_alert_endpoint", toolDefinition{
+		Description: "Update one Enzan alert delivery webhook endpoint by id.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":            map[string]interface{}{"type": "string"},
+				"targetUrl":     map[string]interface{}{"type": "string"},
+				"signingSecret": map[string]interface{}{"type": "string"},
+				"enabled":       map[string]interface{}{"type": "boolean"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanUpdateAlertEndpoint), mutating())
+	r.register("enzan.delete_alert_endpoint", toolDefinition{
+		Description: "Delete one Enzan alert delivery webhook endpoint by id.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanDeleteAlertEndpoint), mutating())
+	r.register("enzan.chat", toolDefinition{
+		Description: "Ask a question about your GPU and API costs. Supports multi-turn conversations with optional time window.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"message":        map[string]interface{}{"type": "string", "description": "Your question about costs"},
+				"conversationId": map[string]interface{}{"type": "string", "description": "Optional conversation ID for follow-ups"},
+				"window":         map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}, "description": "Optional time window; inferred from message if omitted"},
+			},
+			"required":             []string{"message"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanChat))
+	r.register("enzan.burn", toolDefinition{
+		Description: "Get current burn rate in USD/hour.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanBurn), costReporting())
+	r.register("enzan.forecast", toolDefinition{
+		Description: "Project GPU spend over the next 7, 30, or 90 days from current trends. Returns the projected total and a daily series with lower and upper confidence bounds.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"horizon": map[string]interface{}{"type": "string", "enum": []string{"7d", "30d", "90d"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanForecast), costReporting())
+	r.register("enzan.budgets", toolDefinition{
+		Description: "List configured Enzan spend budgets with their amount, scope, period, and current consumption.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).EnzanBudgets), costReporting())
+	r.register("enzan.set_budget", toolDefinition{
+		Description: "Create a spend budget, or update one by id. Creating requires amountUSD and period; an update changes only the fields given. scope limits the budget to a project, team, cluster, or set of labels.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":        map[string]interface{}{"type": "string"},
+				"name":      map[string]interface{}{"type": "string"},
+				"amountUSD": map[string]interface{}{"type": "number", "minimum": 0},
+				"period":    map[string]interface{}{"type": "string", "enum": []string{"monthly", "quarterly", "yearly"}},
+				"scope": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"project": map[string]interface{}{"type": "string"},
+						"team":    map[string]interface{}{"type": "string"},
+						"cluster": map[string]interface{}{"type": "string"},
+						"labels": map[string]interface{}{
+							"type":                 "object",
+							"additionalProperties": map[string]interface{}{"type": "string"},
 						},
-						"required":             []string{"provider", "model", "displayName", "inputCostPer1KTokensUSD", "outputCostPer1KTokensUSD"},
-						"additionalProperties": false,
 					},
+					"additionalProperties": false,
 				},
-				// JSON-Schema-level "exactly one of gpu or llm" enforcement so
-				// generated clients reject invalid payloads at the contract
-				// layer rather than the runtime 400 path.
-				"oneOf": []map[string]interface{}{
-					{"required": []string{"gpu"}, "not": map[string]interface{}{"required": []string{"llm"}}},
-					{"required": []string{"llm"}, "not": map[string]interface{}{"required": []string{"gpu"}}},
-				},
-				"additionalProperties": false,
 			},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.optimize",
-			Description: "Generate cost optimization recommendations for a time window.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"window": map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
-				},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.alerts",
-			Description: "List configured Enzan alert rules.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.create_alert",
-			Description: "Create one Enzan alert rule.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"id":        map[string]interface{}{"type": "string"},
-					"name":      map[string]interface{}{"type": "string"},
-					"type":      map[string]interface{}{"type": "string", "enum": []string{"cost_threshold", "cost_anomaly", "budget_exceeded", "optimization_available", "pricing_change", "daily_summary"}},
-					"threshold": map[string]interface{}{"type": "number"},
-					"window":    map[string]interface{}{"type": "string"},
-					"labels": map[string]interface{}{
-						"type":                 "object",
-						"additionalProperties": map[string]interface{}{"type": "string"},
-					},
-					"enabled": map[string]interface{}{"type": "boolean"},
+	}, serverMethod((*Server).callEnzanSetBudget), mutating(), dryRunnable())
+	r.register("enzan.anomalies", toolDefinition{
+		Description: "List spend anomalies the backend detected in a window: spikes against each project's or cluster's baseline, with the offending project/cluster, expected and actual spend, and the deviation magnitude in percent.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window":          map[string]interface{}{"type": "string", "enum": []string{"24h", "7d", "30d"}},
+				"minDeviationPct": map[string]interface{}{"type": "number", "minimum": 0},
+				"limit":           map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).EnzanAnomalies), costReporting())
+	r.register("enzan.events", toolDefinition{
+		Description: "List infrastructure events in a window in chronological order: node preemptions, GPU out-of-memory kills, and thermal or power throttling. Each event has its time, kind, cluster, node, and affected workload. Use it with enzan.anomalies to tell whether a cost spike lines up with an operational incident.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window":  map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+				"kind":    map[string]interface{}{"type": "string", "enum": []string{"preemption", "oom", "throttling"}},
+				"cluster": map[string]interface{}{"type": "string"},
+				"node":    map[string]interface{}{"type": "string"},
+				"limit":   map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).EnzanEvents))
+	r.register("enzan.breakdown", toolDefinition{
+		Description: "Break GPU spend down by one dimension (project, team, cluster, instanceType, or a label key) over a window. Unlike enzan.summary, results can be sorted and cut to the top N groups. dimension \"label\" requires labelKey.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dimension": map[string]interface{}{"type": "string", "enum": []string{"project", "team", "cluster", "instanceType", "label"}},
+				"labelKey":  map[string]interface{}{"type": "string"},
+				"window":    map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+				"sortBy":    map[string]interface{}{"type": "string", "enum": []string{"cost", "gpuHours"}},
+				"order":     map[string]interface{}{"type": "string", "enum": []string{"asc", "desc"}},
+				"top":       map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"required":             []string{"dimension"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanBreakdown), tabular(), zoned(), costReporting())
+	r.register("enzan.utilization", toolDefinition{
+		Description: "Get GPU utilization metrics for a window: SM occupancy, memory used, and idle percentage, per GPU or aggregated per node. Use it for capacity questions; filter by cluster or node.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window":  map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+				"groupBy": map[string]interface{}{"type": "string", "enum": []string{"gpu", "node"}},
+				"cluster": map[string]interface{}{"type": "string"},
+				"node":    map[string]interface{}{"type": "string"},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).EnzanUtilization))
+	r.register("enzan.recommend", toolDefinition{
+		Description: "List the backend's rightsizing recommendations: downsizing idle nodes, switching instance types, and spot eligibility. Each recommendation has an estimated monthly saving in USD. For window-based cost advice in prose, use enzan.optimize.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"kind":          map[string]interface{}{"type": "string", "enum": []string{"downsize", "instance_type", "spot"}},
+				"cluster":       map[string]interface{}{"type": "string"},
+				"minSavingsUSD": map[string]interface{}{"type": "number", "minimum": 0},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).EnzanRecommendations), costReporting())
+	r.register("enzan.export", toolDefinition{
+		Description: "Export a GPU spend report for a window as CSV. Small reports come back inline as CSV text, ready to paste into a spreadsheet. Large reports come back as a short-lived download link.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window":  map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}},
+				"groupBy": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanExport), withTextRenderer(renderEnzanExport))
+	r.register("enzan.compare", toolDefinition{
+		Description: "Compare GPU spend in the current period with the previous one (today vs yesterday, this week vs last week, this month vs last month). Returns totals for both periods and, per group, the previous and current spend, the delta in USD, and the delta in percent.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"period":  map[string]interface{}{"type": "string", "enum": []string{"day", "week", "month"}},
+				"groupBy": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callEnzanCompare), tabular(), costReporting())
+	r.register("enzan.idle", toolDefinition{
+		Description: "List GPUs that stayed below a utilization threshold (default 10%) for at least N hours (default 24). Each entry has its node, cluster, owner, idle hours, and cost accrued while idle. These are candidates for reclamation.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"thresholdPct": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 100},
+				"minHours":     map[string]interface{}{"type": "integer", "minimum": 1},
+				"cluster":      map[string]interface{}{"type": "string"},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).EnzanIdle), costReporting())
+	r.register("enzan.allocation", toolDefinition{
+		Description: "Allocate GPU spend for a window to the configured cost centers using their tag/label mapping rules (showback/chargeback). Returns spend per cost center and the spend that matched no rule.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"window":     map[string]interface{}{"type": "string", "enum": []string{"24h", "7d", "30d"}},
+				"costCenter": map[string]interface{}{"type": "string"},
+				"mode":       map[string]interface{}{"type": "string", "enum": []string{"showback", "chargeback"}},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).EnzanAllocation), costReporting())
+	r.register("sozo.generate", toolDefinition{
+		Description: "Generate synthetic tabular data from a schema or named preset. For large record counts, set stream to true. The records then arrive in batches with progress notifications, and the result text is JSON Lines split into chunks. Alternatively, set format (csv, jsonl, or parquet) to get a file. Add outputFile, a path relative to the server's output directory, to write the file to disk; the result links to the file instead of holding its rows. Parquet requires outputFile.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"records":      map[string]interface{}{"type": "number"},
+				"schemaName":   map[string]interface{}{"type": "string"},
+				"schema":       map[string]interface{}{"type": "object"},
+				"correlations": map[string]interface{}{"type": "object"},
+				"seed":         map[string]interface{}{"type": "number", "description": "Makes the run reproducible. Returned records then carry determinism.contentHash, checked against the backend's hash; verified: false flags nondeterminism"},
+				"runName":      map[string]interface{}{"type": "string", "description": "Name this seeded run in the seed catalog so sozo.seeds.replay can regenerate it"},
+				"stream":       map[string]interface{}{"type": "boolean"},
+				"format":       map[string]interface{}{"type": "string", "enum": sozoFileFormats},
+				"outputFile":   map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"records"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoGenerate), withTextRenderer(renderSozoGenerate), budgetGuarded(nil))
+	r.register("sozo.schemas", toolDefinition{
+		Description: "List Sozo schema presets. All filters are optional: name matches a substring of the preset name, tag matches a domain or category tag such as finance or healthcare, and minFields/maxFields bound the number of fields.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":      map[string]interface{}{"type": "string"},
+				"tag":       map[string]interface{}{"type": "string"},
+				"minFields": map[string]interface{}{"type": "integer", "minimum": 0},
+				"maxFields": map[string]interface{}{"type": "integer", "minimum": 0},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).SozoSchemas))
+	r.register("sozo.validate", toolDefinition{
+		Description: "Check a Sozo schema before generating from it. Returns structured diagnostics for problems such as unknown field types, impossible correlations, and invalid ranges. Each diagnostic has a severity, the field path, and a message. Nothing is generated.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"schema":       map[string]interface{}{"type": "object"},
+				"correlations": map[string]interface{}{"type": "object"},
+			},
+			"required":             []string{"schema"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoValidate))
+	r.register("sozo.preview", toolDefinition{
+		Description: "Generate a few sample records (5 by default, at most 10) from a schema or named preset so field shapes can be checked before a large sozo.generate run. Previews do not count against generation quota.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"schemaName":   map[string]interface{}{"type": "string"},
+				"schema":       map[string]interface{}{"type": "object"},
+				"correlations": map[string]interface{}{"type": "object"},
+				"seed":         map[string]interface{}{"type": "number"},
+				"records":      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": sozoPreviewMaxRecords},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoPreview), tabular())
+	r.register("sozo.anonymize", toolDefinition{
+		Description: "Turn a small set of real records (at most 500) into a synthetic, de-identified equivalent that keeps their shape and distributions, e.g. for sharing incident samples. rules maps field names to a masking strategy. Fields without a rule are synthesized.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"records": map[string]interface{}{
+					"type":     "array",
+					"items":    map[string]interface{}{"type": "object"},
+					"minItems": 1,
+					"maxItems": sozoSampleMaxRecords,
 				},
-				"required":             []string{"name", "type"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.update_alert",
-			Description: "Update one Enzan alert rule by id.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"id":        map[string]interface{}{"type": "string"},
-					"name":      map[string]interface{}{"type": "string"},
-					"threshold": map[string]interface{}{"type": "number"},
-					"window":    map[string]interface{}{"type": "string"},
-					"labels": map[string]interface{}{
-						"type":                 "object",
-						"additionalProperties": map[string]interface{}{"type": "string"},
+				"rules": map[string]interface{}{
+					"type": "object",
+					"additionalProperties": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"keep", "mask", "hash", "generalize", "synthesize", "drop"},
 					},
-					"enabled": map[string]interface{}{"type": "boolean"},
 				},
-				"required":             []string{"id"},
-				"additionalProperties": false,
+				"seed": map[string]interface{}{"type": "number"},
 			},
+			"required":             []string{"records"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.delete_alert",
-			Description: "Delete one Enzan alert rule by id.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"id": map[string]interface{}{"type": "string"},
-				},
-				"required":             []string{"id"},
-				"additionalProperties": false,
+	}, serverMethod((*Server).callSozoAnonymize))
+	r.register("sozo.schema.save", toolDefinition{
+		Description: "Save a schema to the backend as a named custom preset so it can be reused across sessions via schemaName. Saving an existing name fails unless overwrite is true.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":         map[string]interface{}{"type": "string", "pattern": presetNamePattern},
+				"schema":       map[string]interface{}{"type": "object"},
+				"correlations": map[string]interface{}{"type": "object"},
+				"description":  map[string]interface{}{"type": "string"},
+				"tags":         map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"overwrite":    map[string]interface{}{"type": "boolean"},
 			},
+			"required":             []string{"name", "schema"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.alert_events",
-			Description: "List recent Enzan alert events.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"limit": map[string]interface{}{"type": "number"},
-				},
-				"additionalProperties": false,
+	}, serverMethod((*Server).callSozoSchemaSave), mutating(), dryRunnable())
+	r.register("sozo.schema.delete", toolDefinition{
+		Description: "Delete a custom Sozo preset by name. Built-in presets cannot be deleted.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string", "pattern": presetNamePattern},
 			},
+			"required":             []string{"name"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.alert_deliveries",
-			Description: "List recent Enzan alert deliveries.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"limit": map[string]interface{}{"type": "number"},
-				},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.alert_endpoints",
-			Description: "List configured Enzan alert delivery webhook endpoints.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.create_alert_endpoint",
-			Description: "Create one Enzan alert delivery webhook endpoint.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"targetUrl":     map[string]interface{}{"type": "string"},
-					"signingSecret": map[string]interface{}{"type": "string"},
-				},
-				"required":             []string{"targetUrl"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.update_alert_endpoint",
-			Description: "Update one Enzan alert delivery webhook endpoint by id.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"id":            map[string]interface{}{"type": "string"},
-					"targetUrl":     map[string]interface{}{"type": "string"},
-					"signingSecret": map[string]interface{}{"type": "string"},
-					"enabled":       map[string]interface{}{"type": "boolean"},
-				},
-				"required":             []string{"id"},
-				"additionalProperties": false,
+	}, clientPathTool("name", (*kaizen.Client).SozoDeleteSchema), mutating(), dryRunnable())
+	r.register("sozo.jobs.start", toolDefinition{
+		Description: "Start an asynchronous Sozo generation for runs too large to finish within one tool call. It takes the same arguments as sozo.generate and returns a jobId. Poll sozo.jobs.status until the job is complete, then read the rows with sozo.jobs.result.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"records":      map[string]interface{}{"type": "number"},
+				"schemaName":   map[string]interface{}{"type": "string"},
+				"schema":       map[string]interface{}{"type": "object"},
+				"correlations": map[string]interface{}{"type": "object"},
+				"seed":         map[string]interface{}{"type": "number"},
+				"runName":      map[string]interface{}{"type": "string", "description": "Name this seeded run in the seed catalog so sozo.seeds.replay can regenerate it"},
 			},
+			"required":             []string{"records"},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "enzan.delete_alert_endpoint",
-			Description: "Delete one Enzan alert delivery webhook endpoint by id.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"id": map[string]interface{}{"type": "string"},
-				},
-				"required":             []string{"id"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.chat",
-			Description: "Ask a question about your GPU and API costs. Supports multi-turn conversations with optional time window.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"message":        map[string]interface{}{"type": "string", "description": "Your question about costs"},
-					"conversationId": map[string]interface{}{"type": "string", "description": "Optional conversation ID for follow-ups"},
-					"window":         map[string]interface{}{"type": "string", "enum": []string{"1h", "24h", "7d", "30d"}, "description": "Optional time window; inferred from message if omitted"},
+	}, serverMethod((*Server).callSozoJobsStart), budgetGuarded(nil), sharesCeilingWith("sozo.generate"))
+	r.register("sozo.jobs.status", toolDefinition{
+		Description: "Get the status of an asynchronous Sozo generation job: queued, running, completed, or failed. The response includes progress (records generated so far) and an error message for failed jobs.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"jobId": map[string]interface{}{"type": "string"},
+			},
+			"required":             []string{"jobId"},
+			"additionalProperties": false,
+		},
+	}, clientPathTool("jobId", (*kaizen.Client).SozoJob))
+	r.register("sozo.jobs.result", toolDefinition{
+		Description: "Read the records of a completed Sozo generation job, one page at a time. offset and limit select the page (the backend default is the first 1000 records). The response includes the total record count.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"jobId":  map[string]interface{}{"type": "string"},
+				"offset": map[string]interface{}{"type": "integer", "minimum": 0},
+				"limit":  map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"required":             []string{"jobId"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoJobResult))
+	r.register("sozo.augment", toolDefinition{
+		Description: "Expand a small set of seed records (at most 500) into `records` synthetic records. The output keeps the seeds' field types, value distributions, and correlations. Useful for bootstrapping test fixtures from a few real examples.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"seedRecords": map[string]interface{}{
+					"type":     "array",
+					"items":    map[string]interface{}{"type": "object"},
+					"minItems": 1,
+					"maxItems": sozoSampleMaxRecords,
 				},
-				"required":             []string{"message"},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "enzan.burn",
-			Description: "Get current burn rate in USD/hour.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
-			},
-		},
-		{
-			Name:        "sozo.generate",
-			Description: "Generate synthetic tabular data from a schema or named preset.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"records":      map[string]interface{}{"type": "number"},
-					"schemaName":   map[string]interface{}{"type": "string"},
-					"schema":       map[string]interface{}{"type": "object"},
-					"correlations": map[string]interface{}{"type": "object"},
-					"seed":         map[string]interface{}{"type": "number"},
+				"records": map[string]interface{}{"type": "integer", "minimum": 1},
+				"seed":    map[string]interface{}{"type": "number"},
+			},
+			"required":             []string{"seedRecords", "records"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoAugment))
+	r.register("sozo.drift", toolDefinition{
+		Description: "Generate a dataset from a schema or preset with a controlled distribution shift, for testing drift monitoring. shiftPct sets the shift magnitude relative to the base distributions. fields limits the shift to the named fields (by default the backend picks). kind chooses mean shift, variance change, or category mix change. The response includes the records and a report of the shift applied per field.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"schemaName": map[string]interface{}{"type": "string"},
+				"schema":     map[string]interface{}{"type": "object"},
+				"records":    map[string]interface{}{"type": "integer", "minimum": 1},
+				"shiftPct":   map[string]interface{}{"type": "number", "exclusiveMinimum": 0, "maximum": 100},
+				"fields":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"kind":       map[string]interface{}{"type": "string", "enum": []string{"mean_shift", "variance", "category_mix"}},
+				"seed":       map[string]interface{}{"type": "number"},
+			},
+			"required":             []string{"records", "shiftPct"},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoDrift))
+	r.register("sozo.correlations", toolDefinition{
+		Description: "Check how faithfully a generated dataset reproduces the correlations it was asked for. Pass jobId for a dataset from sozo.jobs.start, or records inline together with the requested correlations. Returns the realized correlation matrix, the requested value for each constrained field pair, and the difference between them.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"jobId": map[string]interface{}{"type": "string"},
+				"records": map[string]interface{}{
+					"type":     "array",
+					"items":    map[string]interface{}{"type": "object"},
+					"minItems": 2,
+					"maxItems": sozoCorrelationMaxRecords,
 				},
-				"required":             []string{"records"},
-				"additionalProperties": false,
+				"correlations": map[string]interface{}{"type": "object", "description": "Requested correlations, in the same form as sozo.generate; required with records"},
+				"fields":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Limit the matrix to these fields"},
+			},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callSozoCorrelations))
+	r.register("sozo.seeds", toolDefinition{
+		Description: "List the seed catalog: past seeded Sozo runs, newest first, each with its run name, seed, schema or preset, record count, and creation time. Filter by schemaName or a substring of the run name, and pass the returned nextCursor back as cursor to page. Use sozo.seeds.replay to regenerate a run exactly.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":       map[string]interface{}{"type": "string"},
+				"schemaName": map[string]interface{}{"type": "string"},
+				"limit":      map[string]interface{}{"type": "integer", "minimum": 1},
+				"cursor":     map[string]interface{}{"type": "string"},
+			},
+			"additionalProperties": false,
+		},
+	}, clientQueryTool((*kaizen.Client).SozoSeeds))
+	r.register("sozo.seeds.replay", toolDefinition{
+		Description: "Regenerate a seeded run from the seed catalog by its run name. The backend reuses the stored schema, correlations, seed, and record count, so the records are identical to the original run, e.g. to rebuild QA fixtures months later.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string", "description": "Run name from sozo.seeds"},
 			},
+			"required":             []string{"name"},
+			"additionalProperties": false,
+		},
+	}, clientPathTool("name", (*kaizen.Client).SozoReplaySeed))
+	r.register("kaizen.status", toolDefinition{
+		Description: "Check whether the Kaizen backend is usable from this server. Reports reachability and latency, health of the akuma/enzan/sozo components, the identity the configured credentials resolve to, and this server's version. It also warns when the backend's API version is newer than this server supports. Call this before telling the user something is broken. Backend failures come back with a diagnosis, not as a tool error.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callKaizenStatus), offline())
+	r.register("mcp.stats", toolDefinition{
+		Description: "Report this MCP server's own statistics since it started: uptime, per-tool call and error counts with average latency, and hit rates of its caches (result pages, readiness checks, currency rates). Use it to inspect server health in-band; it never calls the backend.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, serverMethod((*Server).callMCPStats), offline())
+	r.register("kaizen.whoami", toolDefinition{
+		Description: "Show the identity bound to the configured credentials: the user or service account, organization and tenant, and granted scopes. Use it to confirm which tenant calls are operating against.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+	}, clientTool((*kaizen.Client).WhoAmI))
+	r.register("kaizen.usage", toolDefinition{
+		Description: "Show API usage and remaining quota for the configured key: calls made today per product, rate-limit ceilings, generation quota, and when each window resets. Check it before a long multi-call task to avoid running out of quota partway through.",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
 		},
-		{
-			Name:        "sozo.schemas",
-			Description: "List built-in Sozo schema presets.",
-			InputSchema: map[string]interface{}{
-				"type":                 "object",
-				"properties":           map[string]interface{}{},
-				"additionalProperties": false,
+	}, clientTool((*kaizen.Client).Usage))
+	r.register(resultPageTool, toolDefinition{
+		Description: "Fetch another page of a tool result that was too large to return inline. Truncated results end with a note giving the resultId and the next page number. Results are kept for 30 minutes.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"resultId": map[string]interface{}{"type": "string"},
+				"page":     map[string]interface{}{"type": "integer", "minimum": 1, "description": "1-based page number; defaults to 2"},
 			},
+			"required":             []string{"resultId"},
+			"additionalProperties": false,
 		},
-	}
+	}, serverMethod((*Server).callKaizenResultPage), withTextRenderer(renderResultPage), offline())
 }
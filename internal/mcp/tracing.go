@@ -0,0 +1,376 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Spans are exported as OTLP/HTTP JSON, which needs no SDK: the server
+// batches finished spans and POSTs them to the collector's /v1/traces.
+const (
+	traceparentHeader  = "traceparent"
+	traceExportBatch   = 512
+	traceExportEvery   = 5 * time.Second
+	traceExportTimeout = 10 * time.Second
+)
+
+// OTLP span kinds and status codes.
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+	spanKindClient   = 3
+
+	spanStatusError = 2
+)
+
+// tracer records spans for JSON-RPC messages, tool calls, and Kaizen API
+// requests. A nil tracer (no OTLP endpoint configured) records nothing.
+type tracer struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	httpClient  *http.Client
+	onError     func(error)
+
+	mu      sync.Mutex
+	pending []*span
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	stopped sync.Once
+	done    chan struct{}
+}
+
+// newTracerFromEnv configures tracing from the standard OpenTelemetry
+// variables. Tracing stays off unless an OTLP endpoint is set.
+func newTracerFromEnv(onError func(error)) (*tracer, error) {
+	endpoint, headers, err := otlpEndpointFromEnv("traces")
+	if endpoint == "" || err != nil {
+		return nil, err
+	}
+	t := newTracer(endpoint, headers, getEnv("OTEL_SERVICE_NAME", serverName), onError)
+	go t.run()
+	return t, nil
+}
+
+// otlpEndpointFromEnv resolves the OTLP/HTTP endpoint for a signal
+// (traces or logs) and the export headers, or an empty endpoint when none
+// is configured.
+func otlpEndpointFromEnv(signal string) (string, map[string]string, error) {
+	endpoint := getEnv("OTEL_EXPORTER_OTLP_"+strings.ToUpper(signal)+"_ENDPOINT", "")
+	if endpoint == "" {
+		if base := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""); base != "" {
+			endpoint = strings.TrimRight(base, "/") + "/v1/" + signal
+		}
+	}
+	if endpoint == "" {
+		return "", nil, nil
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return "", nil, fmt.Errorf("invalid OTLP endpoint %q: expected an http(s) URL", endpoint)
+	}
+	headers := map[string]string{}
+	if raw := getEnv("OTEL_EXPORTER_OTLP_HEADERS", ""); raw != "" {
+		for _, pair := range strings.Split(raw, ",") {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return "", nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_HEADERS: expected key=value pairs")
+			}
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return endpoint, headers, nil
+}
+
+func newTracer(endpoint string, headers map[string]string, serviceName string, onError func(error)) *tracer {
+	return &tracer{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		httpClient:  &http.Client{Timeout: traceExportTimeout},
+		onError:     onError,
+		flushCh:     make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// span is one timed operation. Its methods are no-ops on a nil span, so
+// call sites need not check whether tracing is on.
+type span struct {
+	tracer     *tracer
+	traceID    string
+	spanID     string
+	parentID   string
+	name       string
+	kind       int
+	start      time.Time
+	end        time.Time
+	attributes map[string]interface{}
+	errMessage string
+	failed     bool
+}
+
+type spanContextKey struct{}
+
+// remoteParent is a span context received in an HTTP caller's
+// traceparent header.
+type remoteParent struct {
+	traceID, spanID string
+}
+
+// start begins a span as a child of the span in ctx, if any.
+func (t *tracer) start(ctx context.Context, name string, kind int) (context.Context, *span) {
+	if t == nil {
+		return ctx, nil
+	}
+	sp := &span{tracer: t, spanID: randomHex(8), name: name, kind: kind, start: time.Now(), attributes: map[string]interface{}{}}
+	switch parent := ctx.Value(spanContextKey{}).(type) {
+	case *span:
+		sp.traceID, sp.parentID = parent.traceID, parent.spanID
+	case remoteParent:
+		sp.traceID, sp.parentID = parent.traceID, parent.spanID
+	default:
+		sp.traceID = randomHex(16)
+	}
+	return context.WithValue(ctx, spanContextKey{}, sp), sp
+}
+
+// withTraceparent continues the trace of an HTTP caller that sent a W3C
+// traceparent header; malformed headers are ignored.
+func withTraceparent(ctx context.Context, header string) context.Context {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx
+	}
+	if _, err := hex.DecodeString(parts[1] + parts[2]); err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, remoteParent{traceID: parts[1], spanID: parts[2]})
+}
+
+func (sp *span) set(key string, value interface{}) {
+	if sp != nil {
+		sp.attributes[key] = value
+	}
+}
+
+func (sp *span) fail(message string) {
+	if sp != nil {
+		sp.failed, sp.errMessage = true, message
+	}
+}
+
+func (sp *span) finish() {
+	if sp == nil {
+		return
+	}
+	sp.end = time.Now()
+	sp.tracer.enqueue(sp)
+}
+
+func (sp *span) traceparent() string {
+	return "00-" + sp.traceID + "-" + sp.spanID + "-01"
+}
+
+func (t *tracer) enqueue(sp *span) {
+	t.mu.Lock()
+	t.pending = append(t.pending, sp)
+	full := len(t.pending) >= traceExportBatch
+	t.mu.Unlock()
+	if full {
+		select {
+		case t.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *tracer) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(traceExportEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-t.flushCh:
+		case <-t.stopCh:
+			t.flush()
+			return
+		}
+		t.flush()
+	}
+}
+
+// shutdown exports the spans still pending and stops the exporter.
+func (t *tracer) shutdown() {
+	if t == nil {
+		return
+	}
+	t.stopped.Do(func() {
+		close(t.stopCh)
+		<-t.done
+	})
+}
+
+// flush exports every pending span. Spans that fail to export are
+// dropped: tracing must never hold up tool calls.
+func (t *tracer) flush() {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+	if err := t.export(batch); err != nil && t.onError != nil {
+		t.onError(err)
+	}
+}
+
+func (t *tracer) export(batch []*span) error {
+	spans := make([]map[string]interface{}, len(batch))
+	for i, sp := range batch {
+		spans[i] = sp.otlp()
+	}
+	return postOTLP(t.httpClient, t.endpoint, t.headers, "spans", map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": otlpResource(t.serviceName),
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": otlpScope(),
+				"spans": spans,
+			}},
+		}},
+	})
+}
+
+func otlpResource(serviceName string) map[string]interface{} {
+	return map[string]interface{}{
+		"attributes": otlpAttributes(map[string]interface{}{"service.name": serviceName, "service.version": serverVersion}),
+	}
+}
+
+func otlpScope() map[string]interface{} {
+	return map[string]interface{}{"name": serverName, "version": serverVersion}
+}
+
+// postOTLP sends one OTLP/HTTP JSON export request; what names the
+// exported items in errors.
+func postOTLP(client *http.Client, endpoint string, headers map[string]string, what string, payload map[string]interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", what, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", what, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to export %s: collector returned status %d", what, resp.StatusCode)
+	}
+	return nil
+}
+
+func (sp *span) otlp() map[string]interface{} {
+	encoded := map[string]interface{}{
+		"traceId":           sp.traceID,
+		"spanId":            sp.spanID,
+		"name":              sp.name,
+		"kind":              sp.kind,
+		"startTimeUnixNano": strconv.FormatInt(sp.start.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(sp.end.UnixNano(), 10),
+		"attributes":        otlpAttributes(sp.attributes),
+	}
+	if sp.parentID != "" {
+		encoded["parentSpanId"] = sp.parentID
+	}
+	if sp.failed {
+		encoded["status"] = map[string]interface{}{"code": spanStatusError, "message": sp.errMessage}
+	}
+	return encoded
+}
+
+func otlpAttributes(attributes map[string]interface{}) []map[string]interface{} {
+	encoded := make([]map[string]interface{}, 0, len(attributes))
+	for key, value := range attributes {
+		var typed map[string]interface{}
+		switch v := value.(type) {
+		case int:
+			// OTLP JSON carries 64-bit integers as strings.
+			typed = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case bool:
+			typed = map[string]interface{}{"boolValue": v}
+		default:
+			typed = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		encoded = append(encoded, map[string]interface{}{"key": key, "value": typed})
+	}
+	return encoded
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// traceToolCall records a span around every tool call, marked failed when
+// the result is a tool error.
+func (s *Server) traceToolCall(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		ctx, sp := s.tracer.start(ctx, "tools/call "+call.Name, spanKindInternal)
+		defer sp.finish()
+		sp.set("mcp.tool.name", call.Name)
+		sp.set("mcp.request_id", requestIDFrom(ctx))
+		result := next(ctx, call)
+		if result["isError"] == true {
+			content, _ := result["content"].([]map[string]string)
+			message := "tool error"
+			if len(content) > 0 {
+				message = content[0]["text"]
+			}
+			sp.fail(message)
+		}
+		return result
+	}
+}
+
+// traceRequests is the client middleware that records a span for every
+// Kaizen API request and propagates the trace to the backend.
+func (t *tracer) traceRequests(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		_, sp := t.start(req.Context(), req.Method+" "+req.URL.Path, spanKindClient)
+		defer sp.finish()
+		sp.set("http.request.method", req.Method)
+		sp.set("url.path", req.URL.Path)
+		sp.set("server.address", req.URL.Host)
+		req.Header.Set(traceparentHeader, sp.traceparent())
+		resp, err := next(req)
+		if err != nil {
+			sp.fail(err.Error())
+			return nil, err
+		}
+		sp.set("http.response.status_code", resp.StatusCode)
+		if resp.StatusCode >= 500 {
+			sp.fail(http.StatusText(resp.StatusCode))
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTracingSpansToolCallsAndBackendRequests(t *testing.T) {
+	var exports []map[string]interface{}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("X-Honeycomb-Team") != "secret" {
+			t.Errorf("unexpected export %s with headers %v", r.URL.Path, r.Header)
+		}
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		exports = append(exports, body)
+	}))
+	defer collector.Close()
+
+	var traceparent string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get(traceparentHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backend.Close()
+
+	tr := newTracer(collector.URL+"/v1/traces", map[string]string{"X-Honeycomb-Team": "secret"}, "kaizen-mcp-test", nil)
+	s := &Server{tracer: tr, client: &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client()}}
+	s.UseClientMiddleware(tr.traceRequests)
+
+	ctx := withTraceparent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	s.handleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"enzan.burn","arguments":{}}}`))
+	tr.flush()
+
+	if len(exports) != 1 {
+		t.Fatalf("exports = %d, want one batch", len(exports))
+	}
+	resource := exports[0]["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	spans := resource["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	byName := map[string]map[string]interface{}{}
+	for _, raw := range spans {
+		sp := raw.(map[string]interface{})
+		byName[sp["name"].(string)] = sp
+		if sp["traceId"] != "0af7651916cd43dd8448eb211c80319c" {
+			t.Fatalf("%s did not continue the caller's trace: %v", sp["name"], sp["traceId"])
+		}
+	}
+	message, tool, request := byName["tools/call"], byName["tools/call enzan.burn"], byName["GET /v1/enzan/burn"]
+	if message == nil || tool == nil || request == nil {
+		t.Fatalf("missing spans, got %v", byName)
+	}
+	if message["parentSpanId"] != "b7ad6b7169203331" || tool["parentSpanId"] != message["spanId"] || request["parentSpanId"] != tool["spanId"] {
+		t.Fatalf("spans are not nested message > tool > request: %v", spans)
+	}
+	if !strings.Contains(traceparent, request["spanId"].(string)) {
+		t.Fatalf("backend traceparent %q does not name the request span", traceparent)
+	}
+	if !strings.Contains(mustJSON(t, request["attributes"]), `{"key":"http.response.status_code","value":{"intValue":"200"}}`) {
+		t.Fatalf("request span lacks its status: %v", request["attributes"])
+	}
+}
+
+func TestTracingMarksToolErrors(t *testing.T) {
+	tr := newTracer("http://127.0.0.1:0", nil, "kaizen-mcp-test", nil)
+	s := &Server{tracer: tr}
+	raw, _ := json.Marshal(toolsCallParams{Name: "akuma.format", Arguments: map[string]interface{}{"sql": "select 'unterminated"}})
+	s.handleToolCall(context.Background(), raw)
+	if len(tr.pending) != 1 || !tr.pending[0].failed {
+		t.Fatalf("tool error should fail the span, got %+v", tr.pending)
+	}
+}
+
+func TestTracingDisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	if tr, err := newTracerFromEnv(nil); tr != nil || err != nil {
+		t.Fatalf("tracing should be off without an endpoint, got %v, %v", tr, err)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "collector:4318")
+	if _, err := newTracerFromEnv(nil); err == nil {
+		t.Fatalf("expected an error for an endpoint without a scheme")
+	}
+}
+
+func mustJSON(t *testing.T, value interface{}) string {
+	t.Helper()
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(encoded)
+}
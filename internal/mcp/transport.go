@@ -79,8 +79,9 @@ func parseContentLength(headers []string) (int, error) {
 	return 0, fmt.Errorf("missing Content-Length header")
 }
 
-func writeMessage(writer *bufio.Writer, response jsonRPCResponse) error {
-	payload, err := json.Marshal(response)
+// writeMessage frames one JSON-RPC response or notification.
+func writeMessage(writer *bufio.Writer, message interface{}) error {
+	payload, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
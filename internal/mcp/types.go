@@ -16,6 +16,12 @@ type jsonRPCResponse struct {
 	Error   *jsonRPCError `json:"error,omitempty"`
 }
 
+type jsonRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 type jsonRPCError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
@@ -26,9 +32,29 @@ type toolDefinition struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+	Meta        *toolMeta              `json:"_meta,omitempty"`
+}
+
+// toolMeta is optional tool metadata for clients that read _meta.
+type toolMeta struct {
+	Deprecated bool `json:"deprecated,omitempty"`
+	// ReplacedBy names the tool to use instead of a deprecated one.
+	ReplacedBy string `json:"replacedBy,omitempty"`
+	// Examples are sample invocations for few-shot prompting.
+	Examples []toolExample `json:"examples,omitempty"`
+	// ConfigurationNeeded flags tools that cannot run until the server
+	// is given Kaizen credentials.
+	ConfigurationNeeded bool `json:"configurationNeeded,omitempty"`
 }
 
 type toolsCallParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Meta      *toolsCallMeta         `json:"_meta,omitempty"`
+}
+
+type toolsCallMeta struct {
+	// ProgressToken, when set, asks for notifications/progress while the
+	// call runs. It is a string or number chosen by the client.
+	ProgressToken interface{} `json:"progressToken,omitempty"`
 }
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// workspaceHeader names the Kaizen workspace (tenant) a request acts in,
+// so one deployment can serve several orgs.
+const workspaceHeader = "X-Kaizen-Workspace"
+
+type workspaceContextKey struct{}
+
+// withWorkspace overrides the configured workspace for the rest of one
+// HTTP session request or tool call.
+func withWorkspace(ctx context.Context, workspace string) context.Context {
+	return context.WithValue(ctx, workspaceContextKey{}, workspace)
+}
+
+// workspace resolves the workspace for a request: a per-call `workspace`
+// argument, then the HTTP caller's X-Kaizen-Workspace header, then the
+// client's configured workspace (KAIZEN_WORKSPACE or the profile's).
+func (c *kaizenAPIClient) workspaceFor(ctx context.Context) string {
+	if workspace, ok := ctx.Value(workspaceContextKey{}).(string); ok && workspace != "" {
+		return workspace
+	}
+	return c.workspace
+}
+
+func validateWorkspace(source, workspace string) error {
+	if strings.ContainsAny(workspace, "\r\n") {
+		return fmt.Errorf("invalid %s: workspace must be a single line", source)
+	}
+	return nil
+}
+
+// routeWorkspace applies and removes the optional `workspace` argument.
+func (s *Server) routeWorkspace(next ToolFunc) ToolFunc {
+	return func(ctx context.Context, call ToolCall) map[string]interface{} {
+		raw, args := takeArgument(call.Arguments, "workspace")
+		if raw == nil {
+			return next(ctx, call)
+		}
+		workspace, ok := raw.(string)
+		if !ok || strings.TrimSpace(workspace) == "" {
+			return toolErrorResult("workspace must be a non-empty string")
+		}
+		if err := validateWorkspace("workspace", workspace); err != nil {
+			return toolErrorResult(err.Error())
+		}
+		call.Arguments = args
+		return next(withWorkspace(ctx, workspace), call)
+	}
+}
+
+// usesWorkspaces reports whether any backend client has a workspace
+// configured; only then do tools advertise the `workspace` argument.
+func (s *Server) usesWorkspaces() bool {
+	if s.client != nil && s.client.workspace != "" {
+		return true
+	}
+	for _, client := range s.profiles {
+		if client.workspace != "" {
+			return true
+		}
+	}
+	return false
+}
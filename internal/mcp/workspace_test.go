@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceHeaderPrecedence(t *testing.T) {
+	var workspaces []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspaces = append(workspaces, r.Header.Get(workspaceHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backend.Close()
+	s := &Server{client: &kaizenAPIClient{baseURL: backend.URL, apiKey: "k", httpClient: backend.Client(), workspace: "acme"}}
+
+	call := func(ctx context.Context, args map[string]interface{}) {
+		t.Helper()
+		raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: args})
+		if result, _ := s.handleToolCall(ctx, raw); result.(map[string]interface{})["isError"] == true {
+			t.Fatalf("unexpected tool error: %#v", result)
+		}
+	}
+	session := withWorkspace(context.Background(), "globex")
+	call(context.Background(), map[string]interface{}{})
+	call(session, map[string]interface{}{})
+	call(session, map[string]interface{}{"workspace": "initech"})
+	if got := strings.Join(workspaces, ","); got != "acme,globex,initech" {
+		t.Fatalf("workspaces = %s, want configured, then session, then per-call", got)
+	}
+
+	raw, _ := json.Marshal(toolsCallParams{Name: "enzan.burn", Arguments: map[string]interface{}{"workspace": ""}})
+	if result, _ := s.handleToolCall(context.Background(), raw); result.(map[string]interface{})["isError"] != true || len(workspaces) != 3 {
+		t.Fatalf("an empty workspace should be rejected locally")
+	}
+}
+
+func TestWorkspaceArgumentAdvertisedOnlyWhenConfigured(t *testing.T) {
+	s := &Server{client: &kaizenAPIClient{}}
+	hasWorkspace := func() bool {
+		for _, def := range s.listTools() {
+			properties, _ := def.InputSchema["properties"].(map[string]interface{})
+			if _, ok := properties["workspace"]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	if hasWorkspace() {
+		t.Fatalf("workspace advertised without a configured workspace")
+	}
+	s.profiles = map[string]*kaizenAPIClient{"prod": {workspace: "acme"}}
+	if !hasWorkspace() {
+		t.Fatalf("workspace not advertised although a profile sets one")
+	}
+	if _, ok := builtinTools.tools["enzan.burn"].def.InputSchema["properties"].(map[string]interface{})["workspace"]; ok {
+		t.Fatalf("workspace leaked into the shared registry")
+	}
+}
@@ -1,17 +1,76 @@
-// Package main provides the Kaizen MCP server over stdio.
+// Package main provides the Kaizen MCP server over stdio or HTTP.
 package main
 
 import (
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/kaizen-ai-systems/mcp-server/internal/mcp"
 )
 
 func main() {
-	server := mcp.NewServer()
+	readOnlyDefault := false
+	if value := os.Getenv("KAIZEN_MCP_READ_ONLY"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kaizen-mcp: KAIZEN_MCP_READ_ONLY: invalid boolean %q\n", value)
+			os.Exit(1)
+		}
+		readOnlyDefault = parsed
+	}
+	httpAddr := flag.String("http", os.Getenv("KAIZEN_MCP_HTTP_ADDR"), "serve MCP over HTTP on this address (e.g. 127.0.0.1:8787) instead of stdio")
+	recordDir := flag.String("record", os.Getenv("KAIZEN_MCP_RECORD_DIR"), "write every backend request/response pair (secrets redacted) to this directory")
+	auditLog := flag.String("audit-log", os.Getenv("KAIZEN_MCP_AUDIT_LOG"), "append one JSON line per tool call (arguments digested, secrets redacted) to this file")
+	debugFrames := flag.String("debug-frames", os.Getenv("KAIZEN_MCP_DEBUG_FRAMES"), "log every inbound and outbound JSON-RPC frame (pretty-printed, secrets redacted) to this file")
+	pprofAddr := flag.String("pprof", os.Getenv("KAIZEN_MCP_PPROF_ADDR"), "serve net/http/pprof on this loopback address (e.g. localhost:6060)")
+	readOnly := flag.Bool("read-only", readOnlyDefault, "hide and refuse tools that change backend state")
+	flag.Parse()
+
+	server, err := mcp.NewServer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kaizen-mcp: %v\n", err)
+		os.Exit(1)
+	}
+	if *recordDir != "" {
+		if err := server.RecordTranscripts(*recordDir); err != nil {
+			fmt.Fprintf(os.Stderr, "kaizen-mcp: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *auditLog != "" {
+		if err := server.AuditLog(*auditLog); err != nil {
+			fmt.Fprintf(os.Stderr, "kaizen-mcp: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *debugFrames != "" {
+		if err := server.DebugFrames(*debugFrames); err != nil {
+			fmt.Fprintf(os.Stderr, "kaizen-mcp: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *pprofAddr != "" {
+		if err := server.ServePprof(*pprofAddr); err != nil {
+			fmt.Fprintf(os.Stderr, "kaizen-mcp: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	server.SetReadOnly(*readOnly)
 	server.LogStartup()
-	if err := server.Serve(); err != nil {
+	server.RunStartupProbe()
+
+	if *httpAddr != "" {
+		err = server.ListenAndServe(*httpAddr)
+	} else {
+		err = server.Serve()
+	}
+	if err != nil {
 		server.LogFatal(err)
+	}
+	server.Shutdown()
+	if err != nil {
 		os.Exit(1)
 	}
 }